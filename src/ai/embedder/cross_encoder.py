@@ -0,0 +1,73 @@
+import os
+import logging
+import threading
+from typing import Dict, List
+from sentence_transformers import CrossEncoder
+from dotenv import load_dotenv
+
+# Load environment variables from .env file
+load_dotenv()
+
+logger = logging.getLogger(__name__)
+
+
+class CrossEncoderReranker:
+    # cross-encoder models are cached the same way as embedding models in SentenceEncoder
+    _cache_limit: int = int(os.getenv('RERANK_MODEL_CACHE_LIMIT', 1))
+    _local_model_dir: str = os.path.abspath(os.getenv('LOCAL_MODEL_PATH', 'models'))
+
+    # Thread lock for thread-safe access to the cache
+    _lock: threading.Lock = threading.Lock()
+
+    # Dictionary to store cached model instances
+    _model_cache: Dict[str, CrossEncoder] = {}
+
+    @classmethod
+    def _load_model(cls, model_name: str) -> CrossEncoder:
+        """
+        Loads a cross-encoder model from the local directory if available, otherwise downloads and saves it.
+        """
+        model_path: str = os.path.join(cls._local_model_dir, model_name)
+
+        if not os.path.exists(model_path) or not os.listdir(model_path):
+            logger.info(f"{model_name} cross-encoder not found locally, downloading from Hugging Face...")
+            model: CrossEncoder = CrossEncoder(model_name)
+            model.save(model_path)
+            logger.info(f"{model_name} cross-encoder saved locally at {model_path}")
+            return model
+        logger.info(f"loading {model_name} cross-encoder from local directory...")
+        return CrossEncoder(model_path)
+
+    @classmethod
+    def _get_model(cls, model_name: str) -> CrossEncoder:
+        with cls._lock:
+            if model_name in cls._model_cache:
+                return cls._model_cache[model_name]
+
+            # If the cache limit is reached, unload the oldest model
+            if len(cls._model_cache) >= cls._cache_limit:
+                oldest_model: str = next(iter(cls._model_cache))
+                logger.info(f"unloading cross-encoder: {oldest_model}")
+                del cls._model_cache[oldest_model]
+
+            model: CrossEncoder = cls._load_model(model_name)
+            cls._model_cache[model_name] = model
+            return model
+
+    @classmethod
+    def score(cls, query: str, contents: List[str], model_name: str) -> List[float]:
+        """
+        Scores the relevance of each content to the query.
+
+        Parameters:
+        query (str): The search query.
+        contents (list): The contents to be scored.
+        model_name (str): The name of the cross-encoder model.
+
+        Returns:
+        list: A list of scores in the order of contents.
+        """
+        if not contents:
+            return []
+        model: CrossEncoder = cls._get_model(model_name)
+        return [float(score) for score in model.predict([(query, content) for content in contents])]
@@ -2,8 +2,10 @@ import time
 import os
 
 from cognix_lib.gen_types.embed_service_pb2_grpc import EmbedServiceServicer, add_EmbedServiceServicer_to_server
-from cognix_lib.gen_types.embed_service_pb2 import EmbedResponse, EmbedResponseItem
+from cognix_lib.gen_types.embed_service_pb2 import EmbedResponse, EmbedResponseItem, RerankResponse, \
+    RerankResponseItem
 from sentence_encoder import SentenceEncoder
+from cross_encoder import CrossEncoderReranker
 from cognix_lib.helpers.device_checker import DeviceChecker
 import grpc
 from concurrent import futures
@@ -56,6 +58,28 @@ class EmbedServicer(EmbedServiceServicer):
             elapsed_time = end_time - start_time
             logger.info(f"⏰ total elapsed time: {elapsed_time:.2f} seconds to embedd  {len(request.contents)} entities")
 
+    def Rerank(self, request, context):
+        start_time = time.time()  # Record the start time
+        try:
+            logger.info(f"📱incoming rerank request: for {len(request.contents)} entities")
+            scores = CrossEncoderReranker.score(query=request.query, contents=list(request.contents),
+                                                model_name=request.model)
+
+            rerank_response = RerankResponse()
+            # results are ordered by relevance, index refers to the position of the content in the request
+            for index, score in sorted(enumerate(scores), key=lambda item: item[1], reverse=True):
+                rerank_response.results.append(RerankResponseItem(index=index, score=score))
+
+            logger.info("rerank request successfully processed")
+            return rerank_response
+        except Exception as e:
+            logger.exception(e)
+            raise grpc.RpcError(f"❌ failed to process request: {str(e)}")
+        finally:
+            end_time = time.time()  # Record the end time
+            elapsed_time = end_time - start_time
+            logger.info(f"⏰ total elapsed time: {elapsed_time:.2f} seconds to rerank {len(request.contents)} entities")
+
 
 def serve():
     server = grpc.server(futures.ThreadPoolExecutor(),
@@ -1,7 +1,7 @@
 import os
 import logging
 from dotenv import load_dotenv
-from sqlalchemy import Column, BigInteger, TIMESTAMP, Boolean, func, Text, Integer
+from sqlalchemy import Column, BigInteger, TIMESTAMP, Boolean, func, Text, Integer, String
 from sqlalchemy.ext.declarative import declarative_base
 from sqlalchemy.dialects.postgresql import UUID
 from cognix_lib.db.dc_connection_manager import ConnectionManager
@@ -38,6 +38,21 @@ class Document(Base):
                 f"creation_date={self.creation_date}, last_update={self.last_update})>")
 
 
+class DocumentChunk(Base):
+    # chunks used by the full-text search, content_tsv column is generated by the database
+    __tablename__ = 'document_chunks'
+
+    id = Column(Integer, primary_key=True, autoincrement=True)
+    document_id = Column(BigInteger, nullable=False)
+    collection_name = Column(String, nullable=False)
+    content = Column(Text, nullable=False)
+    creation_date = Column(TIMESTAMP(timezone=False), nullable=False, default=func.now())
+
+    def __repr__(self):
+        return (f"<DocumentChunk(id={self.id}, document_id={self.document_id}, "
+                f"collection_name={self.collection_name})>")
+
+
 def with_retry(func):
     def wrapper(*args, **kwargs):
         retries = 3
@@ -170,3 +185,19 @@ class DocumentCRUD:
             deleted_docs = session.query(Document).filter_by(parent_id=parent_id).delete()
             session.commit()
             return deleted_docs
+
+    @with_retry
+    def replace_document_chunks(self, document_id: int, collection_name: str, contents: List[str]) -> None:
+        """
+        Replaces chunks of the document used by the full-text search.
+        :param document_id: id of the document
+        :param collection_name: name of the collection where vectors of the document are stored
+        :param contents: content of chunks
+        """
+        if document_id <= 0:
+            raise ValueError("ID value must be positive")
+        with self.session_scope() as session:
+            session.query(DocumentChunk).filter_by(document_id=document_id).delete()
+            session.add_all([DocumentChunk(document_id=document_id, collection_name=collection_name, content=content)
+                             for content in contents if content])
+            session.commit()
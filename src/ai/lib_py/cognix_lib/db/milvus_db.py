@@ -106,7 +106,8 @@ class Milvus_DB:
                 data=[embedding],  # Embed search value
                 anns_field="vector",  # Search across embeddings
                 param={"metric_type": f"{milvus_metric_type}", "params": {"ef": 64}},
-                limit=10,  # Limit to top_k results per search
+                limit=data.top_k if data.top_k > 0 else 10,  # Limit to top_k results per search
+                expr=self.filter_expression(data),
                 output_fields=["id", "content", "document_id", "parent_id"]
             )
 
@@ -145,8 +146,24 @@ class Milvus_DB:
             elapsed_time = end_time - start_time
             self.logger.debug(f"⏰🤖 milvus query total elapsed time: {elapsed_time:.2f} seconds")
 
+    @staticmethod
+    def filter_expression(data: SearchRequest) -> str | None:
+        # every filter matches the field with any of its values, filters are combined with and
+        expressions = [f"{search_filter.field} in [{', '.join(map(str, search_filter.values))}]"
+                       for search_filter in data.filters if search_filter.field]
+        return " and ".join(expressions) if expressions else None
+
+    @staticmethod
+    def is_relevant(distance: float, score_threshold: float) -> bool:
+        # for L2 metric smaller distance means more similar vectors
+        if score_threshold <= 0:
+            return True
+        if milvus_metric_type == "L2":
+            return distance <= score_threshold
+        return distance >= score_threshold
+
     def store_chunk_list(self, chunk_list: List[ChunkedItem], collection_name: str, model_name: str,
-                         model_dimension: int):
+                         model_dimension: int, connector_id: int = 0):
         self.logger.info(f"🗄️storing {len(chunk_list)} entities in the vector db")
         entities = []
 
@@ -162,6 +179,9 @@ class Milvus_DB:
             FieldSchema(name="id", dtype=DataType.INT64, is_primary=True, auto_id=True),
             FieldSchema(name="document_id", dtype=DataType.INT64),
             FieldSchema(name="parent_id", dtype=DataType.INT64),
+            # connector_id is stored in the dynamic field, so collections created before it keep their schema.
+            # the search of a persona with a retrieval scope filters on it, vectors stored without it
+            # are found again after the connector is reindexed
             FieldSchema(name="content", dtype=DataType.JSON, max_length=65535),
             FieldSchema(name="vector", dtype=DataType.FLOAT_VECTOR, dim=model_dimension),
         ]
@@ -219,6 +239,7 @@ class Milvus_DB:
             entities.append({
                 "document_id": item.document_id,
                 "parent_id": item.parent_id,
+                "connector_id": connector_id,
                 "content": json_content,
                 "vector": list(embedding.vector)  # embedding.vector gives the embedding vector
             })
@@ -14,7 +14,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x13\x65mbed_service.proto\x12\ncom.cognix\"/\n\x0c\x45mbedRequest\x12\x10\n\x08\x63ontents\x18\x01 \x03(\t\x12\r\n\x05model\x18\x02 \x01(\t\"4\n\x11\x45mbedResponseItem\x12\x0f\n\x07\x63ontent\x18\x01 \x01(\t\x12\x0e\n\x06vector\x18\x02 \x03(\x02\"B\n\rEmbedResponse\x12\x31\n\nembeddings\x18\x01 \x03(\x0b\x32\x1d.com.cognix.EmbedResponseItem2U\n\x0c\x45mbedService\x12\x45\n\x0cGetEmbedding\x12\x18.com.cognix.EmbedRequest\x1a\x19.com.cognix.EmbedResponse\"\x00\x42\x1aZ\x18\x62\x61\x63kend/core/proto;protob\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x13\x65mbed_service.proto\x12\ncom.cognix\"/\n\x0c\x45mbedRequest\x12\x10\n\x08\x63ontents\x18\x01 \x03(\t\x12\r\n\x05model\x18\x02 \x01(\t\"4\n\x11\x45mbedResponseItem\x12\x0f\n\x07\x63ontent\x18\x01 \x01(\t\x12\x0e\n\x06vector\x18\x02 \x03(\x02\"B\n\rEmbedResponse\x12\x31\n\nembeddings\x18\x01 \x03(\x0b\x32\x1d.com.cognix.EmbedResponseItem\"?\n\rRerankRequest\x12\r\n\x05query\x18\x01 \x01(\t\x12\x10\n\x08\x63ontents\x18\x02 \x03(\t\x12\r\n\x05model\x18\x03 \x01(\t\"2\n\x12RerankResponseItem\x12\r\n\x05index\x18\x01 \x01(\x05\x12\r\n\x05score\x18\x02 \x01(\x02\"A\n\x0eRerankResponse\x12/\n\x07results\x18\x01 \x03(\x0b\x32\x1e.com.cognix.RerankResponseItem2\x98\x01\n\x0c\x45mbedService\x12\x45\n\x0cGetEmbedding\x12\x18.com.cognix.EmbedRequest\x1a\x19.com.cognix.EmbedResponse\"\x00\x12\x41\n\x06Rerank\x12\x19.com.cognix.RerankRequest\x1a\x1a.com.cognix.RerankResponse\"\x00\x42\x1aZ\x18\x62\x61\x63kend/core/proto;protob\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -28,6 +28,12 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['_EMBEDRESPONSEITEM']._serialized_end=136
   _globals['_EMBEDRESPONSE']._serialized_start=138
   _globals['_EMBEDRESPONSE']._serialized_end=204
-  _globals['_EMBEDSERVICE']._serialized_start=206
-  _globals['_EMBEDSERVICE']._serialized_end=291
+  _globals['_RERANKREQUEST']._serialized_start=206
+  _globals['_RERANKREQUEST']._serialized_end=269
+  _globals['_RERANKRESPONSEITEM']._serialized_start=271
+  _globals['_RERANKRESPONSEITEM']._serialized_end=321
+  _globals['_RERANKRESPONSE']._serialized_start=323
+  _globals['_RERANKRESPONSE']._serialized_end=388
+  _globals['_EMBEDSERVICE']._serialized_start=391
+  _globals['_EMBEDSERVICE']._serialized_end=543
 # @@protoc_insertion_point(module_scope)
@@ -26,3 +26,27 @@ class EmbedResponse(_message.Message):
     EMBEDDINGS_FIELD_NUMBER: _ClassVar[int]
     embeddings: _containers.RepeatedCompositeFieldContainer[EmbedResponseItem]
     def __init__(self, embeddings: _Optional[_Iterable[_Union[EmbedResponseItem, _Mapping]]] = ...) -> None: ...
+
+class RerankRequest(_message.Message):
+    __slots__ = ("query", "contents", "model")
+    QUERY_FIELD_NUMBER: _ClassVar[int]
+    CONTENTS_FIELD_NUMBER: _ClassVar[int]
+    MODEL_FIELD_NUMBER: _ClassVar[int]
+    query: str
+    contents: _containers.RepeatedScalarFieldContainer[str]
+    model: str
+    def __init__(self, query: _Optional[str] = ..., contents: _Optional[_Iterable[str]] = ..., model: _Optional[str] = ...) -> None: ...
+
+class RerankResponseItem(_message.Message):
+    __slots__ = ("index", "score")
+    INDEX_FIELD_NUMBER: _ClassVar[int]
+    SCORE_FIELD_NUMBER: _ClassVar[int]
+    index: int
+    score: float
+    def __init__(self, index: _Optional[int] = ..., score: _Optional[float] = ...) -> None: ...
+
+class RerankResponse(_message.Message):
+    __slots__ = ("results",)
+    RESULTS_FIELD_NUMBER: _ClassVar[int]
+    results: _containers.RepeatedCompositeFieldContainer[RerankResponseItem]
+    def __init__(self, results: _Optional[_Iterable[_Union[RerankResponseItem, _Mapping]]] = ...) -> None: ...
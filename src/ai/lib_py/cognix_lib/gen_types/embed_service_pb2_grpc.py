@@ -44,6 +44,11 @@ class EmbedServiceStub(object):
                 request_serializer=embed__service__pb2.EmbedRequest.SerializeToString,
                 response_deserializer=embed__service__pb2.EmbedResponse.FromString,
                 _registered_method=True)
+        self.Rerank = channel.unary_unary(
+                '/com.cognix.EmbedService/Rerank',
+                request_serializer=embed__service__pb2.RerankRequest.SerializeToString,
+                response_deserializer=embed__service__pb2.RerankResponse.FromString,
+                _registered_method=True)
 
 
 class EmbedServiceServicer(object):
@@ -55,6 +60,12 @@ class EmbedServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def Rerank(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_EmbedServiceServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -63,6 +74,11 @@ def add_EmbedServiceServicer_to_server(servicer, server):
                     request_deserializer=embed__service__pb2.EmbedRequest.FromString,
                     response_serializer=embed__service__pb2.EmbedResponse.SerializeToString,
             ),
+            'Rerank': grpc.unary_unary_rpc_method_handler(
+                    servicer.Rerank,
+                    request_deserializer=embed__service__pb2.RerankRequest.FromString,
+                    response_serializer=embed__service__pb2.RerankResponse.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'com.cognix.EmbedService', rpc_method_handlers)
@@ -99,3 +115,30 @@ class EmbedService(object):
             timeout,
             metadata,
             _registered_method=True)
+
+    @staticmethod
+    def Rerank(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/com.cognix.EmbedService/Rerank',
+            embed__service__pb2.RerankRequest.SerializeToString,
+            embed__service__pb2.RerankResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
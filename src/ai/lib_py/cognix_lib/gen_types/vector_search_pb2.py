@@ -14,7 +14,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x13vector_search.proto\x12\ncom.cognix\"r\n\rSearchRequest\x12\x0f\n\x07\x63ontent\x18\x01 \x01(\t\x12\x0f\n\x07user_id\x18\x02 \x01(\t\x12\x11\n\ttenant_id\x18\x03 \x01(\t\x12\x12\n\nmodel_name\x18\x04 \x01(\t\x12\x18\n\x10\x63ollection_names\x18\x05 \x03(\t\"?\n\x0eSearchResponse\x12-\n\tdocuments\x18\x01 \x03(\x0b\x32\x1a.com.cognix.SearchDocument\"6\n\x0eSearchDocument\x12\x13\n\x0b\x64ocument_id\x18\x01 \x01(\x03\x12\x0f\n\x07\x63ontent\x18\x02 \x01(\t2X\n\rSearchService\x12G\n\x0cVectorSearch\x12\x19.com.cognix.SearchRequest\x1a\x1a.com.cognix.SearchResponse\"\x00\x42\x1aZ\x18\x62\x61\x63kend/core/proto;protob\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x13vector_search.proto\x12\ncom.cognix\"\xc5\x01\n\rSearchRequest\x12\x0f\n\x07\x63ontent\x18\x01 \x01(\t\x12\x0f\n\x07user_id\x18\x02 \x01(\t\x12\x11\n\ttenant_id\x18\x03 \x01(\t\x12\x12\n\nmodel_name\x18\x04 \x01(\t\x12\x18\n\x10\x63ollection_names\x18\x05 \x03(\t\x12\r\n\x05top_k\x18\x06 \x01(\x05\x12\x17\n\x0fscore_threshold\x18\x07 \x01(\x02\x12)\n\x07\x66ilters\x18\x08 \x03(\x0b\x32\x18.com.cognix.SearchFilter\"?\n\x0eSearchResponse\x12-\n\tdocuments\x18\x01 \x03(\x0b\x32\x1a.com.cognix.SearchDocument\"E\n\x0eSearchDocument\x12\x13\n\x0b\x64ocument_id\x18\x01 \x01(\x03\x12\x0f\n\x07\x63ontent\x18\x02 \x01(\t\x12\r\n\x05score\x18\x03 \x01(\x02\"-\n\x0cSearchFilter\x12\r\n\x05\x66ield\x18\x01 \x01(\t\x12\x0e\n\x06values\x18\x02 \x03(\x03\x32X\n\rSearchService\x12G\n\x0cVectorSearch\x12\x19.com.cognix.SearchRequest\x1a\x1a.com.cognix.SearchResponse\"\x00\x42\x1aZ\x18\x62\x61\x63kend/core/proto;protob\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -22,12 +22,14 @@ _builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'vector_search_pb2', _global
 if not _descriptor._USE_C_DESCRIPTORS:
   _globals['DESCRIPTOR']._loaded_options = None
   _globals['DESCRIPTOR']._serialized_options = b'Z\030backend/core/proto;proto'
-  _globals['_SEARCHREQUEST']._serialized_start=35
-  _globals['_SEARCHREQUEST']._serialized_end=149
-  _globals['_SEARCHRESPONSE']._serialized_start=151
-  _globals['_SEARCHRESPONSE']._serialized_end=214
-  _globals['_SEARCHDOCUMENT']._serialized_start=216
-  _globals['_SEARCHDOCUMENT']._serialized_end=270
-  _globals['_SEARCHSERVICE']._serialized_start=272
-  _globals['_SEARCHSERVICE']._serialized_end=360
+  _globals['_SEARCHREQUEST']._serialized_start=36
+  _globals['_SEARCHREQUEST']._serialized_end=233
+  _globals['_SEARCHRESPONSE']._serialized_start=235
+  _globals['_SEARCHRESPONSE']._serialized_end=298
+  _globals['_SEARCHDOCUMENT']._serialized_start=300
+  _globals['_SEARCHDOCUMENT']._serialized_end=369
+  _globals['_SEARCHFILTER']._serialized_start=371
+  _globals['_SEARCHFILTER']._serialized_end=416
+  _globals['_SEARCHSERVICE']._serialized_start=418
+  _globals['_SEARCHSERVICE']._serialized_end=506
 # @@protoc_insertion_point(module_scope)
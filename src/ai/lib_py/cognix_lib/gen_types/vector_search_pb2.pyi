@@ -6,18 +6,24 @@ from typing import ClassVar as _ClassVar, Iterable as _Iterable, Mapping as _Map
 DESCRIPTOR: _descriptor.FileDescriptor
 
 class SearchRequest(_message.Message):
-    __slots__ = ("content", "user_id", "tenant_id", "model_name", "collection_names")
+    __slots__ = ("content", "user_id", "tenant_id", "model_name", "collection_names", "top_k", "score_threshold", "filters")
     CONTENT_FIELD_NUMBER: _ClassVar[int]
     USER_ID_FIELD_NUMBER: _ClassVar[int]
     TENANT_ID_FIELD_NUMBER: _ClassVar[int]
     MODEL_NAME_FIELD_NUMBER: _ClassVar[int]
     COLLECTION_NAMES_FIELD_NUMBER: _ClassVar[int]
+    TOP_K_FIELD_NUMBER: _ClassVar[int]
+    SCORE_THRESHOLD_FIELD_NUMBER: _ClassVar[int]
+    FILTERS_FIELD_NUMBER: _ClassVar[int]
     content: str
     user_id: str
     tenant_id: str
     model_name: str
     collection_names: _containers.RepeatedScalarFieldContainer[str]
-    def __init__(self, content: _Optional[str] = ..., user_id: _Optional[str] = ..., tenant_id: _Optional[str] = ..., model_name: _Optional[str] = ..., collection_names: _Optional[_Iterable[str]] = ...) -> None: ...
+    top_k: int
+    score_threshold: float
+    filters: _containers.RepeatedCompositeFieldContainer[SearchFilter]
+    def __init__(self, content: _Optional[str] = ..., user_id: _Optional[str] = ..., tenant_id: _Optional[str] = ..., model_name: _Optional[str] = ..., collection_names: _Optional[_Iterable[str]] = ..., top_k: _Optional[int] = ..., score_threshold: _Optional[float] = ..., filters: _Optional[_Iterable[_Union[SearchFilter, _Mapping]]] = ...) -> None: ...
 
 class SearchResponse(_message.Message):
     __slots__ = ("documents",)
@@ -26,9 +32,19 @@ class SearchResponse(_message.Message):
     def __init__(self, documents: _Optional[_Iterable[_Union[SearchDocument, _Mapping]]] = ...) -> None: ...
 
 class SearchDocument(_message.Message):
-    __slots__ = ("document_id", "content")
+    __slots__ = ("document_id", "content", "score")
     DOCUMENT_ID_FIELD_NUMBER: _ClassVar[int]
     CONTENT_FIELD_NUMBER: _ClassVar[int]
+    SCORE_FIELD_NUMBER: _ClassVar[int]
     document_id: int
     content: str
-    def __init__(self, document_id: _Optional[int] = ..., content: _Optional[str] = ...) -> None: ...
+    score: float
+    def __init__(self, document_id: _Optional[int] = ..., content: _Optional[str] = ..., score: _Optional[float] = ...) -> None: ...
+
+class SearchFilter(_message.Message):
+    __slots__ = ("field", "values")
+    FIELD_FIELD_NUMBER: _ClassVar[int]
+    VALUES_FIELD_NUMBER: _ClassVar[int]
+    field: str
+    values: _containers.RepeatedScalarFieldContainer[int]
+    def __init__(self, field: _Optional[str] = ..., values: _Optional[_Iterable[int]] = ...) -> None: ...
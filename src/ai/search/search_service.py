@@ -62,6 +62,8 @@ class SearchServicer(SearchServiceServicer):
                     for hit in hits:
                         content = hit.entity.get("content")
                         document_id = hit.entity.get("document_id")
+                        if not Milvus_DB.is_relevant(hit.distance, request.score_threshold):
+                            continue
                         if content and document_id:
                             # Ensure types are correct
                             try:
@@ -69,7 +71,8 @@ class SearchServicer(SearchServiceServicer):
                                 content = str(content)
                                 search_doc = SearchDocument(
                                     document_id=document_id,
-                                    content=content
+                                    content=content,
+                                    score=hit.distance
                                 )
                                 search_response.documents.append(search_doc)
                             except ValueError as ve:
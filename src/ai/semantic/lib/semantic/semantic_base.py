@@ -98,7 +98,12 @@ class BaseSemantic:
         # Perform batch insertion into Milvus
 
         milvus_db.store_chunk_list(chunk_list=collected_data, collection_name=data.collection_name,
-                                   model_name=data.model_name, model_dimension=data.model_dimension)
+                                   model_name=data.model_name, model_dimension=data.model_dimension,
+                                   connector_id=data.connector_id)
+
+        # storing the same chunks in the relational db for the keyword (full-text) search
+        document_crud.replace_document_chunks(document_id=data.document_id, collection_name=data.collection_name,
+                                              contents=[item.content for item in collected_data])
 
         # update the status of the doc in the relational db
         doc.analyzed = True
@@ -42,6 +42,10 @@ func (h *ConnectorHandler) Mount(route *gin.Engine, authMiddleware gin.HandlerFu
 	handler.POST("/", server.HandlerErrorFuncAuth(h.Create))
 	handler.PUT("/:id", server.HandlerErrorFuncAuth(h.Update))
 	handler.POST("/:id/:action", server.HandlerErrorFuncAuth(h.Archive))
+	handler.POST("/:id/sync", server.HandlerErrorFuncAuth(h.Sync))
+	handler.POST("/:id/reindex", server.HandlerErrorFuncAuth(h.Reindex))
+	handler.GET("/:id/runs", server.HandlerErrorFuncAuth(h.GetRuns))
+	handler.GET("/:id/runs/:run_id", server.HandlerErrorFuncAuth(h.GetRun))
 }
 
 // GetAll return list of allowed connectors
@@ -178,3 +182,101 @@ func (h *ConnectorHandler) Archive(c *gin.Context, identity *security.Identity)
 	}
 	return server.JsonResult(c, http.StatusOK, connector)
 }
+
+// GetRuns return run history of connector
+// @Summary return run history of connector
+// @Description return runs of connector with statistics, the latest run first
+// @Tags Connectors
+// @ID connectors_get_runs
+// @Param id path int true "connector id"
+// @Param limit query int false "maximum number of runs, 50 by default"
+// @Param offset query int false "number of runs to skip"
+// @Produce  json
+// @Security ApiKeyAuth
+// @Success 200 {array} model.ConnectorRun
+// @Router /manage/connector/{id}/runs [get]
+func (h *ConnectorHandler) GetRuns(c *gin.Context, identity *security.Identity) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		return utils.ErrorBadRequest.New("id should be presented")
+	}
+	var param parameters.ConnectorRunListParam
+	if err = c.ShouldBindQuery(&param); err != nil {
+		return utils.ErrorBadRequest.Wrap(err, "wrong parameters")
+	}
+	if err = param.Validate(); err != nil {
+		return utils.ErrorBadRequest.Wrapf(err, "validation error %s", err.Error())
+	}
+	runs, err := h.connectorBL.GetRuns(c.Request.Context(), identity.User, id, &param)
+	if err != nil {
+		return err
+	}
+	return server.JsonResult(c, http.StatusOK, runs)
+}
+
+// GetRun return run of connector
+// @Summary return run of connector
+// @Description return run of connector with statistics and errors of documents
+// @Tags Connectors
+// @ID connectors_get_run
+// @Param id path int true "connector id"
+// @Param run_id path int true "run id"
+// @Produce  json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.ConnectorRun
+// @Router /manage/connector/{id}/runs/{run_id} [get]
+func (h *ConnectorHandler) GetRun(c *gin.Context, identity *security.Identity) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		return utils.ErrorBadRequest.New("id should be presented")
+	}
+	runID, err := strconv.ParseInt(c.Param("run_id"), 10, 64)
+	if err != nil || runID == 0 {
+		return utils.ErrorBadRequest.New("run_id should be presented")
+	}
+	run, err := h.connectorBL.GetRun(c.Request.Context(), identity.User, id, runID)
+	if err != nil {
+		return err
+	}
+	return server.JsonResult(c, http.StatusOK, run)
+}
+
+// Sync runs connector now
+// @Summary runs connector now
+// @Description sends connector to the connector service without waiting for its refresh frequency, only new and changed documents are analyzed
+// @Tags Connectors
+// @ID connectors_sync
+// @Param id path int true "connector id"
+// @Produce  json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.ConnectorRun
+// @Router /manage/connector/{id}/sync [post]
+func (h *ConnectorHandler) Sync(c *gin.Context, identity *security.Identity) error {
+	return h.sync(c, identity, model.ModeSync)
+}
+
+// Reindex analyzes all documents of connector again
+// @Summary analyzes all documents of connector again
+// @Description drops documents and vectors of connector and sends it to the connector service
+// @Tags Connectors
+// @ID connectors_reindex
+// @Param id path int true "connector id"
+// @Produce  json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.ConnectorRun
+// @Router /manage/connector/{id}/reindex [post]
+func (h *ConnectorHandler) Reindex(c *gin.Context, identity *security.Identity) error {
+	return h.sync(c, identity, model.ModeReindex)
+}
+
+func (h *ConnectorHandler) sync(c *gin.Context, identity *security.Identity, mode string) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		return utils.ErrorBadRequest.New("id should be presented")
+	}
+	run, err := h.connectorBL.Sync(c.Request.Context(), identity.User, id, mode)
+	if err != nil {
+		return err
+	}
+	return server.JsonResult(c, http.StatusOK, run)
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"cognix.ch/api/v2/core/logic"
+	"cognix.ch/api/v2/core/parameters"
+	"cognix.ch/api/v2/core/security"
+	"cognix.ch/api/v2/core/server"
+	"cognix.ch/api/v2/core/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// DeadLetterHandler handles endpoints of messages that failed in the pipeline streams.
+type DeadLetterHandler struct {
+	deadLetterBL logic.DeadLetterBL
+}
+
+// NewDeadLetterHandler creates a new instance of DeadLetterHandler.
+func NewDeadLetterHandler(deadLetterBL logic.DeadLetterBL) *DeadLetterHandler {
+	return &DeadLetterHandler{deadLetterBL: deadLetterBL}
+}
+
+// Mount sets up the dead letter routes by adding them to the specified gin.Engine instance.
+func (h *DeadLetterHandler) Mount(router *gin.Engine, authMiddleware gin.HandlerFunc) {
+	handler := router.Group("/api/manage/dead_letters").Use(authMiddleware)
+	handler.GET("/", server.HandlerErrorFuncAuth(h.GetAll))
+	handler.GET("/:sequence", server.HandlerErrorFuncAuth(h.GetByID))
+	handler.POST("/:sequence/replay", server.HandlerErrorFuncAuth(h.Replay))
+	handler.DELETE("/:sequence", server.HandlerErrorFuncAuth(h.Delete))
+}
+
+// GetAll return list of dead letters
+// @Summary return list of dead letters
+// @Description return messages that failed in the pipeline streams, only for super admin
+// @Tags DeadLetters
+// @ID dead_letters_get_all
+// @Param stream query string false "name of the stream the messages failed in"
+// @Param from query int false "sequence of the first dead letter"
+// @Param limit query int false "maximum number of dead letters, 50 by default"
+// @Produce  json
+// @Security ApiKeyAuth
+// @Success 200 {array} messaging.DeadLetter
+// @Router /manage/dead_letters [get]
+func (h *DeadLetterHandler) GetAll(c *gin.Context, identity *security.Identity) error {
+	var param parameters.DeadLetterListParam
+	if err := c.ShouldBindQuery(&param); err != nil {
+		return utils.ErrorBadRequest.Wrap(err, "wrong parameters")
+	}
+	if err := param.Validate(); err != nil {
+		return utils.ErrorBadRequest.Wrapf(err, "validation error %s", err.Error())
+	}
+	deadLetters, err := h.deadLetterBL.GetAll(c.Request.Context(), identity.User, &param)
+	if err != nil {
+		return err
+	}
+	return server.JsonResult(c, http.StatusOK, deadLetters)
+}
+
+// GetByID return dead letter with its message
+// @Summary return dead letter with its message
+// @Description return dead letter with the parsed message, only for super admin
+// @Tags DeadLetters
+// @ID dead_letters_get_by_id
+// @Param sequence path int true "sequence of the dead letter"
+// @Produce  json
+// @Security ApiKeyAuth
+// @Success 200 {object} messaging.DeadLetter
+// @Router /manage/dead_letters/{sequence} [get]
+func (h *DeadLetterHandler) GetByID(c *gin.Context, identity *security.Identity) error {
+	sequence, err := strconv.ParseUint(c.Param("sequence"), 10, 64)
+	if err != nil || sequence == 0 {
+		return utils.ErrorBadRequest.New("sequence should be presented")
+	}
+	deadLetter, err := h.deadLetterBL.GetByID(c.Request.Context(), identity.User, sequence)
+	if err != nil {
+		return err
+	}
+	return server.JsonResult(c, http.StatusOK, deadLetter)
+}
+
+// Replay publishes the message of the dead letter again
+// @Summary publishes the message of the dead letter again
+// @Description publishes the message on its stream and removes the dead letter, only for super admin
+// @Tags DeadLetters
+// @ID dead_letters_replay
+// @Param sequence path int true "sequence of the dead letter"
+// @Produce  json
+// @Security ApiKeyAuth
+// @Success 200 {object} server.JsonResponse
+// @Router /manage/dead_letters/{sequence}/replay [post]
+func (h *DeadLetterHandler) Replay(c *gin.Context, identity *security.Identity) error {
+	sequence, err := strconv.ParseUint(c.Param("sequence"), 10, 64)
+	if err != nil || sequence == 0 {
+		return utils.ErrorBadRequest.New("sequence should be presented")
+	}
+	if err = h.deadLetterBL.Replay(c.Request.Context(), identity.User, sequence); err != nil {
+		return err
+	}
+	return server.JsonResult(c, http.StatusOK, "ok")
+}
+
+// Delete removes the dead letter
+// @Summary removes the dead letter
+// @Description removes the dead letter, only for super admin
+// @Tags DeadLetters
+// @ID dead_letters_delete
+// @Param sequence path int true "sequence of the dead letter"
+// @Produce  json
+// @Security ApiKeyAuth
+// @Success 200 {object} server.JsonResponse
+// @Router /manage/dead_letters/{sequence} [delete]
+func (h *DeadLetterHandler) Delete(c *gin.Context, identity *security.Identity) error {
+	sequence, err := strconv.ParseUint(c.Param("sequence"), 10, 64)
+	if err != nil || sequence == 0 {
+		return utils.ErrorBadRequest.New("sequence should be presented")
+	}
+	if err = h.deadLetterBL.Delete(c.Request.Context(), identity.User, sequence); err != nil {
+		return err
+	}
+	return server.JsonResult(c, http.StatusOK, "ok")
+}
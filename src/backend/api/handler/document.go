@@ -5,10 +5,14 @@ import (
 	"cognix.ch/api/v2/core/parameters"
 	"cognix.ch/api/v2/core/security"
 	"cognix.ch/api/v2/core/server"
+	"cognix.ch/api/v2/core/utils"
+	"encoding/json"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"io"
 	"mime/multipart"
 	"net/http"
+	"strconv"
 	"sync"
 )
 
@@ -33,6 +37,8 @@ func (h *DocumentHandler) Mount(router *gin.Engine, authMiddleware gin.HandlerFu
 	handler := router.Group("/api/manage/documents").Use(authMiddleware)
 	handler.GET("/", server.HandlerErrorFuncAuth(h.GetAll))
 	handler.POST("/upload", server.HandlerErrorFuncAuth(h.Upload))
+	handler.PUT("/ingestion/:id", server.HandlerErrorFuncAuth(h.Ingest))
+	handler.DELETE("/ingestion/:id", server.HandlerErrorFuncAuth(h.DeleteIngested))
 }
 
 func (h *DocumentHandler) GetAll(c *gin.Context, identity *security.Identity) error {
@@ -79,3 +85,87 @@ func (h *DocumentHandler) Upload(c *gin.Context, identity *security.Identity) er
 	wg.Wait()
 	return server.JsonResult(c, http.StatusOK, result)
 }
+
+// Ingest creates or updates a document in an ingestion api connector
+// @Summary creates or updates a document in an ingestion api connector
+// @Description creates or updates the document with the given source id and sends it to the semantic service.
+// @Description The payload is either json with text or markdown content,
+// @Description or multipart form with source_id, title, original_url, metadata (json) and file fields.
+// @Tags Documents
+// @ID documents_ingest
+// @Param id path int true "ingestion api connector id"
+// @Param params body parameters.IngestDocumentParam true "document parameter"
+// @Accept json,mpfd
+// @Produce  json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.Document
+// @Router /manage/documents/ingestion/{id} [put]
+func (h *DocumentHandler) Ingest(c *gin.Context, identity *security.Identity) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		return utils.ErrorBadRequest.New("id should be presented")
+	}
+	var param parameters.IngestDocumentParam
+	var fileName, contentType string
+	var file io.Reader
+	if c.ContentType() == gin.MIMEMultipartPOSTForm {
+		if err = c.ShouldBind(&param); err != nil {
+			return utils.ErrorBadRequest.Wrap(err, "wrong payload")
+		}
+		if metadata := c.PostForm("metadata"); metadata != "" {
+			if err = json.Unmarshal([]byte(metadata), &param.Metadata); err != nil {
+				return utils.ErrorBadRequest.Wrap(err, "wrong metadata")
+			}
+		}
+		if fileHeader, err := c.FormFile("file"); err == nil {
+			fileReader, err := fileHeader.Open()
+			if err != nil {
+				return utils.ErrorBadRequest.Wrap(err, "can not open file")
+			}
+			defer fileReader.Close()
+			fileName = fileHeader.Filename
+			contentType = fileHeader.Header.Get("Content-Type")
+			file = fileReader
+		}
+	} else if err = c.BindJSON(&param); err != nil {
+		return utils.ErrorBadRequest.Wrap(err, "wrong payload")
+	}
+	if err = param.Validate(); err != nil {
+		return utils.ErrorBadRequest.Wrapf(err, "validation error %s", err.Error())
+	}
+	document, err := h.documentBL.IngestDocument(c.Request.Context(), identity.User, id, &param, fileName, contentType, file)
+	if err != nil {
+		return err
+	}
+	return server.JsonResult(c, http.StatusOK, document)
+}
+
+// DeleteIngested deletes a document from an ingestion api connector
+// @Summary deletes a document from an ingestion api connector
+// @Description deletes the document with the given source id with its content and embeddings
+// @Tags Documents
+// @ID documents_delete_ingested
+// @Param id path int true "ingestion api connector id"
+// @Param source_id query string true "source id of the document"
+// @Produce  json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.Document
+// @Router /manage/documents/ingestion/{id} [delete]
+func (h *DocumentHandler) DeleteIngested(c *gin.Context, identity *security.Identity) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		return utils.ErrorBadRequest.New("id should be presented")
+	}
+	var param parameters.DeleteIngestedDocumentParam
+	if err = c.ShouldBindQuery(&param); err != nil {
+		return utils.ErrorBadRequest.Wrap(err, "wrong parameters")
+	}
+	if err = param.Validate(); err != nil {
+		return utils.ErrorBadRequest.Wrapf(err, "validation error %s", err.Error())
+	}
+	document, err := h.documentBL.DeleteIngestedDocument(c.Request.Context(), identity.User, id, param.SourceID)
+	if err != nil {
+		return err
+	}
+	return server.JsonResult(c, http.StatusOK, document)
+}
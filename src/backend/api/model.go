@@ -26,6 +26,7 @@ type MountParams struct {
 	DocumentHandler       *handler.DocumentHandler
 	OAuthHandler          *handler.OAuthHandler
 	ConfigMapHandler      *handler.ConfigMapHandler
+	DeadLetterHandler     *handler.DeadLetterHandler
 }
 
 type Config struct {
@@ -43,6 +43,7 @@ var Module = fx.Options(
 		handler.NewTenantHandler,
 		handler.NewDocumentHandler,
 		handler.NewConfigMapHandler,
+		handler.NewDeadLetterHandler,
 		newOauthHandler,
 	),
 	fx.Invoke(
@@ -68,6 +69,7 @@ func MountRoute(param MountParams) error {
 	param.TenantHandler.Mount(param.Router, param.AuthMiddleware.RequireAuth)
 	param.DocumentHandler.Mount(param.Router, param.AuthMiddleware.RequireAuth)
 	param.ConfigMapHandler.Mount(param.Router, param.AuthMiddleware.RequireAuth)
+	param.DeadLetterHandler.Mount(param.Router, param.AuthMiddleware.RequireAuth)
 	param.OAuthHandler.Mount(param.Router)
 	return nil
 }
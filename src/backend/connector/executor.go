@@ -14,7 +14,9 @@ import (
 	"github.com/go-pg/pg/v10"
 	"github.com/go-resty/resty/v2"
 	proto2 "github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
 	"github.com/nats-io/nats.go/jetstream"
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 	"strings"
 
@@ -27,11 +29,14 @@ type Executor struct {
 	cfg            *Config
 	connectorRepo  repository.ConnectorRepository
 	docRepo        repository.DocumentRepository
+	runRepo        repository.ConnectorRunRepository
 	msgClient      messaging.Client
 	minioClient    storage.FileStorageClient
 	milvusClient   storage.VectorDBClient
 	oauthClient    *resty.Client
 	downloadClient *resty.Client
+	// newConnector creates the connector of the model, it is replaced in tests.
+	newConnector func(connectorModel *model.Connector, connectorRepo repository.ConnectorRepository, oauthURL string) (connector.Connector, error)
 }
 
 // run is a method that listens to a specific stream and topic using the messaging.Client provided
@@ -50,6 +55,8 @@ func (e *Executor) run(streamName, topic string, task messaging.MessageHandler)
 // documents, and publishes messages to either the Voice or Semantic stream based on the file type.
 // After processing all the results, it deletes unused files associated with the connector and updates the connector status.
 // Finally, it updates the connector in the repository and returns any error that occurred during the process.
+// Statistics and errors of documents are recorded in the run of the session.
+// In the reindex mode all documents of the connector are dropped before the connector is executed.
 func (e *Executor) runConnector(ctx context.Context, msg jetstream.Msg) error {
 	startTime := time.Now()
 	//ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(msg.Header()))
@@ -57,13 +64,20 @@ func (e *Executor) runConnector(ctx context.Context, msg jetstream.Msg) error {
 
 	if err := proto2.Unmarshal(msg.Data(), &trigger); err != nil {
 		zap.S().Errorf("Error unmarshalling message: %s", err.Error())
-		return err
+		// the message is broken, delivering it again does not help
+		return messaging.Permanent(err)
 	}
 	// read connector model with documents, embedding model
 	connectorModel, err := e.connectorRepo.GetByID(ctx, trigger.GetId())
 	if err != nil {
 		return err
 	}
+	if !connectorModel.DeletedDate.IsZero() {
+		return utils.NotFound.Newf("connector %d is deleted", trigger.GetId())
+	}
+	if connectorModel.Status == model.ConnectorStatusDisabled {
+		return utils.ErrorBadRequest.Newf("connector %d is disabled", trigger.GetId())
+	}
 	defer func() {
 		zap.S().Infof("connector %s completed. elapsed time: %d ms", connectorModel.Name, time.Since(startTime)/time.Millisecond)
 	}()
@@ -72,19 +86,42 @@ func (e *Executor) runConnector(ctx context.Context, msg jetstream.Msg) error {
 	// refresh token if needed
 	connectorModel.Status = model.ConnectorStatusWorking
 
-	// create new instance of connector by connector model
-	connectorWF, err := connector.New(connectorModel, e.connectorRepo, e.cfg.OAuthURL)
+	if trigger.Params == nil {
+		trigger.Params = make(map[string]string)
+	}
+	run, err := e.startRun(ctx, connectorModel, trigger.Params)
 	if err != nil {
-		zap.S().Error(err)
 		return err
 	}
-	if trigger.Params == nil {
-		trigger.Params = make(map[string]string)
+	var runErr error
+	defer func() {
+		run.Finish(runErr)
+		if errr := e.runRepo.Update(ctx, run); errr != nil {
+			zap.S().Errorf("failed to update connector run: %v", errr)
+		}
+	}()
+
+	if trigger.Params[model.ParamMode] == model.ModeReindex {
+		if err = e.dropDocuments(ctx, connectorModel, run); err != nil {
+			runErr = err
+			return err
+		}
+	}
+
+	// create new instance of connector by connector model
+	connectorWF, err := e.newConnector(connectorModel, e.connectorRepo, e.cfg.OAuthURL)
+	if err != nil {
+		zap.S().Error(err)
+		runErr = err
+		// the configuration of the connector is invalid
+		return messaging.Permanent(err)
 	}
 	// execute connector
+	trigger.Params[model.ParamLocalRoot] = e.cfg.LocalRoot
 	resultCh := connectorWF.Execute(ctx, trigger.Params)
 	// read result from channel
 	hasSemanticMessage := false
+	processed := make(map[string]bool)
 	for result := range resultCh {
 		var loopErr error
 		// empty result when channel was closed.
@@ -92,18 +129,25 @@ func (e *Executor) runConnector(ctx context.Context, msg jetstream.Msg) error {
 			break
 		}
 		hasSemanticMessage = true
+		processed[result.SourceID] = true
 
 		// save content in minio
 		if result.Content != nil {
-			if err = e.saveContent(ctx, result); err != nil {
-				loopErr = err
+			size, saveErr := e.saveContent(ctx, result)
+			run.BytesDownloaded += size
+			if saveErr != nil {
+				err = saveErr
+				run.AddDocumentError(result.SourceID, saveErr)
+				zap.S().Errorf("Failed to save content: %v", saveErr)
+				continue
 			}
-
 		}
+		existing, ok := connectorModel.DocsMap[result.SourceID]
+		isNew := !ok || existing.ID.IntPart() == 0
 		// find or create document from result
 		doc := e.handleResult(connectorModel, result)
 		// create or update document in database
-		if doc.ID.IntPart() != 0 {
+		if !isNew {
 			loopErr = e.docRepo.Update(ctx, doc)
 		} else {
 			loopErr = e.docRepo.Create(ctx, doc)
@@ -111,6 +155,7 @@ func (e *Executor) runConnector(ctx context.Context, msg jetstream.Msg) error {
 
 		if loopErr != nil {
 			err = loopErr
+			run.AddDocumentError(result.SourceID, loopErr)
 			zap.S().Errorf("Failed to update document: %v", loopErr)
 			continue
 		}
@@ -134,6 +179,7 @@ func (e *Executor) runConnector(ctx context.Context, msg jetstream.Msg) error {
 				e.msgClient.StreamConfig().VoiceStreamSubject,
 				&voiceDate); loopErr != nil {
 				err = loopErr
+				run.AddDocumentError(result.SourceID, loopErr)
 				zap.S().Errorf("Failed to publish voice service: %v", loopErr)
 				continue
 			}
@@ -155,13 +201,27 @@ func (e *Executor) runConnector(ctx context.Context, msg jetstream.Msg) error {
 				e.msgClient.StreamConfig().SemanticStreamSubject,
 				&semanticData); loopErr != nil {
 				err = loopErr
+				run.AddDocumentError(result.SourceID, loopErr)
 				zap.S().Errorf("Failed to publish semantic: %v", loopErr)
 				continue
 			}
 		}
+		if isNew {
+			run.DocsAdded++
+		} else {
+			run.DocsUpdated++
+		}
+	}
+	for sourceID, doc := range connectorModel.DocsMap {
+		if doc.IsExists && doc.ID.IntPart() != 0 && !processed[sourceID] {
+			run.DocsSkipped++
+		}
 	}
-	if errr := e.deleteUnusedFiles(ctx, connectorModel); err != nil {
+	deleted, errr := e.deleteUnusedFiles(ctx, connectorModel)
+	run.DocsDeleted += deleted
+	if errr != nil {
 		zap.S().Errorf("deleting unused files: %v", errr)
+		runErr = errr
 		if err == nil {
 			err = errr
 		}
@@ -177,11 +237,43 @@ func (e *Executor) runConnector(ctx context.Context, msg jetstream.Msg) error {
 	connectorModel.LastUpdate = pg.NullTime{time.Now().UTC()}
 
 	if err = e.connectorRepo.Update(ctx, connectorModel); err != nil {
+		runErr = err
 		return err
 	}
 	return nil
 }
 
+// startRun returns the run of the session from the message parameters with reset statistics.
+// A run is created if the orchestrator did not record one, like for messages without a session.
+// Every delivery of the message counts as an attempt.
+func (e *Executor) startRun(ctx context.Context, connectorModel *model.Connector, params map[string]string) (*model.ConnectorRun, error) {
+	sessionID, err := uuid.Parse(params[model.ParamSessionID])
+	if err != nil {
+		sessionID = uuid.New()
+	}
+	existing, err := e.runRepo.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	run := &model.ConnectorRun{
+		ConnectorID:    connectorModel.ID,
+		SessionID:      sessionID,
+		Status:         model.ConnectorStatusWorking,
+		Attempts:       1,
+		DocumentErrors: make([]*model.ConnectorRunError, 0),
+		CreationDate:   now,
+		StartDate:      pg.NullTime{now},
+	}
+	if existing == nil {
+		return run, e.runRepo.Create(ctx, run)
+	}
+	run.ID = existing.ID
+	run.CreationDate = existing.CreationDate
+	run.Attempts = existing.Attempts + 1
+	return run, e.runRepo.Update(ctx, run)
+}
+
 // deleteUnusedFiles is a method that deletes unused files associated with a connector.
 // It iterates through the documents in the connector's DocsMap, checks if the document
 // is not marked as exists and has a non-zero ID. If the document's URL starts with "minio:",
@@ -190,8 +282,8 @@ func (e *Executor) runConnector(ctx context.Context, msg jetstream.Msg) error {
 // After iterating through all the documents, if the "ids" slice is not empty,
 // it uses the e.milvusClient to delete the documents from the Milvus storage and
 // the e.docRepo to delete the documents from the repository.
-// If there are any errors during the process, it returns the error. Otherwise, it returns nil.
-func (e *Executor) deleteUnusedFiles(ctx context.Context, connector *model.Connector) error {
+// It returns the number of deleted documents and the error if there are any errors during the process.
+func (e *Executor) deleteUnusedFiles(ctx context.Context, connector *model.Connector) (int, error) {
 	var ids []int64
 	for _, doc := range connector.DocsMap {
 		if doc.IsExists || doc.ID.IntPart() == 0 {
@@ -200,17 +292,36 @@ func (e *Executor) deleteUnusedFiles(ctx context.Context, connector *model.Conne
 		filepath := strings.Split(doc.URL, ":")
 		if len(filepath) == 3 && filepath[0] == "minio" {
 			if err := e.minioClient.DeleteObject(ctx, filepath[1], filepath[2]); err != nil {
-				return err
+				return 0, err
 			}
 		}
 		ids = append(ids, doc.ID.IntPart())
 	}
 	if len(ids) > 0 {
 		if err := e.milvusClient.Delete(ctx, connector.CollectionName(), ids...); err != nil {
-			return err
+			return 0, err
 		}
-		return e.docRepo.DeleteByIDS(ctx, ids...)
+		if err := e.docRepo.DeleteByIDS(ctx, ids...); err != nil {
+			return 0, err
+		}
+	}
+	return len(ids), nil
+}
+
+// dropDocuments deletes all documents of the connector with their files and vectors and resets
+// the state of the connector, so that the connector analyzes all documents again as new documents.
+func (e *Executor) dropDocuments(ctx context.Context, connectorModel *model.Connector, run *model.ConnectorRun) error {
+	for _, doc := range connectorModel.DocsMap {
+		doc.IsExists = false
+	}
+	deleted, err := e.deleteUnusedFiles(ctx, connectorModel)
+	if err != nil {
+		return err
 	}
+	run.DocsDeleted += deleted
+	connectorModel.Docs = nil
+	connectorModel.DocsMap = make(map[string]*model.Document)
+	connectorModel.State = model.JSONMap{}
 	return nil
 }
 
@@ -219,10 +330,11 @@ func (e *Executor) deleteUnusedFiles(ctx context.Context, connector *model.Conne
 // reader from the raw content and save it. The method uses the `minioClient` to upload the file
 // to the specified bucket using the provided name, MIME type, and reader. Upon successful upload,
 // it sets the URL of the response to the corresponding MinIO URL and returns nil. If any error
-// occurs during the process, it returns the error.
+// occurs during the process, it returns the error. The number of bytes read from the content is
+// returned in both cases.
 //
 // The method expects a context and a pointer to a `connector.Response` as input parameters.
-func (e *Executor) saveContent(ctx context.Context, response *connector.Response) error {
+func (e *Executor) saveContent(ctx context.Context, response *connector.Response) (int64, error) {
 
 	var reader io.Reader
 	//  download file if url presented.
@@ -232,7 +344,7 @@ func (e *Executor) saveContent(ctx context.Context, response *connector.Response
 			Get(response.Content.URL)
 		defer fileResponse.RawBody().Close()
 		if err = utils.WrapRestyError(fileResponse, err); err != nil {
-			return err
+			return 0, err
 		}
 		reader = fileResponse.RawBody()
 	} else {
@@ -245,12 +357,25 @@ func (e *Executor) saveContent(ctx context.Context, response *connector.Response
 		}
 	}
 
-	fileName, _, err := e.minioClient.Upload(ctx, response.Content.Bucket, response.Name, response.MimeType, reader)
+	counter := &countingReader{reader: reader}
+	fileName, _, err := e.minioClient.Upload(ctx, response.Content.Bucket, response.Name, response.MimeType, counter)
 	if err != nil {
-		return err
+		return counter.size, err
 	}
 	response.URL = fmt.Sprintf("minio:%s:%s", response.Content.Bucket, fileName)
-	return nil
+	return counter.size, nil
+}
+
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	reader io.Reader
+	size   int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.size += int64(n)
+	return n, err
 }
 
 // handleResult is a method that handles the result of a connector task.
@@ -261,6 +386,7 @@ func (e *Executor) saveContent(ctx context.Context, response *connector.Response
 // the CreationDate is set to the current UTC time, and other fields are populated from the Response.
 // If the SourceID already exists, it updates the existing Document's URL to the Response's URL
 // and updates the LastUpdate field to the current UTC time.
+// If the Response refers to a parent document that is already stored, the Document's ParentID is set.
 // The method returns the updated or newly created Document.
 func (e *Executor) handleResult(connectorModel *model.Connector, result *connector.Response) *model.Document {
 	doc, ok := connectorModel.DocsMap[result.SourceID]
@@ -277,19 +403,25 @@ func (e *Executor) handleResult(connectorModel *model.Connector, result *connect
 		doc.URL = result.URL
 		doc.LastUpdate = pg.NullTime{time.Now().UTC()}
 	}
+	if result.ParentSourceID != "" {
+		if parent, ok := connectorModel.DocsMap[result.ParentSourceID]; ok && parent.ID.IntPart() != 0 {
+			doc.ParentID = decimal.NewNullDecimal(parent.ID)
+		}
+	}
 
 	return doc
 }
 
 // NewExecutor is a constructor function that creates a new instance of the Executor struct.
 // It takes in various dependencies including a *Config for configuration, a ConnectorRepository for accessing connectors,
-// a DocumentRepository for accessing documents, a messaging.Client for handling messaging,
+// a DocumentRepository for accessing documents, a ConnectorRunRepository for recording runs, a messaging.Client for handling messaging,
 // a storage.FileStorageClient for working with MinIO storage, and a storage.VectorDBClient for working with Milvus storage.
 // It returns a pointer to the newly created Executor instance.
 func NewExecutor(
 	cfg *Config,
 	connectorRepo repository.ConnectorRepository,
 	docRepo repository.DocumentRepository,
+	runRepo repository.ConnectorRunRepository,
 	streamClient messaging.Client,
 	minioClient storage.FileStorageClient,
 	milvusClient storage.VectorDBClient,
@@ -298,6 +430,7 @@ func NewExecutor(
 		cfg:           cfg,
 		connectorRepo: connectorRepo,
 		docRepo:       docRepo,
+		runRepo:       runRepo,
 		msgClient:     streamClient,
 		minioClient:   minioClient,
 		milvusClient:  milvusClient,
@@ -307,5 +440,6 @@ func NewExecutor(
 		downloadClient: resty.New().
 			SetTimeout(time.Minute).
 			SetDoNotParseResponse(true),
+		newConnector: connector.New,
 	}
 }
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"cognix.ch/api/v2/core/connector"
+	"cognix.ch/api/v2/core/messaging"
+	"cognix.ch/api/v2/core/model"
+	"cognix.ch/api/v2/core/proto"
+	"cognix.ch/api/v2/core/repository"
+	"cognix.ch/api/v2/core/storage"
+	"cognix.ch/api/v2/orchestrator/mocks"
+	"context"
+	"errors"
+	"fmt"
+	proto2 "github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// fakeConnector reports the results and marks the unchanged documents as existing like the connectors do.
+type fakeConnector struct {
+	model     *model.Connector
+	events    *[]string
+	results   []*connector.Response
+	unchanged []string
+}
+
+func (c *fakeConnector) Execute(ctx context.Context, param map[string]string) chan *connector.Response {
+	*c.events = append(*c.events, "execute")
+	for _, result := range c.results {
+		doc, ok := c.model.DocsMap[result.SourceID]
+		if !ok {
+			doc = &model.Document{SourceID: result.SourceID, ConnectorID: c.model.ID}
+			c.model.DocsMap[result.SourceID] = doc
+		}
+		doc.IsExists = true
+	}
+	for _, sourceID := range c.unchanged {
+		c.model.DocsMap[sourceID].IsExists = true
+	}
+	ch := make(chan *connector.Response, len(c.results))
+	for _, result := range c.results {
+		ch <- result
+	}
+	close(ch)
+	return ch
+}
+
+func (c *fakeConnector) PrepareTask(ctx context.Context, sessionID uuid.UUID, task connector.Task) error {
+	return nil
+}
+
+func (c *fakeConnector) Validate() error {
+	return nil
+}
+
+type docRepoRecorder struct {
+	repository.DocumentRepository
+	events *[]string
+	nextID int64
+}
+
+func (r *docRepoRecorder) Create(ctx context.Context, document *model.Document) error {
+	if document.SourceID == "broken" {
+		return errors.New("can not create document")
+	}
+	r.nextID++
+	document.ID = decimal.NewFromInt(100 + r.nextID)
+	*r.events = append(*r.events, "create "+document.SourceID)
+	return nil
+}
+
+func (r *docRepoRecorder) Update(ctx context.Context, document *model.Document) error {
+	*r.events = append(*r.events, "update "+document.SourceID)
+	return nil
+}
+
+func (r *docRepoRecorder) DeleteByIDS(ctx context.Context, ids ...int64) error {
+	*r.events = append(*r.events, fmt.Sprintf("delete %d documents", len(ids)))
+	return nil
+}
+
+type fileStorageStub struct {
+	storage.FileStorageClient
+	events *[]string
+}
+
+func (s *fileStorageStub) DeleteObject(ctx context.Context, bucket, filename string) error {
+	*s.events = append(*s.events, "delete object "+filename)
+	return nil
+}
+
+type vectorDBStub struct {
+	storage.VectorDBClient
+	events *[]string
+}
+
+func (v *vectorDBStub) Delete(ctx context.Context, collection string, documentID ...int64) error {
+	*v.events = append(*v.events, fmt.Sprintf("delete %d vectors", len(documentID)))
+	return nil
+}
+
+type connectorMsgStub struct {
+	jetstream.Msg
+	data []byte
+}
+
+func (m *connectorMsgStub) Data() []byte {
+	return m.data
+}
+
+// newTestExecutor returns the executor of the connector model that runs the fake connector.
+func newTestExecutor(connectorModel *model.Connector, fake *fakeConnector) (*Executor, *mocks.MockConnectorRunRepository) {
+	runRepo := mocks.NewMockConnectorRunRepo().(*mocks.MockConnectorRunRepository)
+	fake.model = connectorModel
+	return &Executor{
+		cfg:           &Config{},
+		connectorRepo: mocks.NewMockConnectorRepoWithConnectors(map[int64]*model.Connector{connectorModel.ID.IntPart(): connectorModel}),
+		docRepo:       &docRepoRecorder{DocumentRepository: mocks.NewMockDocumentRepo(), events: fake.events},
+		runRepo:       runRepo,
+		msgClient:     mocks.NewMockMessenger(nil),
+		minioClient:   &fileStorageStub{events: fake.events},
+		milvusClient:  &vectorDBStub{events: fake.events},
+		newConnector: func(connectorModel *model.Connector, connectorRepo repository.ConnectorRepository, oauthURL string) (connector.Connector, error) {
+			return fake, nil
+		},
+	}, runRepo
+}
+
+func newTestConnector(docs ...*model.Document) *model.Connector {
+	connectorModel := &model.Connector{
+		ID:      decimal.NewFromInt(1),
+		Name:    "test connector",
+		Type:    model.SourceTypeWEB,
+		Status:  model.ConnectorStatusPending,
+		State:   model.JSONMap{"cursor": "next"},
+		User:    &model.User{EmbeddingModel: &model.EmbeddingModel{ModelDim: 3}},
+		DocsMap: make(map[string]*model.Document),
+	}
+	for _, doc := range docs {
+		doc.ConnectorID = connectorModel.ID
+		connectorModel.Docs = append(connectorModel.Docs, doc)
+		connectorModel.DocsMap[doc.SourceID] = doc
+	}
+	return connectorModel
+}
+
+func connectorMessage(t *testing.T, params map[string]string) jetstream.Msg {
+	data, err := proto2.Marshal(&proto.ConnectorRequest{Id: 1, Params: params})
+	assert.NoError(t, err)
+	return &connectorMsgStub{data: data}
+}
+
+func TestExecutor_Reindex(t *testing.T) {
+	events := make([]string, 0)
+	connectorModel := newTestConnector(
+		&model.Document{ID: decimal.NewFromInt(10), SourceID: "page-1", URL: "minio:bucket:page-1.md"},
+		&model.Document{ID: decimal.NewFromInt(11), SourceID: "page-2", URL: "https://page-2"},
+	)
+	fake := &fakeConnector{
+		events:  &events,
+		results: []*connector.Response{{SourceID: "page-1", URL: "https://page-1"}},
+	}
+	executor, runRepo := newTestExecutor(connectorModel, fake)
+
+	err := executor.runConnector(context.Background(), connectorMessage(t, map[string]string{
+		model.ParamMode: model.ModeReindex,
+	}))
+	assert.NoError(t, err)
+	// documents, files and vectors are dropped before the connector analyzes all documents again
+	assert.Equal(t, []string{
+		"delete object page-1.md",
+		"delete 2 vectors",
+		"delete 2 documents",
+		"execute",
+		"create page-1",
+	}, events)
+	assert.Empty(t, connectorModel.State)
+	if runs := runRepo.Runs(); assert.Len(t, runs, 1) {
+		assert.Equal(t, 2, runs[0].DocsDeleted)
+		assert.Equal(t, 1, runs[0].DocsAdded)
+		assert.Equal(t, 0, runs[0].DocsUpdated)
+	}
+}
+
+func TestExecutor_RunStatistics(t *testing.T) {
+	events := make([]string, 0)
+	connectorModel := newTestConnector(
+		&model.Document{ID: decimal.NewFromInt(10), SourceID: "changed", URL: "https://changed"},
+		&model.Document{ID: decimal.NewFromInt(11), SourceID: "unchanged", URL: "https://unchanged"},
+		&model.Document{ID: decimal.NewFromInt(12), SourceID: "removed", URL: "https://removed"},
+	)
+	fake := &fakeConnector{
+		events: &events,
+		results: []*connector.Response{
+			{SourceID: "changed", URL: "https://changed"},
+			{SourceID: "new", URL: "https://new"},
+			{SourceID: "broken", URL: "https://broken"},
+		},
+		unchanged: []string{"unchanged"},
+	}
+	executor, runRepo := newTestExecutor(connectorModel, fake)
+	sessionID := uuid.New()
+
+	err := executor.runConnector(context.Background(), connectorMessage(t, map[string]string{
+		model.ParamSessionID: sessionID.String(),
+	}))
+	assert.NoError(t, err)
+	if runs := runRepo.Runs(); assert.Len(t, runs, 1) {
+		run := runs[0]
+		assert.Equal(t, sessionID, run.SessionID)
+		assert.Equal(t, 1, run.Attempts)
+		assert.Equal(t, 1, run.DocsAdded)
+		assert.Equal(t, 1, run.DocsUpdated)
+		assert.Equal(t, 1, run.DocsSkipped)
+		assert.Equal(t, 1, run.DocsDeleted)
+		assert.Equal(t, 1, run.DocsFailed)
+		if assert.Len(t, run.DocumentErrors, 1) {
+			assert.Equal(t, "broken", run.DocumentErrors[0].SourceID)
+			assert.Equal(t, "can not create document", run.DocumentErrors[0].Error)
+		}
+		// the run is completed with errors of documents
+		assert.Equal(t, model.ConnectorStatusError, run.Status)
+		assert.False(t, run.EndDate.IsZero())
+	}
+	assert.Equal(t, model.ConnectorStatusUnableProcess, connectorModel.Status)
+}
+
+func TestExecutor_RunFailure(t *testing.T) {
+	events := make([]string, 0)
+	executor, runRepo := newTestExecutor(newTestConnector(), &fakeConnector{events: &events})
+	executor.newConnector = func(connectorModel *model.Connector, connectorRepo repository.ConnectorRepository, oauthURL string) (connector.Connector, error) {
+		return nil, errors.New("invalid config")
+	}
+
+	err := executor.runConnector(context.Background(), connectorMessage(t, map[string]string{}))
+	assert.True(t, messaging.IsPermanent(err))
+	if runs := runRepo.Runs(); assert.Len(t, runs, 1) {
+		assert.Equal(t, model.ConnectorStatusUnableProcess, runs[0].Status)
+		assert.Equal(t, "invalid config", runs[0].Error)
+		assert.False(t, runs[0].EndDate.IsZero())
+	}
+	assert.Empty(t, events)
+}
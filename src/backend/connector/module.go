@@ -10,8 +10,11 @@ import (
 	"go.uber.org/zap"
 )
 
+// Config contains the settings of the connector service.
+// LocalRoot is the directory that local git repositories must be in, local repositories are disabled if it is empty.
 type Config struct {
-	OAuthURL string `env:"OAUTH_URL,required"`
+	OAuthURL  string `env:"OAUTH_URL,required"`
+	LocalRoot string `env:"CONNECTOR_LOCAL_ROOT"`
 }
 
 var Module = fx.Options(
@@ -31,6 +34,7 @@ var Module = fx.Options(
 		},
 		repository.NewConnectorRepository,
 		repository.NewDocumentRepository,
+		repository.NewConnectorRunRepository,
 		repository.NewEmbeddingModelRepository,
 		NewExecutor,
 	),
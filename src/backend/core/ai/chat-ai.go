@@ -1,25 +1,55 @@
 package ai
 
 import (
+	"bufio"
 	"cognix.ch/api/v2/core/utils"
 	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/go-resty/resty/v2"
+	"io"
+	"net"
+	"net/http"
+	"strings"
 	"time"
 )
 
-const ChatMessageRoleUser = "user"
+const (
+	ChatMessageRoleSystem    = "system"
+	ChatMessageRoleUser      = "user"
+	ChatMessageRoleAssistant = "assistant"
+)
+
 const chatCompletionURL = "/chat/completions"
 
+const (
+	streamDataPrefix = "data:"
+	streamDone       = "[DONE]"
+)
+
+// chatTimeout limits a request without streaming, the connection and the headers of a streamed answer.
+// The body of a streamed answer is read until the stream ends or the context is canceled.
+const chatTimeout = time.Minute
+
 type ChatAI struct {
-	client    *resty.Client
-	apiKey    string
-	modelName string
+	client       *resty.Client
+	streamClient *resty.Client
+	apiKey       string
+	modelName    string
 }
 type ChatRequest struct {
-	Model    string         `json:"model"`
-	Messages []*ChatMessage `json:"messages"`
+	Model          string              `json:"model"`
+	Messages       []*ChatMessage      `json:"messages"`
+	Stream         bool                `json:"stream,omitempty"`
+	Temperature    *float32            `json:"temperature,omitempty"`
+	TopP           *float32            `json:"top_p,omitempty"`
+	MaxTokens      int                 `json:"max_tokens,omitempty"`
+	Stop           []string            `json:"stop,omitempty"`
+	ResponseFormat *ChatResponseFormat `json:"response_format,omitempty"`
+}
+
+type ChatResponseFormat struct {
+	Type string `json:"type"`
 }
 
 type ChatResponse struct {
@@ -41,15 +71,26 @@ type ChatChoice struct {
 	FinishReason string      `json:"finish_reason"`
 }
 
-func (c *ChatAI) Request(ctx context.Context, message string) (*Response, error) {
-	request := &ChatRequest{
-		Model: c.modelName,
-		Messages: []*ChatMessage{
-			{Role: ChatMessageRoleUser,
-				Content: message,
-			}},
-	}
-	response, err := c.client.R().SetHeader("Authorization", fmt.Sprintf("Bearer %s", c.apiKey)).SetBody(request).Post(chatCompletionURL)
+// ChatStreamResponse is a chunk of the answer received in streaming mode.
+type ChatStreamResponse struct {
+	Id      string              `json:"id"`
+	Object  string              `json:"object"`
+	Created int                 `json:"created"`
+	Model   string              `json:"model"`
+	Choices []*ChatStreamChoice `json:"choices"`
+}
+
+type ChatStreamChoice struct {
+	Index        int         `json:"index"`
+	Delta        ChatMessage `json:"delta"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+func (c *ChatAI) Request(ctx context.Context, messages []*ChatMessage, settings *GenerationSettings) (*Response, error) {
+	request := c.chatRequest(messages, settings)
+	response, err := c.client.R().SetContext(ctx).
+		SetHeader("Authorization", fmt.Sprintf("Bearer %s", c.apiKey)).
+		SetBody(request).Post(chatCompletionURL)
 	if err = utils.WrapRestyError(response, err); err != nil {
 		return nil, err
 	}
@@ -64,10 +105,89 @@ func (c *ChatAI) Request(ctx context.Context, message string) (*Response, error)
 	return &Response{Message: chatResponse.Choices[0].Message.Content}, nil
 }
 
+// RequestStream sends the message with stream enabled and reads server-sent events
+// until the end of the stream. Every chunk of the answer is passed to the callback.
+func (c *ChatAI) RequestStream(ctx context.Context, messages []*ChatMessage, settings *GenerationSettings, callback StreamCallback) (*Response, error) {
+	request := c.chatRequest(messages, settings)
+	request.Stream = true
+	response, err := c.streamClient.R().SetContext(ctx).
+		SetHeader("Authorization", fmt.Sprintf("Bearer %s", c.apiKey)).
+		SetHeader("Accept", "text/event-stream").
+		SetDoNotParseResponse(true).
+		SetBody(request).Post(chatCompletionURL)
+	if err != nil {
+		return nil, err
+	}
+	body := response.RawBody()
+	defer body.Close()
+	if response.IsError() {
+		errMsg, _ := io.ReadAll(body)
+		return nil, fmt.Errorf("ai response %s : %s", response.Status(), string(errMsg))
+	}
+
+	var answer strings.Builder
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, streamDataPrefix) {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, streamDataPrefix))
+		if data == streamDone {
+			break
+		}
+		var chunk ChatStreamResponse
+		if err = json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, err
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+		answer.WriteString(chunk.Choices[0].Delta.Content)
+		if err = callback(chunk.Choices[0].Delta.Content); err != nil {
+			return nil, err
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	if answer.Len() == 0 {
+		return nil, fmt.Errorf("no response from ai")
+	}
+	return &Response{Message: answer.String()}, nil
+}
+
+// chatRequest builds the chat completion request with the generation settings.
+func (c *ChatAI) chatRequest(messages []*ChatMessage, settings *GenerationSettings) *ChatRequest {
+	request := &ChatRequest{
+		Model:    c.modelName,
+		Messages: messages,
+	}
+	if settings == nil {
+		return request
+	}
+	request.Temperature = settings.Temperature
+	request.TopP = settings.TopP
+	request.MaxTokens = settings.MaxTokens
+	request.Stop = settings.Stop
+	if settings.ResponseFormat != "" {
+		request.ResponseFormat = &ChatResponseFormat{Type: settings.ResponseFormat}
+	}
+	return request
+}
+
+// NewChatAI creates a client of an OpenAI compatible chat API. Streamed answers of self-hosted models
+// may take longer than chatTimeout, so they are requested without a timeout of the whole request.
 func NewChatAI(baseUrl, apiKey, modelName string) Client {
+	streamTransport := http.DefaultTransport.(*http.Transport).Clone()
+	streamTransport.DialContext = (&net.Dialer{Timeout: chatTimeout}).DialContext
+	streamTransport.TLSHandshakeTimeout = chatTimeout
+	streamTransport.ResponseHeaderTimeout = chatTimeout
 	return &ChatAI{
-		client:    resty.New().SetTimeout(time.Minute).SetBaseURL(baseUrl),
-		apiKey:    apiKey,
-		modelName: modelName,
+		client:       resty.New().SetTimeout(chatTimeout).SetBaseURL(baseUrl),
+		streamClient: resty.New().SetTransport(streamTransport).SetBaseURL(baseUrl),
+		apiKey:       apiKey,
+		modelName:    modelName,
 	}
 }
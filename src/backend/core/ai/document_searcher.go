@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"cognix.ch/api/v2/core/repository"
 	"cognix.ch/api/v2/core/storage"
 	"context"
 	"fmt"
@@ -12,12 +13,14 @@ type SearcherConfig struct {
 	ApiVectorSearch  string `env:"API-VECTOR-SEARCH" envDefault:"INTERNAL"`
 	InternalSearcher *EmbeddingConfig
 	GRPCSearcher     *VectorSearchConfig
+	Reranker         *RerankerConfig
 }
 
 // SearcherResponse represents the response structure for a search operation.
 type SearcherResponse struct {
-	DocumentID int64  `json:"document_id,omitempty"`
-	Content    string `json:"content,omitempty"`
+	DocumentID int64   `json:"document_id,omitempty"`
+	Content    string  `json:"content,omitempty"`
+	Score      float32 `json:"score,omitempty"`
 }
 
 // Searcher is an interface that defines the method for finding documents based on search criteria.
@@ -25,19 +28,22 @@ type Searcher interface {
 	FindDocuments(ctx context.Context, userID, tenantID uuid.UUID,
 		embeddingModel string,
 		message string,
+		params *storage.SearchParams,
 		collectionNames ...string) ([]*SearcherResponse, error)
 }
 
 // NewSearcher creates a new Searcher based on the specified searcherType.
 //
 // It takes in the searcherType as a string, embeddBuilder as an EmbeddingBuilder,
-// vectorDB as a VectorDBClient, and embeddGRPCBuilder as a GRPCEmbeddingBuilder.
+// vectorDB as a VectorDBClient, embeddGRPCBuilder as a GRPCEmbeddingBuilder
+// and chunkRepo as a DocumentChunkRepository.
 //
 // It returns a Searcher interface and an error.
 //
 // It checks the value of searcherType and returns an instance of InternalSearcher if
-// the searcherType is VectorSearchInternal, or an instance of SearcherGRPC if the
-// searcherType is VectorSearchGRPCService. Otherwise, it returns an error indicating
+// the searcherType is VectorSearchInternal, an instance of SearcherGRPC if the
+// searcherType is VectorSearchGRPCService, or an instance of HybridSearcher if the
+// searcherType is VectorSearchHybrid. Otherwise, it returns an error indicating
 // that the specified searcherType is not implemented.
 //
 // The InternalSearcher implementation of the Searcher interface uses the embeddBuilder
@@ -46,11 +52,15 @@ type Searcher interface {
 //
 // The SearcherGRPC implementation of the Searcher interface uses the embeddGRPCBuilder to
 // search for documents by performing vector search over gRPC.
+//
+// The HybridSearcher implementation of the Searcher interface combines the vector search of
+// InternalSearcher with the full-text search over chunks stored in the database.
 func NewSearcher(
 	searcherType string,
 	embeddBuilder *EmbeddingBuilder,
 	vectorDB storage.VectorDBClient,
 	embeddGRPCBuilder *GRPCEmbeddingBuilder,
+	chunkRepo repository.DocumentChunkRepository,
 ) (Searcher, error) {
 	switch searcherType {
 	case VectorSearchInternal:
@@ -62,6 +72,14 @@ func NewSearcher(
 		return &SearcherGRPC{
 			embeddBuilder: embeddGRPCBuilder,
 		}, nil
+	case VectorSearchHybrid:
+		return &HybridSearcher{
+			vectorSearcher: &InternalSearcher{
+				embeddBuilder: embeddBuilder,
+				vectorDB:      vectorDB,
+			},
+			chunkRepo: chunkRepo,
+		}, nil
 	}
 	return nil, fmt.Errorf("vector searcher %s not implemented", searcherType)
 }
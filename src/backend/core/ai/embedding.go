@@ -14,6 +14,7 @@ import (
 const (
 	VectorSearchInternal    = "INTERNAL"
 	VectorSearchGRPCService = "GRPC-SERVICE"
+	VectorSearchHybrid      = "HYBRID"
 )
 
 // EmbeddingConfig is a configuration struct for embedding module.
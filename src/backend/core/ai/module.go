@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"cognix.ch/api/v2/core/repository"
 	"cognix.ch/api/v2/core/storage"
 	"cognix.ch/api/v2/core/utils"
 	"go.uber.org/fx"
@@ -11,6 +12,7 @@ var EmbeddingModule = fx.Options(
 		cfg := SearcherConfig{
 			InternalSearcher: &EmbeddingConfig{},
 			GRPCSearcher:     &VectorSearchConfig{},
+			Reranker:         &RerankerConfig{},
 		}
 		if err := utils.ReadConfig(&cfg); err != nil {
 			return nil, err
@@ -20,6 +22,7 @@ var EmbeddingModule = fx.Options(
 		newInternal,
 		newGrpcEmbedder,
 		newSearcher),
+	fx.Invoke(PreloadEncodings),
 )
 
 func newInternal(cfg *SearcherConfig) *EmbeddingBuilder {
@@ -32,6 +35,18 @@ func newGrpcEmbedder(cfg *SearcherConfig) *GRPCEmbeddingBuilder {
 func newSearcher(cfg *SearcherConfig,
 	internalBuilder *EmbeddingBuilder,
 	vectorDBClinet storage.VectorDBClient,
-	grpcBuilder *GRPCEmbeddingBuilder) (Searcher, error) {
-	return NewSearcher(cfg.ApiVectorSearch, internalBuilder, vectorDBClinet, grpcBuilder)
+	grpcBuilder *GRPCEmbeddingBuilder,
+	chunkRepo repository.DocumentChunkRepository) (Searcher, error) {
+	searcher, err := NewSearcher(cfg.ApiVectorSearch, internalBuilder, vectorDBClinet, grpcBuilder, chunkRepo)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Reranker.Reranker == RerankerNone {
+		return searcher, nil
+	}
+	reranker, err := NewReranker(cfg.Reranker, internalBuilder)
+	if err != nil {
+		return nil, err
+	}
+	return NewRerankingSearcher(searcher, reranker, cfg.Reranker.OverFetchFactor), nil
 }
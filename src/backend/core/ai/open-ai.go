@@ -1,8 +1,14 @@
 package ai
 
 import (
+	"cognix.ch/api/v2/core/model"
 	"context"
+	"errors"
+	"fmt"
 	openai "github.com/sashabaranov/go-openai"
+	"io"
+	"math"
+	"strings"
 )
 
 type (
@@ -12,10 +18,27 @@ type (
 		Message string
 	}
 
+	// StreamCallback is called for every chunk of the answer received from a streaming request.
+	StreamCallback func(delta string) error
+
+	// GenerationSettings are sampling parameters of the request.
+	// Nil settings, nil pointers and empty values use defaults of the provider.
+	GenerationSettings struct {
+		Temperature    *float32
+		TopP           *float32
+		MaxTokens      int
+		Stop           []string
+		ResponseFormat string
+	}
+
 	// Client is an interface for making requests to the OpenAI chat API.
-	// The Request method takes a context and a message as input and returns a Response or an error.
+	// The Request method takes a context, role-tagged messages of the conversation and generation settings as input
+	// and returns a Response or an error.
+	// The RequestStream method requests the answer in streaming mode, calls the callback
+	// for every received chunk and returns the whole answer when the stream ends.
 	Client interface {
-		Request(ctx context.Context, message string) (*Response, error)
+		Request(ctx context.Context, messages []*ChatMessage, settings *GenerationSettings) (*Response, error)
+		RequestStream(ctx context.Context, messages []*ChatMessage, settings *GenerationSettings, callback StreamCallback) (*Response, error)
 	}
 
 	// openAIClient is a struct that represents the client for making requests to the OpenAI chat API.
@@ -30,23 +53,16 @@ type (
 // It takes a context.Context parameter and a string message parameter.
 // It returns a *Response and an error.
 //
-// The method first creates a ChatCompletionMessage using the user's message.
+// The method first converts the conversation messages into ChatCompletionMessages.
 // Then it calls the client's CreateChatCompletion method to make the API request.
 // If there is an error, it returns nil and the error.
 // If the API request is successful, it creates a Response with the content of the first message choice
 // and returns it along with nil for the error.
-func (o *openAIClient) Request(ctx context.Context, message string) (*Response, error) {
+func (o *openAIClient) Request(ctx context.Context, messages []*ChatMessage, settings *GenerationSettings) (*Response, error) {
 
-	userMessage := openai.ChatCompletionMessage{
-		Role:    openai.ChatMessageRoleUser,
-		Content: message,
-	}
 	resp, err := o.client.CreateChatCompletion(
 		context.Background(),
-		openai.ChatCompletionRequest{
-			Model:    o.modelID,
-			Messages: []openai.ChatCompletionMessage{userMessage},
-		},
+		o.completionRequest(messages, settings),
 	)
 	if err != nil {
 		return nil, err
@@ -55,6 +71,101 @@ func (o *openAIClient) Request(ctx context.Context, message string) (*Response,
 	return response, nil
 }
 
+// RequestStream is a method of the openAIClient struct that makes a streaming request to the OpenAI chat API.
+// Every received chunk is passed to the callback. The accumulated answer is returned when the stream ends,
+// an empty answer is an error like in ChatAI.
+func (o *openAIClient) RequestStream(ctx context.Context, messages []*ChatMessage, settings *GenerationSettings, callback StreamCallback) (*Response, error) {
+	request := o.completionRequest(messages, settings)
+	request.Stream = true
+	stream, err := o.client.CreateChatCompletionStream(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var answer strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+		answer.WriteString(chunk.Choices[0].Delta.Content)
+		if err = callback(chunk.Choices[0].Delta.Content); err != nil {
+			return nil, err
+		}
+	}
+	if answer.Len() == 0 {
+		return nil, fmt.Errorf("no response from ai")
+	}
+	return &Response{Message: answer.String()}, nil
+}
+
+// completionRequest builds the chat completion request with the generation settings.
+// The client omits zero temperature and top_p, so the smallest positive value is sent instead of zero.
+func (o *openAIClient) completionRequest(messages []*ChatMessage, settings *GenerationSettings) openai.ChatCompletionRequest {
+	request := openai.ChatCompletionRequest{
+		Model:    o.modelID,
+		Messages: o.completionMessages(messages),
+	}
+	if settings == nil {
+		return request
+	}
+	if settings.Temperature != nil {
+		request.Temperature = nonZero(*settings.Temperature)
+	}
+	if settings.TopP != nil {
+		request.TopP = nonZero(*settings.TopP)
+	}
+	request.MaxTokens = settings.MaxTokens
+	request.Stop = settings.Stop
+	if settings.ResponseFormat != "" {
+		request.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatType(settings.ResponseFormat),
+		}
+	}
+	return request
+}
+
+// nonZero returns the smallest positive float32 for zero.
+func nonZero(value float32) float32 {
+	if value == 0 {
+		return math.SmallestNonzeroFloat32
+	}
+	return value
+}
+
+// NewGenerationSettings returns generation settings of the LLM.
+func NewGenerationSettings(llm *model.LLM) *GenerationSettings {
+	if llm == nil {
+		return nil
+	}
+	return &GenerationSettings{
+		Temperature:    llm.Temperature,
+		TopP:           llm.TopP,
+		MaxTokens:      llm.MaxTokens,
+		Stop:           llm.Stop,
+		ResponseFormat: llm.ResponseFormat,
+	}
+}
+
+// completionMessages converts conversation messages into the OpenAI chat format.
+func (o *openAIClient) completionMessages(messages []*ChatMessage) []openai.ChatCompletionMessage {
+	result := make([]openai.ChatCompletionMessage, 0, len(messages))
+	for _, message := range messages {
+		result = append(result, openai.ChatCompletionMessage{
+			Role:    message.Role,
+			Content: message.Content,
+		})
+	}
+	return result
+}
+
 // NewOpenAIClient is a function that creates a new instance of the Client.
 // It takes the modelID and apiKey as input parameters and returns an instance of Client.
 // The function creates a new openaIClient struct with the provided modelID and apiKey.
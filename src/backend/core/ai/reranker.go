@@ -0,0 +1,141 @@
+package ai
+
+import (
+	"cognix.ch/api/v2/core/proto"
+	"cognix.ch/api/v2/core/storage"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	RerankerNone = "NONE"
+	RerankerGRPC = "GRPC-SERVICE"
+)
+
+type (
+	// RerankerConfig is a configuration struct for the reranking of search results.
+	//   - Reranker: type of the reranker, NONE disables reranking.
+	//   - Model: cross-encoder model used by the rerank service.
+	//   - OverFetchFactor: how many times more chunks are retrieved than returned after reranking.
+	RerankerConfig struct {
+		Reranker        string `env:"RERANKER" envDefault:"NONE"`
+		Model           string `env:"RERANKER_MODEL" envDefault:"cross-encoder/ms-marco-MiniLM-L-6-v2"`
+		OverFetchFactor int    `env:"RERANKER_OVER_FETCH_FACTOR" envDefault:"3"`
+	}
+
+	// Reranker is an interface that orders search results by relevance to the query.
+	Reranker interface {
+		Rerank(ctx context.Context, query string, documents []*SearcherResponse) ([]*SearcherResponse, error)
+	}
+
+	// nopReranker keeps the order of search results.
+	nopReranker struct{}
+
+	// GRPCReranker orders search results with the cross-encoder of the embedder service.
+	GRPCReranker struct {
+		embeddBuilder *EmbeddingBuilder
+		model         string
+	}
+
+	// RerankingSearcher is a Searcher that over-fetches results of the underlying searcher,
+	// reranks them and returns the most relevant ones.
+	RerankingSearcher struct {
+		searcher        Searcher
+		reranker        Reranker
+		overFetchFactor int
+	}
+)
+
+// NewReranker creates a new Reranker based on the configured reranker type.
+func NewReranker(cfg *RerankerConfig, embeddBuilder *EmbeddingBuilder) (Reranker, error) {
+	switch cfg.Reranker {
+	case RerankerNone, "":
+		return &nopReranker{}, nil
+	case RerankerGRPC:
+		return &GRPCReranker{
+			embeddBuilder: embeddBuilder,
+			model:         cfg.Model,
+		}, nil
+	}
+	return nil, fmt.Errorf("reranker %s not implemented", cfg.Reranker)
+}
+
+// NewRerankingSearcher wraps the searcher with the reranker.
+func NewRerankingSearcher(searcher Searcher, reranker Reranker, overFetchFactor int) Searcher {
+	if overFetchFactor < 1 {
+		overFetchFactor = 1
+	}
+	return &RerankingSearcher{
+		searcher:        searcher,
+		reranker:        reranker,
+		overFetchFactor: overFetchFactor,
+	}
+}
+
+// Rerank returns documents as is.
+func (n *nopReranker) Rerank(ctx context.Context, query string, documents []*SearcherResponse) ([]*SearcherResponse, error) {
+	return documents, nil
+}
+
+// Rerank sends the query and contents of documents to the rerank service
+// and returns documents ordered by the relevance score.
+func (g *GRPCReranker) Rerank(ctx context.Context, query string, documents []*SearcherResponse) ([]*SearcherResponse, error) {
+	if len(documents) == 0 {
+		return documents, nil
+	}
+	client, err := g.embeddBuilder.Client()
+	if err != nil {
+		return nil, err
+	}
+	contents := make([]string, 0, len(documents))
+	for _, doc := range documents {
+		contents = append(contents, doc.Content)
+	}
+	response, err := client.Rerank(ctx, &proto.RerankRequest{
+		Query:    query,
+		Contents: contents,
+		Model:    g.model,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*SearcherResponse, 0, len(documents))
+	for _, item := range response.GetResults() {
+		if item.GetIndex() < 0 || int(item.GetIndex()) >= len(documents) {
+			continue
+		}
+		result = append(result, documents[item.GetIndex()])
+	}
+	return result, nil
+}
+
+// FindDocuments searches for documents with the underlying searcher and reranks results.
+// The underlying searcher fetches top k multiplied by the over-fetch factor candidates,
+// only top k of reranked results are returned. If reranking fails, results are returned in the original order.
+func (r *RerankingSearcher) FindDocuments(ctx context.Context, userID, tenantID uuid.UUID,
+	embeddingModel string,
+	message string, params *storage.SearchParams, collectionNames ...string) ([]*SearcherResponse, error) {
+	limit := params.Limit()
+	overFetchParams := &storage.SearchParams{
+		TopK: limit * r.overFetchFactor,
+	}
+	if params != nil {
+		overFetchParams.ScoreThreshold = params.ScoreThreshold
+		overFetchParams.Filters = params.Filters
+	}
+	documents, err := r.searcher.FindDocuments(ctx, userID, tenantID, embeddingModel, message, overFetchParams, collectionNames...)
+	if err != nil {
+		return nil, err
+	}
+	reranked, err := r.reranker.Rerank(ctx, message, documents)
+	if err != nil {
+		zap.S().Errorf("rerank documents %s ", err.Error())
+		reranked = documents
+	}
+	if len(reranked) > limit {
+		reranked = reranked[:limit]
+	}
+	return reranked, nil
+}
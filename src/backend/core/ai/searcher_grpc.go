@@ -2,6 +2,7 @@ package ai
 
 import (
 	"cognix.ch/api/v2/core/proto"
+	"cognix.ch/api/v2/core/storage"
 	"context"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -23,6 +24,7 @@ type SearcherGRPC struct {
 //   - tenantID: The ID of the tenant.
 //   - embeddingModel: The embedding model to use for the search.
 //   - message: The message to search for.
+//   - params: The number of results, the score threshold and metadata filters of the search.
 //   - collectionNames: The optional collection names to search within.
 //
 // Returns:
@@ -30,18 +32,29 @@ type SearcherGRPC struct {
 //   - error: An error if any occurred during the search.
 func (i *SearcherGRPC) FindDocuments(ctx context.Context, userID, tenantID uuid.UUID,
 	embeddingModel string,
-	message string, collectionNames ...string) ([]*SearcherResponse, error) {
+	message string, params *storage.SearchParams, collectionNames ...string) ([]*SearcherResponse, error) {
 	embedding, err := i.embeddBuilder.Client()
 	if err != nil {
 		return nil, err
 	}
-	response, err := embedding.VectorSearch(ctx, &proto.SearchRequest{
+	request := &proto.SearchRequest{
 		Content:         message,
 		UserId:          userID.String(),
 		TenantId:        tenantID.String(),
 		CollectionNames: collectionNames,
-		ModelName: embeddingModel,
-	})
+		ModelName:       embeddingModel,
+		TopK:            int32(params.Limit()),
+	}
+	if params != nil {
+		request.ScoreThreshold = params.ScoreThreshold
+		for _, filter := range params.Filters {
+			request.Filters = append(request.Filters, &proto.SearchFilter{
+				Field:  filter.Field,
+				Values: filter.Values,
+			})
+		}
+	}
+	response, err := embedding.VectorSearch(ctx, request)
 	if err != nil {
 		zap.S().Errorf("embeding service %s ", err.Error())
 		return nil, err
@@ -52,6 +65,7 @@ func (i *SearcherGRPC) FindDocuments(ctx context.Context, userID, tenantID uuid.
 		resDocument := &SearcherResponse{
 			DocumentID: doc.GetDocumentId(),
 			Content:    doc.GetContent(),
+			Score:      doc.GetScore(),
 		}
 		result = append(result, resDocument)
 	}
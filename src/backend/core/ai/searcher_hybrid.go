@@ -0,0 +1,92 @@
+package ai
+
+import (
+	"cognix.ch/api/v2/core/repository"
+	"cognix.ch/api/v2/core/storage"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"sort"
+)
+
+const (
+	// rrfK is a constant of the reciprocal rank fusion that reduces the impact of the top ranked results.
+	rrfK = 60
+)
+
+// HybridSearcher is a searcher that combines the vector search with the full-text search over chunks
+// of documents. Results of both searches are fused with reciprocal rank fusion.
+type HybridSearcher struct {
+	vectorSearcher *InternalSearcher
+	chunkRepo      repository.DocumentChunkRepository
+}
+
+// FindDocuments searches for documents with the vector and the full-text search
+// and returns results ordered by the reciprocal rank fusion score.
+// The full-text search returns at most top k chunks of params, the connector id filter is applied to it as well.
+// The score threshold of params applies to the vector search only. The full-text rank is not comparable
+// with the vector metric, so chunks found only by the full-text search have Score 0 and are kept
+// regardless of the threshold. At most top k fused results are returned.
+// If one of the searches fails, results of the other one are returned.
+func (h *HybridSearcher) FindDocuments(ctx context.Context, userID, tenantID uuid.UUID,
+	embeddingModel string,
+	message string, params *storage.SearchParams, collectionNames ...string) ([]*SearcherResponse, error) {
+	vectorResult, vectorErr := h.vectorSearcher.FindDocuments(ctx, userID, tenantID, embeddingModel, message, params, collectionNames...)
+	if vectorErr != nil {
+		zap.S().Errorf("vector search %s ", vectorErr.Error())
+	}
+	var keywordResult []*SearcherResponse
+	var connectorIDs []int64
+	if values, ok := params.FilterValues(storage.ColumnNameConnectorID); ok {
+		connectorIDs = append(make([]int64, 0, len(values)), values...)
+	}
+	chunks, keywordErr := h.chunkRepo.Search(ctx, message, params.Limit(), connectorIDs, collectionNames...)
+	if keywordErr != nil {
+		zap.S().Errorf("keyword search %s ", keywordErr.Error())
+	}
+	for _, chunk := range chunks {
+		keywordResult = append(keywordResult, &SearcherResponse{
+			DocumentID: chunk.DocumentID.IntPart(),
+			Content:    chunk.Content,
+		})
+	}
+	if vectorErr != nil && keywordErr != nil {
+		return nil, vectorErr
+	}
+	return fuseRanks(params.Limit(), vectorResult, keywordResult), nil
+}
+
+// fuseRanks merges ranked lists of search results with reciprocal rank fusion and returns at most limit results.
+// The same chunk found by several searches is returned once with the sum of its scores.
+func fuseRanks(limit int, rankedLists ...[]*SearcherResponse) []*SearcherResponse {
+	type fusedResponse struct {
+		response *SearcherResponse
+		score    float64
+	}
+	fused := make(map[string]*fusedResponse)
+	var ranked []*fusedResponse
+	for _, list := range rankedLists {
+		for rank, response := range list {
+			key := fmt.Sprintf("%d:%s", response.DocumentID, response.Content)
+			entry, ok := fused[key]
+			if !ok {
+				entry = &fusedResponse{response: response}
+				fused[key] = entry
+				ranked = append(ranked, entry)
+			}
+			entry.score += 1 / float64(rrfK+rank+1)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score > ranked[j].score
+	})
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	result := make([]*SearcherResponse, 0, len(ranked))
+	for _, entry := range ranked {
+		result = append(result, entry.response)
+	}
+	return result
+}
@@ -0,0 +1,34 @@
+package ai
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFuseRanks(t *testing.T) {
+	vectorResult := []*SearcherResponse{
+		{DocumentID: 1, Content: "first"},
+		{DocumentID: 2, Content: "second"},
+		{DocumentID: 3, Content: "third"},
+	}
+	keywordResult := []*SearcherResponse{
+		{DocumentID: 3, Content: "third"},
+		{DocumentID: 4, Content: "fourth"},
+	}
+	result := fuseRanks(10, vectorResult, keywordResult)
+	if assert.Len(t, result, 4) {
+		// found by both searches
+		assert.Equal(t, int64(3), result[0].DocumentID)
+		assert.Equal(t, int64(1), result[1].DocumentID)
+		// equal scores keep the order of the first appearance
+		assert.Equal(t, int64(2), result[2].DocumentID)
+		assert.Equal(t, int64(4), result[3].DocumentID)
+	}
+
+	// results are truncated to the limit
+	result = fuseRanks(2, vectorResult, keywordResult)
+	if assert.Len(t, result, 2) {
+		assert.Equal(t, int64(3), result[0].DocumentID)
+		assert.Equal(t, int64(1), result[1].DocumentID)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"sort"
 )
 
 type InternalSearcher struct {
@@ -15,7 +16,7 @@ type InternalSearcher struct {
 
 func (i *InternalSearcher) FindDocuments(ctx context.Context, userID, tenantID uuid.UUID,
 	embeddingModel string,
-	message string, collectionNames ...string) ([]*SearcherResponse, error) {
+	message string, params *storage.SearchParams, collectionNames ...string) ([]*SearcherResponse, error) {
 	embedding, err := i.embeddBuilder.Client()
 	if err != nil {
 		return nil, err
@@ -34,7 +35,7 @@ func (i *InternalSearcher) FindDocuments(ctx context.Context, userID, tenantID u
 		if response.GetEmbeddings() == nil || len(response.GetEmbeddings()) == 0 {
 			continue
 		}
-		docs, err := i.vectorDB.Load(ctx, collectionName, response.GetEmbeddings()[0].GetVector())
+		docs, err := i.vectorDB.Load(ctx, collectionName, response.GetEmbeddings()[0].GetVector(), params)
 		if err != nil {
 			zap.S().Errorf("error loading document from vector database :%s", err.Error())
 			continue
@@ -43,9 +44,17 @@ func (i *InternalSearcher) FindDocuments(ctx context.Context, userID, tenantID u
 			resDocument := &SearcherResponse{
 				DocumentID: doc.DocumentID,
 				Content:    doc.Content,
+				Score:      doc.Score,
 			}
 			result = append(result, resDocument)
 		}
 	}
+	// every collection returns its own top results, keep the best of all of them
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	if limit := params.Limit(); len(result) > limit {
+		result = result[:limit]
+	}
 	return result, nil
 }
@@ -0,0 +1,57 @@
+package ai
+
+import (
+	"cognix.ch/api/v2/core/proto"
+	"cognix.ch/api/v2/core/storage"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"testing"
+)
+
+type embedClientStub struct {
+	proto.EmbedServiceClient
+}
+
+func (e *embedClientStub) GetEmbedding(ctx context.Context, in *proto.EmbedRequest, opts ...grpc.CallOption) (*proto.EmbedResponse, error) {
+	return &proto.EmbedResponse{
+		Embeddings: []*proto.EmbedResponseItem{{Vector: []float32{0.1, 0.2}}},
+	}, nil
+}
+
+type vectorDBStub struct {
+	storage.VectorDBClient
+	collections map[string][]*storage.MilvusPayload
+}
+
+func (v *vectorDBStub) Load(ctx context.Context, collection string, vector []float32, params *storage.SearchParams) ([]*storage.MilvusPayload, error) {
+	return v.collections[collection], nil
+}
+
+func TestInternalSearcherFindDocuments(t *testing.T) {
+	searcher := &InternalSearcher{
+		embeddBuilder: &EmbeddingBuilder{client: &embedClientStub{}},
+		vectorDB: &vectorDBStub{collections: map[string][]*storage.MilvusPayload{
+			"tenant": {
+				{DocumentID: 1, Score: 0.9},
+				{DocumentID: 2, Score: 0.5},
+				{DocumentID: 3, Score: 0.3},
+			},
+			"user": {
+				{DocumentID: 4, Score: 0.8},
+				{DocumentID: 5, Score: 0.4},
+			},
+		}},
+	}
+
+	result, err := searcher.FindDocuments(context.Background(), uuid.New(), uuid.New(), "model", "message",
+		&storage.SearchParams{TopK: 3}, "tenant", "user")
+	assert.NoError(t, err)
+	// results of both collections are merged by score and truncated to the limit
+	if assert.Len(t, result, 3) {
+		assert.Equal(t, int64(1), result[0].DocumentID)
+		assert.Equal(t, int64(4), result[1].DocumentID)
+		assert.Equal(t, int64(2), result[2].DocumentID)
+	}
+}
@@ -0,0 +1,217 @@
+package ai
+
+import (
+	"github.com/pkoukk/tiktoken-go"
+	"go.uber.org/zap"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const (
+	// DefaultContextSize is the context window of models that are not known to the tokenizer.
+	DefaultContextSize = 4096
+	// lettersPerToken is an average number of latin letters in one token of BPE vocabularies.
+	lettersPerToken = 5
+	// digitsPerToken is the number of digits encoded in one token of BPE vocabularies.
+	digitsPerToken = 3
+	// encodingRetryInterval is the time after which an encoding that could not be loaded is loaded again.
+	encodingRetryInterval = 5 * time.Minute
+)
+
+// contextSizes contains context windows of well-known models. Model ids are matched by prefix,
+// the longest prefix wins.
+var contextSizes = map[string]int{
+	"gpt-3.5-turbo":          16385,
+	"gpt-3.5-turbo-instruct": 4096,
+	"gpt-4":                  8192,
+	"gpt-4-32k":              32768,
+	"gpt-4-turbo":            128000,
+	"gpt-4-1106":             128000,
+	"gpt-4-0125":             128000,
+	"gpt-4o":                 128000,
+	"mistral":                32768,
+	"mixtral":                32768,
+	"llama3":                 8192,
+	"llama-3":                8192,
+}
+
+// modelEncodings contains BPE encodings of OpenAI models. Model ids are matched by prefix,
+// the longest prefix wins.
+var modelEncodings = map[string]string{
+	"gpt-3.5-turbo":          tiktoken.MODEL_CL100K_BASE,
+	"gpt-4":                  tiktoken.MODEL_CL100K_BASE,
+	"gpt-4o":                 tiktoken.MODEL_O200K_BASE,
+	"text-embedding-ada-002": tiktoken.MODEL_CL100K_BASE,
+	"text-embedding-3":       tiktoken.MODEL_CL100K_BASE,
+}
+
+// encodingEntry is the state of a BPE encoding, the encoding is nil until its ranks are loaded.
+type encodingEntry struct {
+	encoding *tiktoken.Tiktoken
+	loading  bool
+	failedAt time.Time
+}
+
+// encodings caches BPE encodings by name. Ranks are loaded in background, the lock is never held
+// while they are downloaded.
+var encodings = struct {
+	sync.Mutex
+	entries map[string]*encodingEntry
+}{entries: make(map[string]*encodingEntry)}
+
+// Tokenizer counts tokens of texts sent to the LLM.
+type Tokenizer interface {
+	CountTokens(text string) int
+	// CountMessages returns the number of tokens of chat messages including
+	// the overhead added by the chat format to every message.
+	CountMessages(messages ...*ChatMessage) int
+}
+
+// bpeTokenizer approximates tokenizers of BPE vocabularies used by chat models.
+// Words are split into tokens of lettersPerToken characters, numbers into tokens of digitsPerToken digits,
+// every punctuation character and every non-latin character is a separate token.
+type bpeTokenizer struct {
+	messageOverhead int
+}
+
+// tiktokenTokenizer counts tokens with the BPE encoding of the model.
+type tiktokenTokenizer struct {
+	encoding        *tiktoken.Tiktoken
+	messageOverhead int
+}
+
+// NewTokenizer creates a tokenizer for the model. OpenAI models use their BPE encoding,
+// other models and models whose encoding can not be loaded use the approximation of bpeTokenizer.
+// Ranks of encodings are downloaded once and cached in TIKTOKEN_CACHE_DIR, the approximation is used
+// until the encoding is loaded.
+func NewTokenizer(modelID string) Tokenizer {
+	if name := encodingName(modelID); name != "" {
+		if encoding := loadEncoding(name); encoding != nil {
+			return &tiktokenTokenizer{encoding: encoding, messageOverhead: 4}
+		}
+	}
+	return &bpeTokenizer{messageOverhead: 4}
+}
+
+// encodingName returns the name of the BPE encoding of the model, empty if the model is not known.
+func encodingName(modelID string) string {
+	modelID = strings.ToLower(modelID)
+	name, prefixLen := "", 0
+	for prefix, encoding := range modelEncodings {
+		if strings.HasPrefix(modelID, prefix) && len(prefix) > prefixLen {
+			name, prefixLen = encoding, len(prefix)
+		}
+	}
+	return name
+}
+
+// PreloadEncodings starts loading encodings of known models, so tokens of the first chats are counted exactly.
+func PreloadEncodings() {
+	for _, name := range modelEncodings {
+		loadEncoding(name)
+	}
+}
+
+// loadEncoding returns the cached encoding or nil if the encoding is not loaded yet.
+// The first call starts loading the encoding in background, an encoding that could not be loaded
+// is loaded again after encodingRetryInterval.
+func loadEncoding(name string) *tiktoken.Tiktoken {
+	encodings.Lock()
+	defer encodings.Unlock()
+	entry, ok := encodings.entries[name]
+	if !ok {
+		entry = &encodingEntry{}
+		encodings.entries[name] = entry
+	}
+	if entry.encoding == nil && !entry.loading && time.Since(entry.failedAt) >= encodingRetryInterval {
+		entry.loading = true
+		go fetchEncoding(name, entry)
+	}
+	return entry.encoding
+}
+
+// fetchEncoding loads ranks of the encoding and stores the result in the entry.
+func fetchEncoding(name string, entry *encodingEntry) {
+	encoding, err := tiktoken.GetEncoding(name)
+	encodings.Lock()
+	defer encodings.Unlock()
+	entry.loading = false
+	if err != nil {
+		zap.S().Errorf("load encoding %s, tokens are approximated : %s", name, err.Error())
+		entry.failedAt = time.Now()
+		return
+	}
+	entry.encoding = encoding
+}
+
+// CountTokens returns the number of tokens in the text.
+func (t *tiktokenTokenizer) CountTokens(text string) int {
+	return len(t.encoding.EncodeOrdinary(text))
+}
+
+// CountMessages returns the number of tokens of chat messages.
+func (t *tiktokenTokenizer) CountMessages(messages ...*ChatMessage) int {
+	tokens := 0
+	for _, message := range messages {
+		tokens += t.messageOverhead + t.CountTokens(message.Role) + t.CountTokens(message.Content)
+	}
+	return tokens
+}
+
+// ContextSize returns the context window of the model.
+// The size configured for the model takes precedence over the size of the well-known model.
+func ContextSize(modelID string, configured int) int {
+	if configured > 0 {
+		return configured
+	}
+	modelID = strings.ToLower(modelID)
+	size, prefixLen := DefaultContextSize, 0
+	for prefix, s := range contextSizes {
+		if strings.HasPrefix(modelID, prefix) && len(prefix) > prefixLen {
+			size, prefixLen = s, len(prefix)
+		}
+	}
+	return size
+}
+
+// CountTokens returns the number of tokens in the text.
+func (t *bpeTokenizer) CountTokens(text string) int {
+	tokens := 0
+	letters, digits := 0, 0
+	flush := func() {
+		tokens += (letters+lettersPerToken-1)/lettersPerToken + (digits+digitsPerToken-1)/digitsPerToken
+		letters, digits = 0, 0
+	}
+	for _, r := range text {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || r == '\''):
+			if digits > 0 {
+				flush()
+			}
+			letters++
+		case unicode.IsDigit(r):
+			if letters > 0 {
+				flush()
+			}
+			digits++
+		case unicode.IsSpace(r):
+			flush()
+		default:
+			flush()
+			tokens++
+		}
+	}
+	flush()
+	return tokens
+}
+
+// CountMessages returns the number of tokens of chat messages.
+func (t *bpeTokenizer) CountMessages(messages ...*ChatMessage) int {
+	tokens := 0
+	for _, message := range messages {
+		tokens += t.messageOverhead + t.CountTokens(message.Role) + t.CountTokens(message.Content)
+	}
+	return tokens
+}
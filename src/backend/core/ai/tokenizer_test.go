@@ -0,0 +1,49 @@
+package ai
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCountTokens(t *testing.T) {
+	tokenizer := NewTokenizer("llama3")
+	assert.Equal(t, 0, tokenizer.CountTokens(""))
+	assert.Equal(t, 2, tokenizer.CountTokens("hello world"))
+	// long words are split into several tokens
+	assert.Equal(t, 3, tokenizer.CountTokens("tokenization"))
+	// punctuation and numbers
+	assert.Equal(t, 4, tokenizer.CountTokens("year 2024!"))
+	assert.Equal(t, 4+tokenizer.CountTokens("user")+2, tokenizer.CountMessages(&ChatMessage{
+		Role:    ChatMessageRoleUser,
+		Content: "hello world",
+	}))
+}
+
+func TestNewTokenizer(t *testing.T) {
+	assert.Equal(t, "cl100k_base", encodingName("gpt-4-turbo"))
+	assert.Equal(t, "o200k_base", encodingName("gpt-4o-mini"))
+	assert.Equal(t, "", encodingName("llama3"))
+
+	// the encoding is loaded in background, the approximation is used meanwhile
+	loadEncoding("cl100k_base")
+	assert.Eventually(t, func() bool {
+		encodings.Lock()
+		defer encodings.Unlock()
+		return !encodings.entries["cl100k_base"].loading
+	}, time.Minute, 10*time.Millisecond)
+	tokenizer, ok := NewTokenizer("gpt-4").(*tiktokenTokenizer)
+	if !ok {
+		t.Skip("cl100k_base encoding can not be loaded")
+	}
+	assert.Equal(t, 2, tokenizer.CountTokens("hello world"))
+	assert.Equal(t, 2, tokenizer.CountTokens("tokenization"))
+}
+
+func TestContextSize(t *testing.T) {
+	assert.Equal(t, 8192, ContextSize("gpt-4", 0))
+	assert.Equal(t, 128000, ContextSize("gpt-4-turbo-preview", 0))
+	assert.Equal(t, 128000, ContextSize("gpt-4o-mini", 0))
+	assert.Equal(t, DefaultContextSize, ContextSize("unknown-model", 0))
+	assert.Equal(t, 1000, ContextSize("gpt-4", 1000))
+}
@@ -51,6 +51,7 @@ type Base struct {
 // - SearchForSitemap: a boolean indicating whether to search for a sitemap.
 // - Name: a string representing the name.
 // - SourceID: a string representing the source ID.
+// - ParentSourceID: a string representing the source ID of the parent document, if any.
 // - DocumentID: an int64 representing the document ID.
 // - MimeType: a string representing the mime type.
 // - FileType: a proto.FileType representing the file type.
@@ -62,6 +63,7 @@ type Response struct {
 	SearchForSitemap bool
 	Name             string
 	SourceID         string
+	ParentSourceID   string
 	DocumentID       int64
 	MimeType         string
 	FileType         proto.FileType
@@ -109,6 +111,8 @@ func New(connectorModel *model.Connector,
 		return NewWeb(connectorModel)
 	case model.SourceTypeOneDrive:
 		return NewOneDrive(connectorModel, connectorRepo, oauthURL)
+	case model.SourceTypeSharepoint:
+		return NewSharePoint(connectorModel, connectorRepo, oauthURL)
 	case model.SourceTypeFile:
 		return NewFile(connectorModel)
 	case model.SourceTypeYoutube:
@@ -117,6 +121,22 @@ func New(connectorModel *model.Connector,
 		return NewMSTeams(connectorModel, connectorRepo, oauthURL)
 	case model.SourceTypeGoogleDrive:
 		return NewGoogleDrive(connectorModel, connectorRepo, oauthURL)
+	case model.SourceTypeSlack:
+		return NewSlack(connectorModel, connectorRepo)
+	case model.SourceTypeConfluence:
+		return NewConfluence(connectorModel, connectorRepo)
+	case model.SourceTypeGithub, model.SourceTypeGitlab:
+		return NewGit(connectorModel, connectorRepo)
+	case model.SourceTypeJira:
+		return NewJira(connectorModel, connectorRepo)
+	case model.SourceTypeNotion:
+		return NewNotion(connectorModel, connectorRepo)
+	case model.SourceTypeZendesk:
+		return NewZendesk(connectorModel, connectorRepo)
+	case model.SourceTypeGMAIL:
+		return NewMail(connectorModel, connectorRepo, oauthURL)
+	case model.SourceTypeIngestionApi:
+		return NewIngestionAPI(connectorModel)
 	default:
 		return &nopConnector{}, nil
 	}
@@ -46,6 +46,18 @@ var validationConnectors = []*ValidationTestData{
 		},
 		isValid: false,
 	},
+	{name: "web wrong exclude pattern",
+		connectoModel: &model.Connector{
+			ID:   decimal.NewFromInt(1),
+			Name: "web",
+			Type: model.SourceTypeWEB,
+			ConnectorSpecificConfig: model.JSONMap{
+				"url":              "https://test.url",
+				"exclude_patterns": []string{"("},
+			},
+		},
+		isValid: false,
+	},
 	{name: "file valid",
 		connectoModel: &model.Connector{
 			ID:   decimal.NewFromInt(1),
@@ -107,6 +119,225 @@ var validationConnectors = []*ValidationTestData{
 		},
 		isValid: false,
 	},
+	{name: "slack valid",
+		connectoModel: &model.Connector{
+			ID:   decimal.NewFromInt(1),
+			Name: "slack",
+			Type: model.SourceTypeSlack,
+			ConnectorSpecificConfig: model.JSONMap{
+				"token":    "xoxb-token",
+				"channels": []string{"general"},
+			},
+		},
+		isValid: true,
+	},
+	{name: "slack empty token",
+		connectoModel: &model.Connector{
+			ID:                      decimal.NewFromInt(1),
+			Name:                    "slack",
+			Type:                    model.SourceTypeSlack,
+			ConnectorSpecificConfig: model.JSONMap{},
+		},
+		isValid: false,
+	},
+	{name: "confluence valid",
+		connectoModel: &model.Connector{
+			ID:   decimal.NewFromInt(1),
+			Name: "confluence",
+			Type: model.SourceTypeConfluence,
+			ConnectorSpecificConfig: model.JSONMap{
+				"base_url": "https://test.atlassian.net/wiki",
+				"token":    "token",
+			},
+		},
+		isValid: true,
+	},
+	{name: "confluence wrong url",
+		connectoModel: &model.Connector{
+			ID:   decimal.NewFromInt(1),
+			Name: "confluence",
+			Type: model.SourceTypeConfluence,
+			ConnectorSpecificConfig: model.JSONMap{
+				"base_url": "wrong url",
+				"token":    "token",
+			},
+		},
+		isValid: false,
+	},
+	{name: "github valid",
+		connectoModel: &model.Connector{
+			ID:   decimal.NewFromInt(1),
+			Name: "github",
+			Type: model.SourceTypeGithub,
+			ConnectorSpecificConfig: model.JSONMap{
+				"repository": "owner/repo",
+			},
+		},
+		isValid: true,
+	},
+	{name: "gitlab local clone",
+		connectoModel: &model.Connector{
+			ID:   decimal.NewFromInt(1),
+			Name: "gitlab",
+			Type: model.SourceTypeGitlab,
+			ConnectorSpecificConfig: model.JSONMap{
+				"local_path": "/tmp/repo",
+			},
+		},
+		isValid: true,
+	},
+	{name: "gitlab branch as option",
+		connectoModel: &model.Connector{
+			ID:   decimal.NewFromInt(1),
+			Name: "gitlab",
+			Type: model.SourceTypeGitlab,
+			ConnectorSpecificConfig: model.JSONMap{
+				"local_path": "/tmp/repo",
+				"branch":     "--output=/tmp/file",
+			},
+		},
+		isValid: false,
+	},
+	{name: "gitlab empty repository",
+		connectoModel: &model.Connector{
+			ID:                      decimal.NewFromInt(1),
+			Name:                    "gitlab",
+			Type:                    model.SourceTypeGitlab,
+			ConnectorSpecificConfig: model.JSONMap{},
+		},
+		isValid: false,
+	},
+	{name: "jira valid",
+		connectoModel: &model.Connector{
+			ID:   decimal.NewFromInt(1),
+			Name: "jira",
+			Type: model.SourceTypeJira,
+			ConnectorSpecificConfig: model.JSONMap{
+				"base_url": "https://test.atlassian.net",
+				"jql":      "project = DOC",
+				"token":    "token",
+			},
+		},
+		isValid: true,
+	},
+	{name: "jira empty jql",
+		connectoModel: &model.Connector{
+			ID:   decimal.NewFromInt(1),
+			Name: "jira",
+			Type: model.SourceTypeJira,
+			ConnectorSpecificConfig: model.JSONMap{
+				"base_url": "https://test.atlassian.net",
+				"token":    "token",
+			},
+		},
+		isValid: false,
+	},
+	{name: "notion valid",
+		connectoModel: &model.Connector{
+			ID:   decimal.NewFromInt(1),
+			Name: "notion",
+			Type: model.SourceTypeNotion,
+			ConnectorSpecificConfig: model.JSONMap{
+				"token": "secret",
+			},
+		},
+		isValid: true,
+	},
+	{name: "notion empty token",
+		connectoModel: &model.Connector{
+			ID:                      decimal.NewFromInt(1),
+			Name:                    "notion",
+			Type:                    model.SourceTypeNotion,
+			ConnectorSpecificConfig: model.JSONMap{},
+		},
+		isValid: false,
+	},
+	{name: "zendesk valid",
+		connectoModel: &model.Connector{
+			ID:   decimal.NewFromInt(1),
+			Name: "zendesk",
+			Type: model.SourceTypeZendesk,
+			ConnectorSpecificConfig: model.JSONMap{
+				"base_url": "https://test.zendesk.com",
+				"email":    "agent@test.com",
+				"token":    "token",
+			},
+		},
+		isValid: true,
+	},
+	{name: "zendesk empty url",
+		connectoModel: &model.Connector{
+			ID:   decimal.NewFromInt(1),
+			Name: "zendesk",
+			Type: model.SourceTypeZendesk,
+			ConnectorSpecificConfig: model.JSONMap{
+				"token": "token",
+			},
+		},
+		isValid: false,
+	},
+	{name: "ingestion api valid",
+		connectoModel: &model.Connector{
+			ID:                      decimal.NewFromInt(1),
+			Name:                    "ingestion api",
+			Type:                    model.SourceTypeIngestionApi,
+			ConnectorSpecificConfig: model.JSONMap{},
+		},
+		isValid: true,
+	},
+	{name: "sharepoint valid",
+		connectoModel: &model.Connector{
+			ID:   decimal.NewFromInt(1),
+			Name: "sharepoint",
+			Type: model.SourceTypeSharepoint,
+			ConnectorSpecificConfig: model.JSONMap{
+				"sites": []string{"https://test.sharepoint.com/sites/intranet"},
+				"token": map[string]interface{}{
+					"access_token":  "access",
+					"refresh_token": "refresh",
+					"token_type":    "Bearer",
+					"expiry":        "2100-01-01T00:00:00Z",
+				},
+			},
+		},
+		isValid: true,
+	},
+	{name: "sharepoint empty token",
+		connectoModel: &model.Connector{
+			ID:   decimal.NewFromInt(1),
+			Name: "sharepoint",
+			Type: model.SourceTypeSharepoint,
+			ConnectorSpecificConfig: model.JSONMap{
+				"sites": []string{"https://test.sharepoint.com/sites/intranet"},
+			},
+		},
+		isValid: false,
+	},
+	{name: "imap valid",
+		connectoModel: &model.Connector{
+			ID:   decimal.NewFromInt(1),
+			Name: "mail",
+			Type: model.SourceTypeGMAIL,
+			ConnectorSpecificConfig: model.JSONMap{
+				"host":     "imap.test.com",
+				"username": "user",
+				"password": "password",
+			},
+		},
+		isValid: true,
+	},
+	{name: "imap empty password",
+		connectoModel: &model.Connector{
+			ID:   decimal.NewFromInt(1),
+			Name: "mail",
+			Type: model.SourceTypeGMAIL,
+			ConnectorSpecificConfig: model.JSONMap{
+				"host":     "imap.test.com",
+				"username": "user",
+			},
+		},
+		isValid: false,
+	},
 }
 
 func TestParameter_Validation(t *testing.T) {
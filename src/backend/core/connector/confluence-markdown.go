@@ -0,0 +1,233 @@
+package connector
+
+import (
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownSpaces   = regexp.MustCompile(`[ \t\r\n]+`)
+	markdownNewLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// markdownWriter renders the storage format of Confluence pages (XHTML with Confluence macros) as markdown.
+type markdownWriter struct {
+	builder strings.Builder
+	lists   []string
+}
+
+// storageToMarkdown converts the body of the Confluence page in the storage format to markdown.
+func storageToMarkdown(storage string) (string, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(storage), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return "", err
+	}
+	w := &markdownWriter{}
+	for _, node := range nodes {
+		w.render(node)
+	}
+	return w.String(), nil
+}
+
+// String returns the rendered markdown without redundant empty lines.
+func (w *markdownWriter) String() string {
+	return strings.TrimSpace(markdownNewLines.ReplaceAllString(w.builder.String(), "\n\n"))
+}
+
+// render writes the node and its children.
+func (w *markdownWriter) render(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		w.builder.WriteString(markdownSpaces.ReplaceAllString(n.Data, " "))
+		return
+	case html.CommentNode:
+		// macro bodies are stored as CDATA sections that are parsed as comments
+		if strings.HasPrefix(n.Data, "[CDATA[") {
+			w.builder.WriteString(strings.TrimSuffix(strings.TrimPrefix(n.Data, "[CDATA["), "]]"))
+		}
+		return
+	case html.ElementNode:
+	default:
+		w.renderChildren(n)
+		return
+	}
+
+	switch n.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		w.block(strings.Repeat("#", int(n.Data[1]-'0')) + " " + w.inline(n))
+	case "p", "div", "blockquote":
+		if n.Parent != nil && (n.Parent.Data == "li" || n.Parent.Data == "td" || n.Parent.Data == "th") {
+			w.renderChildren(n)
+			return
+		}
+		w.builder.WriteString("\n\n")
+		w.renderChildren(n)
+		w.builder.WriteString("\n\n")
+	case "br":
+		w.builder.WriteString("\n")
+	case "hr":
+		w.block("---")
+	case "strong", "b":
+		w.wrap(n, "**")
+	case "em", "i":
+		w.wrap(n, "_")
+	case "s", "del":
+		w.wrap(n, "~~")
+	case "code":
+		w.wrap(n, "`")
+	case "a":
+		text, href := w.inline(n), attribute(n, "href")
+		if href == "" || text == "" {
+			w.builder.WriteString(text)
+		} else {
+			w.builder.WriteString("[" + text + "](" + href + ")")
+		}
+	case "ul", "ol":
+		w.lists = append(w.lists, n.Data)
+		w.renderChildren(n)
+		w.lists = w.lists[:len(w.lists)-1]
+		w.builder.WriteString("\n\n")
+	case "li":
+		marker := "- "
+		if len(w.lists) > 0 && w.lists[len(w.lists)-1] == "ol" {
+			marker = "1. "
+		}
+		w.builder.WriteString("\n" + strings.Repeat("  ", max(len(w.lists)-1, 0)) + marker)
+		w.renderChildren(n)
+	case "pre":
+		w.block("```\n" + strings.TrimSpace(w.text(n)) + "\n```")
+	case "table":
+		w.table(n)
+	case "ac:structured-macro":
+		if attribute(n, "ac:name") == "code" {
+			w.block("```" + macroParameter(n, "language") + "\n" + strings.TrimSpace(w.text(n)) + "\n```")
+			return
+		}
+		w.renderChildren(n)
+	case "ac:parameter", "ri:attachment", "ac:image":
+		// macro parameters and images do not contain text of the page
+	case "ac:link":
+		if text := w.inline(n); text != "" {
+			w.builder.WriteString(text)
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.Data == "ri:page" {
+				w.builder.WriteString(attribute(c, "ri:content-title"))
+			}
+		}
+	default:
+		w.renderChildren(n)
+	}
+}
+
+// renderChildren writes children of the node.
+func (w *markdownWriter) renderChildren(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.render(c)
+	}
+}
+
+// block writes the text as a separate block.
+func (w *markdownWriter) block(text string) {
+	w.builder.WriteString("\n\n" + text + "\n\n")
+}
+
+// wrap writes the inline text of the node between markers.
+func (w *markdownWriter) wrap(n *html.Node, marker string) {
+	if text := w.inline(n); text != "" {
+		w.builder.WriteString(marker + text + marker)
+	}
+}
+
+// inline returns the markdown of the children of the node on a single line.
+func (w *markdownWriter) inline(n *html.Node) string {
+	child := &markdownWriter{lists: w.lists}
+	child.renderChildren(n)
+	return strings.TrimSpace(markdownSpaces.ReplaceAllString(child.builder.String(), " "))
+}
+
+// text returns the raw text of the node with whitespaces preserved.
+func (w *markdownWriter) text(n *html.Node) string {
+	var builder strings.Builder
+	var collect func(n *html.Node)
+	collect = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			builder.WriteString(n.Data)
+		case n.Type == html.CommentNode && strings.HasPrefix(n.Data, "[CDATA["):
+			builder.WriteString(strings.TrimSuffix(strings.TrimPrefix(n.Data, "[CDATA["), "]]"))
+		case n.Type == html.ElementNode && n.Data == "ac:parameter":
+		case n.Type == html.ElementNode && n.Data == "br":
+			builder.WriteString("\n")
+		default:
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				collect(c)
+			}
+		}
+	}
+	collect(n)
+	return builder.String()
+}
+
+// table writes the table as a markdown table. The first row is used as a header.
+func (w *markdownWriter) table(n *html.Node) {
+	var rows [][]string
+	var collect func(n *html.Node)
+	collect = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			if c.Data != "tr" {
+				collect(c)
+				continue
+			}
+			var cells []string
+			for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+				if cell.Type == html.ElementNode && (cell.Data == "td" || cell.Data == "th") {
+					cells = append(cells, strings.ReplaceAll(w.inline(cell), "|", "\\|"))
+				}
+			}
+			rows = append(rows, cells)
+		}
+	}
+	collect(n)
+	if len(rows) == 0 {
+		return
+	}
+	lines := make([]string, 0, len(rows)+1)
+	for i, cells := range rows {
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", len(cells)))
+		}
+	}
+	w.block(strings.Join(lines, "\n"))
+}
+
+// attribute returns the value of the attribute of the node.
+func attribute(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// macroParameter returns the value of the parameter of the Confluence macro.
+func macroParameter(n *html.Node, name string) string {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == "ac:parameter" && attribute(c, "ac:name") == name && c.FirstChild != nil {
+			return strings.TrimSpace(c.FirstChild.Data)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,342 @@
+package connector
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"cognix.ch/api/v2/core/proto"
+	"cognix.ch/api/v2/core/repository"
+	"cognix.ch/api/v2/core/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/go-pg/pg/v10"
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	confluenceSpaces  = "/rest/api/space"
+	confluencePages   = "/rest/api/content"
+	confluencePage    = "/rest/api/content/%s"
+	confluencePageLen = 50
+)
+
+// Confluence is a struct that represents the Confluence connector.
+//
+// The struct contains the following fields:
+// - Base: a struct that represents the base properties and methods needed for various connectors.
+// - param: a pointer to the ConfluenceParameters struct that contains the connector parameters.
+// - client: a pointer to the resty.Client struct for making requests to the Confluence REST API.
+// - sessionID: a uuid.NullUUID representing the session ID.
+type (
+	Confluence struct {
+		Base
+		param     *ConfluenceParameters
+		client    *resty.Client
+		sessionID uuid.NullUUID
+	}
+	// ConfluenceParameters contains the URL of the Confluence site (https://<site>.atlassian.net/wiki for Cloud),
+	// keys of spaces to analyze (all global spaces if empty) and credentials.
+	// Confluence Cloud uses the email with the API token, Confluence Server uses the personal access token only.
+	ConfluenceParameters struct {
+		BaseURL string            `json:"base_url"`
+		Spaces  model.StringSlice `json:"spaces"`
+		Email   string            `json:"email"`
+		Token   string            `json:"token"`
+	}
+
+	confluenceLinks struct {
+		Base  string `json:"base"`
+		Next  string `json:"next"`
+		WebUI string `json:"webui"`
+	}
+	confluenceSpace struct {
+		Key string `json:"key"`
+	}
+	confluenceSpacesResponse struct {
+		Results []*confluenceSpace `json:"results"`
+		Size    int                `json:"size"`
+		Links   confluenceLinks    `json:"_links"`
+	}
+	confluencePageItem struct {
+		ID      string `json:"id"`
+		Title   string `json:"title"`
+		Version struct {
+			Number int `json:"number"`
+		} `json:"version"`
+		Ancestors []struct {
+			ID string `json:"id"`
+		} `json:"ancestors"`
+		Body struct {
+			Storage struct {
+				Value string `json:"value"`
+			} `json:"storage"`
+		} `json:"body"`
+		Links confluenceLinks `json:"_links"`
+	}
+	confluencePagesResponse struct {
+		Results []*confluencePageItem `json:"results"`
+		Size    int                   `json:"size"`
+		Links   confluenceLinks       `json:"_links"`
+	}
+)
+
+// Validate checks if the ConfluenceParameters struct is valid.
+// It returns an error if the URL of the site or the token is missing.
+func (p ConfluenceParameters) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.BaseURL, validation.Required, is.URL),
+		validation.Field(&p.Token, validation.Required),
+	)
+}
+
+// Validate checks if the Confluence parameter is valid.
+func (c *Confluence) Validate() error {
+	if c.param == nil {
+		return fmt.Errorf("confluence parameter is required")
+	}
+	return c.param.Validate()
+}
+
+// PrepareTask sends the connector request with the session ID to the connector service.
+func (c *Confluence) PrepareTask(ctx context.Context, sessionID uuid.UUID, task Task) error {
+	params := make(map[string]string)
+	params[model.ParamSessionID] = sessionID.String()
+	return task.RunConnector(ctx, &proto.ConnectorRequest{
+		Id:     c.model.ID.IntPart(),
+		Params: params,
+	})
+}
+
+// Execute executes the Confluence connector with the given context and parameters. It returns a channel of Response
+// objects. Documents of pages that no longer exist are not marked as existing, so the executor deletes them.
+func (c *Confluence) Execute(ctx context.Context, param map[string]string) chan *Response {
+	paramSessionID, _ := param[model.ParamSessionID]
+	if uuidSessionID, err := uuid.Parse(paramSessionID); err != nil {
+		c.sessionID = uuid.NullUUID{uuid.New(), true}
+	} else {
+		c.sessionID = uuid.NullUUID{uuidSessionID, true}
+	}
+	go func() {
+		defer close(c.resultCh)
+		if err := c.execute(ctx); err != nil {
+			zap.S().Errorf("execute %s ", err.Error())
+			// the list of pages is incomplete, keep all documents
+			for _, doc := range c.model.DocsMap {
+				doc.IsExists = true
+			}
+		}
+	}()
+	return c.resultCh
+}
+
+// execute walks pages of the spaces. Pages are sent after their ancestors, so the executor
+// stores parent documents first and can link children to them.
+func (c *Confluence) execute(ctx context.Context) error {
+	spaces := c.param.Spaces
+	if len(spaces) == 0 {
+		var err error
+		if spaces, err = c.getSpaces(ctx); err != nil {
+			return err
+		}
+	}
+	var pages []*confluencePageItem
+	for _, space := range spaces {
+		spacePages, err := c.getPages(ctx, space)
+		if err != nil {
+			return err
+		}
+		pages = append(pages, spacePages...)
+	}
+	sort.SliceStable(pages, func(i, j int) bool {
+		return len(pages[i].Ancestors) < len(pages[j].Ancestors)
+	})
+	for _, page := range pages {
+		if err := c.loadPage(ctx, page); err != nil {
+			zap.S().Errorf("error loading page %s : %s ", page.Title, err.Error())
+			// keep the previous version of the page
+			if doc, ok := c.model.DocsMap[confluenceSourceID(page.ID)]; ok {
+				doc.IsExists = true
+			}
+		}
+	}
+	return nil
+}
+
+// loadPage loads the body of the page if its version has changed and sends it as a markdown document.
+func (c *Confluence) loadPage(ctx context.Context, page *confluencePageItem) error {
+	sourceID := confluenceSourceID(page.ID)
+	signature := strconv.Itoa(page.Version.Number)
+	doc, ok := c.model.DocsMap[sourceID]
+	if ok {
+		doc.IsExists = true
+		if doc.Signature == signature {
+			return nil
+		}
+	}
+
+	var body confluencePageItem
+	if err := c.requestAndParse(ctx, fmt.Sprintf(confluencePage, page.ID),
+		map[string]string{"expand": "body.storage,version"}, &body); err != nil {
+		return err
+	}
+	content, err := storageToMarkdown(body.Body.Storage.Value)
+	if err != nil {
+		return err
+	}
+
+	fileName := ""
+	if !ok {
+		doc = &model.Document{
+			SourceID:     sourceID,
+			ConnectorID:  c.model.ID,
+			CreationDate: time.Now().UTC(),
+		}
+		c.model.DocsMap[sourceID] = doc
+	} else {
+		// rewrite the file of the existing document
+		minioFile := strings.Split(doc.URL, ":")
+		if len(minioFile) == 3 && minioFile[0] == "minio" {
+			fileName = minioFile[2]
+		}
+	}
+	if fileName == "" {
+		fileName = utils.StripFileName(c.model.BuildFileName(fmt.Sprintf("%s-%s.md", uuid.New().String(), page.Title)))
+	}
+	doc.Signature = signature
+	doc.ChunkingSession = c.sessionID
+	doc.LastUpdate = pg.NullTime{time.Now().UTC()}
+	doc.OriginalURL = c.pageURL(page)
+	doc.IsExists = true
+
+	parentSourceID := ""
+	if len(page.Ancestors) > 0 {
+		parentSourceID = confluenceSourceID(page.Ancestors[len(page.Ancestors)-1].ID)
+	}
+	c.resultCh <- &Response{
+		URL:            doc.URL,
+		Name:           fileName,
+		SourceID:       sourceID,
+		ParentSourceID: parentSourceID,
+		DocumentID:     doc.ID.IntPart(),
+		MimeType:       "text/markdown",
+		FileType:       proto.FileType_MD,
+		Signature:      signature,
+		Content: &Content{
+			Bucket: model.BucketName(c.model.User.EmbeddingModel.TenantID),
+			Body:   []byte(fmt.Sprintf("# %s\n\n%s", page.Title, content)),
+		},
+	}
+	return nil
+}
+
+// getSpaces returns keys of all global spaces available to the user.
+func (c *Confluence) getSpaces(ctx context.Context) ([]string, error) {
+	var spaces []string
+	for start := 0; ; {
+		var response confluenceSpacesResponse
+		if err := c.requestAndParse(ctx, confluenceSpaces, map[string]string{
+			"type":  "global",
+			"start": strconv.Itoa(start),
+			"limit": strconv.Itoa(confluencePageLen),
+		}, &response); err != nil {
+			return nil, err
+		}
+		for _, space := range response.Results {
+			spaces = append(spaces, space.Key)
+		}
+		if response.Links.Next == "" || response.Size == 0 {
+			break
+		}
+		start += response.Size
+	}
+	return spaces, nil
+}
+
+// getPages returns current pages of the space with their versions and ancestors.
+func (c *Confluence) getPages(ctx context.Context, space string) ([]*confluencePageItem, error) {
+	var pages []*confluencePageItem
+	for start := 0; ; {
+		var response confluencePagesResponse
+		if err := c.requestAndParse(ctx, confluencePages, map[string]string{
+			"spaceKey": space,
+			"type":     "page",
+			"status":   "current",
+			"expand":   "version,ancestors",
+			"start":    strconv.Itoa(start),
+			"limit":    strconv.Itoa(confluencePageLen),
+		}, &response); err != nil {
+			return nil, err
+		}
+		for _, page := range response.Results {
+			if page.Links.Base == "" {
+				page.Links.Base = response.Links.Base
+			}
+			pages = append(pages, page)
+		}
+		if response.Links.Next == "" || response.Size == 0 {
+			break
+		}
+		start += response.Size
+	}
+	return pages, nil
+}
+
+// pageURL returns the link to the page in the Confluence user interface.
+func (c *Confluence) pageURL(page *confluencePageItem) string {
+	base := page.Links.Base
+	if base == "" {
+		base = c.param.BaseURL
+	}
+	return strings.TrimSuffix(base, "/") + page.Links.WebUI
+}
+
+// requestAndParse sends a GET request to the Confluence REST API and parses the response into the result.
+func (c *Confluence) requestAndParse(ctx context.Context, url string, params map[string]string, result interface{}) error {
+	response, err := c.client.R().SetContext(ctx).
+		SetQueryParams(params).
+		Get(url)
+	if err = utils.WrapRestyError(response, err); err != nil {
+		return err
+	}
+	return json.Unmarshal(response.Body(), result)
+}
+
+// confluenceSourceID returns the source id of the document of the page.
+func confluenceSourceID(pageID string) string {
+	return "confluence:" + pageID
+}
+
+// NewConfluence creates new instance of Confluence connector
+func NewConfluence(connector *model.Connector,
+	connectorRepo repository.ConnectorRepository) (Connector, error) {
+	conn := Confluence{
+		Base: Base{
+			connectorRepo: connectorRepo,
+		},
+		param: &ConfluenceParameters{},
+	}
+	conn.Base.Config(connector)
+
+	if err := connector.ConnectorSpecificConfig.ToStruct(conn.param); err != nil {
+		return nil, err
+	}
+	if err := conn.Validate(); err != nil {
+		return nil, err
+	}
+	conn.client = resty.New().
+		SetTimeout(time.Minute).
+		SetBaseURL(strings.TrimSuffix(conn.param.BaseURL, "/"))
+	if conn.param.Email != "" {
+		conn.client.SetBasicAuth(conn.param.Email, conn.param.Token)
+	} else {
+		conn.client.SetAuthToken(conn.param.Token)
+	}
+	return &conn, nil
+}
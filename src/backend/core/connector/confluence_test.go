@@ -0,0 +1,104 @@
+package connector
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConfluence_Execute(t *testing.T) {
+	pages := []map[string]interface{}{
+		{"id": "2", "title": "Child", "version": map[string]int{"number": 3},
+			"ancestors": []map[string]string{{"id": "1"}}, "_links": map[string]string{"webui": "/spaces/DOC/pages/2"}},
+		{"id": "1", "title": "Root", "version": map[string]int{"number": 1},
+			"_links": map[string]string{"webui": "/spaces/DOC/pages/1"}},
+		{"id": "3", "title": "Unchanged", "version": map[string]int{"number": 5},
+			"_links": map[string]string{"webui": "/spaces/DOC/pages/3"}},
+	}
+	bodies := map[string]string{
+		"1": `<h1>Overview</h1><p>Root <strong>page</strong></p>`,
+		"2": `<p>Child page</p><ul><li><p>first</p></li><li>second</li></ul>`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "user@test.com" || password != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body interface{}
+		switch r.URL.Path {
+		case confluencePages:
+			assert.Equal(t, "DOC", r.URL.Query().Get("spaceKey"))
+			body = map[string]interface{}{"results": pages, "size": len(pages),
+				"_links": map[string]string{"base": "https://test.atlassian.net/wiki"}}
+		case "/rest/api/content/1", "/rest/api/content/2":
+			id := r.URL.Path[len(confluencePages)+1:]
+			body = map[string]interface{}{"id": id,
+				"body": map[string]interface{}{"storage": map[string]string{"value": bodies[id]}}}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	docs := map[string]*model.Document{
+		"confluence:3": {ID: decimal.NewFromInt(3), SourceID: "confluence:3", Signature: "5"},
+		"confluence:4": {ID: decimal.NewFromInt(4), SourceID: "confluence:4", Signature: "1"},
+	}
+	conn, err := NewConfluence(&model.Connector{
+		ID:   decimal.NewFromInt(1),
+		Type: model.SourceTypeConfluence,
+		ConnectorSpecificConfig: model.JSONMap{
+			"base_url": server.URL,
+			"spaces":   []string{"DOC"},
+			"email":    "user@test.com",
+			"token":    "token",
+		},
+		DocsMap: docs,
+		User:    &model.User{EmbeddingModel: &model.EmbeddingModel{TenantID: uuid.New()}},
+	}, nil)
+	assert.NoError(t, err)
+
+	var responses []*Response
+	for response := range conn.Execute(context.Background(), nil) {
+		responses = append(responses, response)
+	}
+	if assert.Len(t, responses, 2) {
+		// parent pages are sent before their children
+		assert.Equal(t, "confluence:1", responses[0].SourceID)
+		assert.Equal(t, "", responses[0].ParentSourceID)
+		assert.Equal(t, "1", responses[0].Signature)
+		assert.Equal(t, "# Root\n\n# Overview\n\nRoot **page**", string(responses[0].Content.Body))
+
+		assert.Equal(t, "confluence:2", responses[1].SourceID)
+		assert.Equal(t, "confluence:1", responses[1].ParentSourceID)
+		assert.Equal(t, "# Child\n\nChild page\n\n- first\n- second", string(responses[1].Content.Body))
+	}
+	assert.Equal(t, "https://test.atlassian.net/wiki/spaces/DOC/pages/2", docs["confluence:2"].OriginalURL)
+	assert.True(t, docs["confluence:3"].IsExists)
+	assert.False(t, docs["confluence:4"].IsExists)
+}
+
+func TestStorageToMarkdown(t *testing.T) {
+	storage := `<h2>Install</h2>` +
+		`<ol><li>Download</li><li>Run <code>make</code></li></ol>` +
+		`<ac:structured-macro ac:name="code"><ac:parameter ac:name="language">go</ac:parameter>` +
+		`<ac:plain-text-body><![CDATA[fmt.Println("hi")]]></ac:plain-text-body></ac:structured-macro>` +
+		`<table><tbody><tr><th>Key</th><th>Value</th></tr><tr><td>a</td><td>1</td></tr></tbody></table>` +
+		`<p>See <a href="https://test.url">docs</a></p>`
+	markdown, err := storageToMarkdown(storage)
+	assert.NoError(t, err)
+	assert.Equal(t, "## Install\n\n"+
+		"1. Download\n1. Run `make`\n\n"+
+		"```go\nfmt.Println(\"hi\")\n```\n\n"+
+		"| Key | Value |\n| --- | --- |\n| a | 1 |\n\n"+
+		"See [docs](https://test.url)", markdown)
+}
@@ -0,0 +1,498 @@
+package connector
+
+import (
+	"bufio"
+	"bytes"
+	"cognix.ch/api/v2/core/model"
+	"cognix.ch/api/v2/core/proto"
+	"cognix.ch/api/v2/core/repository"
+	"cognix.ch/api/v2/core/utils"
+	"context"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	gitPerPage     = 100
+	gitFilePrefix  = "file:"
+	gitIssuePrefix = "issue:"
+	gitPullPrefix  = "pull:"
+)
+
+// gitDefaultFiles are patterns of documentation files loaded if no patterns are configured.
+var gitDefaultFiles = model.StringSlice{"*.md", "*.mdx", "*.rst", "*.txt", "docs/**"}
+
+// Git is a struct that represents the connector for GitHub and GitLab repositories.
+//
+// The struct contains the following fields:
+// - Base: a struct that represents the base properties and methods needed for various connectors.
+// - param: a pointer to the GitParameters struct that contains the connector parameters.
+// - provider: the gitProvider used to read the repository (GitHub API, GitLab API or a local clone).
+// - fileSizeLimit: an integer representing the maximum file size limit.
+// - sessionID: a uuid.NullUUID representing the session ID.
+type (
+	Git struct {
+		Base
+		param         *GitParameters
+		provider      gitProvider
+		fileSizeLimit int
+		sessionID     uuid.NullUUID
+	}
+	// GitParameters contains the repository path (owner/name for GitHub, group/project for GitLab),
+	// the branch (the default branch if empty), the access token and the API URL for self-hosted instances.
+	// If the local path is set, files are read from the local clone of the repository without the API,
+	// the clone must be in the local root directory of the connector service.
+	// Files are glob patterns of loaded files, documentation files are loaded if empty.
+	GitParameters struct {
+		Repository       string            `json:"repository"`
+		Branch           string            `json:"branch"`
+		Token            string            `json:"token"`
+		BaseURL          string            `json:"base_url"`
+		LocalPath        string            `json:"local_path"`
+		Files            model.StringSlice `json:"files"`
+		LoadIssues       bool              `json:"load_issues"`
+		LoadPullRequests bool              `json:"load_pull_requests"`
+	}
+
+	// gitProvider reads files, issues and pull requests of the repository.
+	gitProvider interface {
+		Files(ctx context.Context) ([]*gitFile, error)
+		Content(ctx context.Context, file *gitFile) ([]byte, error)
+		Issues(ctx context.Context, pullRequests bool) ([]*gitIssue, error)
+		Comments(ctx context.Context, issue *gitIssue) ([]*gitComment, error)
+	}
+	// gitFile is a file of the repository. SHA is the hash of the file content in the commit.
+	gitFile struct {
+		Path string
+		SHA  string
+		Size int
+		URL  string
+	}
+	// gitIssue is an issue or a pull request of the repository.
+	gitIssue struct {
+		Number      int
+		Title       string
+		Body        string
+		State       string
+		Author      string
+		URL         string
+		UpdatedAt   string
+		PullRequest bool
+	}
+	gitComment struct {
+		Author    string
+		Body      string
+		CreatedAt string
+	}
+	// gitLocal reads files of a local clone of the repository with the git command.
+	gitLocal struct {
+		path string
+		ref  string
+		root string
+	}
+)
+
+// Validate checks if the GitParameters struct is valid.
+// The repository is required if the local path is not set. The branch can not start with "-",
+// so that it is not taken as an option of the git command.
+func (p GitParameters) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.Repository, validation.When(p.LocalPath == "", validation.Required)),
+		validation.Field(&p.Branch, validation.By(func(value interface{}) error {
+			if strings.HasPrefix(p.Branch, "-") {
+				return fmt.Errorf("branch should not start with -")
+			}
+			return nil
+		})),
+		validation.Field(&p.BaseURL, is.URL),
+	)
+}
+
+// Validate checks if the Git parameter is valid.
+func (c *Git) Validate() error {
+	if c.param == nil {
+		return fmt.Errorf("git parameter is required")
+	}
+	return c.param.Validate()
+}
+
+// PrepareTask sends the connector request with the session ID to the connector service.
+func (c *Git) PrepareTask(ctx context.Context, sessionID uuid.UUID, task Task) error {
+	params := make(map[string]string)
+	params[model.ParamSessionID] = sessionID.String()
+	return task.RunConnector(ctx, &proto.ConnectorRequest{
+		Id:     c.model.ID.IntPart(),
+		Params: params,
+	})
+}
+
+// Execute executes the Git connector with the given context and parameters. It returns a channel of Response
+// objects. Documents of files, issues and pull requests that no longer exist are deleted by the executor.
+func (c *Git) Execute(ctx context.Context, param map[string]string) chan *Response {
+	var fileSizeLimit int
+	if size, ok := param[model.ParamFileLimit]; ok {
+		fileSizeLimit, _ = strconv.Atoi(size)
+	}
+	if fileSizeLimit == 0 {
+		fileSizeLimit = 1
+	}
+	c.fileSizeLimit = fileSizeLimit * model.GB
+	if local, ok := c.provider.(*gitLocal); ok {
+		local.root = param[model.ParamLocalRoot]
+	}
+	paramSessionID, _ := param[model.ParamSessionID]
+	if uuidSessionID, err := uuid.Parse(paramSessionID); err != nil {
+		c.sessionID = uuid.NullUUID{uuid.New(), true}
+	} else {
+		c.sessionID = uuid.NullUUID{uuidSessionID, true}
+	}
+	go func() {
+		defer close(c.resultCh)
+		c.execute(ctx)
+	}()
+	return c.resultCh
+}
+
+// execute loads files, issues and pull requests. If a list can not be loaded,
+// documents of this kind are kept as they are. Documents of disabled kinds are deleted.
+func (c *Git) execute(ctx context.Context) {
+	if err := c.loadFiles(ctx); err != nil {
+		zap.S().Errorf("error loading files: %s", err.Error())
+		c.keepDocuments(gitFilePrefix)
+	}
+	if c.param.LoadIssues {
+		if err := c.loadIssues(ctx, false); err != nil {
+			zap.S().Errorf("error loading issues: %s", err.Error())
+			c.keepDocuments(gitIssuePrefix)
+		}
+	}
+	if c.param.LoadPullRequests {
+		if err := c.loadIssues(ctx, true); err != nil {
+			zap.S().Errorf("error loading pull requests: %s", err.Error())
+			c.keepDocuments(gitPullPrefix)
+		}
+	}
+}
+
+// loadFiles sends files that match the patterns and have changed since the previous execution.
+func (c *Git) loadFiles(ctx context.Context) error {
+	files, err := c.provider.Files(ctx)
+	if err != nil {
+		return err
+	}
+	patterns := c.param.Files
+	if len(patterns) == 0 {
+		patterns = gitDefaultFiles
+	}
+	for _, file := range files {
+		if !matchPatterns(patterns, file.Path) || file.Size > c.fileSizeLimit {
+			continue
+		}
+		sourceID := c.sourceID(gitFilePrefix + file.Path)
+		if doc, ok := c.model.DocsMap[sourceID]; ok {
+			doc.IsExists = true
+			if doc.Signature == file.SHA {
+				continue
+			}
+		}
+		content, err := c.provider.Content(ctx, file)
+		if err != nil {
+			zap.S().Errorf("error loading file %s : %s", file.Path, err.Error())
+			continue
+		}
+		mimeType, fileType, ok := gitFileType(file.Path, content)
+		if !ok {
+			continue
+		}
+		c.send(sourceID, path.Base(file.Path), file.SHA, file.URL, mimeType, fileType, content)
+	}
+	return nil
+}
+
+// loadIssues sends issues or pull requests with their comments as markdown documents.
+// The time of the last update is used as a signature.
+func (c *Git) loadIssues(ctx context.Context, pullRequests bool) error {
+	issues, err := c.provider.Issues(ctx, pullRequests)
+	if err != nil {
+		return err
+	}
+	prefix := gitIssuePrefix
+	if pullRequests {
+		prefix = gitPullPrefix
+	}
+	for _, issue := range issues {
+		sourceID := c.sourceID(prefix + strconv.Itoa(issue.Number))
+		if doc, ok := c.model.DocsMap[sourceID]; ok {
+			doc.IsExists = true
+			if doc.Signature == issue.UpdatedAt {
+				continue
+			}
+		}
+		comments, err := c.provider.Comments(ctx, issue)
+		if err != nil {
+			zap.S().Errorf("error loading comments of %d : %s", issue.Number, err.Error())
+			continue
+		}
+		c.send(sourceID, fmt.Sprintf("%s-%d.md", strings.TrimSuffix(prefix, ":"), issue.Number),
+			issue.UpdatedAt, issue.URL, "text/markdown", proto.FileType_MD,
+			[]byte(buildIssueMD(issue, comments)))
+	}
+	return nil
+}
+
+// send creates or updates the document and sends its content to the result channel.
+// The file of the existing document is overwritten.
+func (c *Git) send(sourceID, name, signature, originalURL, mimeType string, fileType proto.FileType, content []byte) {
+	doc, ok := c.model.DocsMap[sourceID]
+	fileName := ""
+	if !ok {
+		doc = &model.Document{
+			SourceID:     sourceID,
+			ConnectorID:  c.model.ID,
+			CreationDate: time.Now().UTC(),
+		}
+		c.model.DocsMap[sourceID] = doc
+	} else {
+		minioFile := strings.Split(doc.URL, ":")
+		if len(minioFile) == 3 && minioFile[0] == "minio" {
+			fileName = minioFile[2]
+		}
+	}
+	if fileName == "" {
+		fileName = utils.StripFileName(c.model.BuildFileName(uuid.New().String() + "-" + name))
+	}
+	doc.Signature = signature
+	doc.ChunkingSession = c.sessionID
+	doc.LastUpdate = pg.NullTime{time.Now().UTC()}
+	doc.OriginalURL = originalURL
+	doc.IsExists = true
+
+	c.resultCh <- &Response{
+		URL:        doc.URL,
+		Name:       fileName,
+		SourceID:   sourceID,
+		DocumentID: doc.ID.IntPart(),
+		MimeType:   mimeType,
+		FileType:   fileType,
+		Signature:  signature,
+		Content: &Content{
+			Bucket: model.BucketName(c.model.User.EmbeddingModel.TenantID),
+			Body:   content,
+		},
+	}
+}
+
+// keepDocuments marks documents with the source id prefix as existing, so they are not deleted.
+func (c *Git) keepDocuments(prefix string) {
+	prefix = c.sourceID(prefix)
+	for sourceID, doc := range c.model.DocsMap {
+		if strings.HasPrefix(sourceID, prefix) {
+			doc.IsExists = true
+		}
+	}
+}
+
+// sourceID returns the source id of the document prefixed with the source type.
+func (c *Git) sourceID(id string) string {
+	return string(c.model.Type) + ":" + id
+}
+
+// buildIssueMD renders the issue or the pull request with its comments as markdown.
+func buildIssueMD(issue *gitIssue, comments []*gitComment) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("# #%d %s\n\n", issue.Number, issue.Title))
+	builder.WriteString(fmt.Sprintf("State: %s, author: %s\n\n", issue.State, issue.Author))
+	if issue.Body != "" {
+		builder.WriteString(issue.Body + "\n\n")
+	}
+	for _, comment := range comments {
+		builder.WriteString(fmt.Sprintf("## %s, %s\n\n%s\n\n", comment.Author, comment.CreatedAt, comment.Body))
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+// gitFileType returns the type of the file. Files of supported document types are recognized by extension,
+// other files are loaded as plain text if their content is a text.
+func gitFileType(filePath string, content []byte) (string, proto.FileType, bool) {
+	ext := strings.ToUpper(strings.TrimPrefix(path.Ext(filePath), "."))
+	if ext == "MDX" || ext == "MARKDOWN" {
+		ext = "MD"
+	}
+	if mimeType, ok := model.SupportedExtensions[ext]; ok {
+		return mimeType, model.SupportedMimeTypes[mimeType], true
+	}
+	if len(content) == 0 || !utf8.Valid(content) || bytes.IndexByte(content, 0) >= 0 {
+		return "", proto.FileType_UNKNOWN, false
+	}
+	return "text/plain", proto.FileType_TXT, true
+}
+
+// matchPatterns returns true if the path matches one of glob patterns.
+// Patterns without a slash are matched against the file name, "**" matches any number of directories.
+func matchPatterns(patterns model.StringSlice, filePath string) bool {
+	for _, pattern := range patterns {
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := path.Match(pattern, path.Base(filePath)); ok {
+				return true
+			}
+			continue
+		}
+		if globRegexp(pattern).MatchString(filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// globRegexp converts the glob pattern with "**" to the regular expression.
+func globRegexp(pattern string) *regexp.Regexp {
+	var builder strings.Builder
+	builder.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			builder.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			builder.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			builder.WriteString("[^/]*")
+		case pattern[i] == '?':
+			builder.WriteString("[^/]")
+		default:
+			builder.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	builder.WriteString("$")
+	return regexp.MustCompile(builder.String())
+}
+
+// Files returns files of the commit with hashes of their content.
+func (g *gitLocal) Files(ctx context.Context) ([]*gitFile, error) {
+	output, err := g.git(ctx, "ls-tree", "-r", "-l", "--end-of-options", g.ref)
+	if err != nil {
+		return nil, err
+	}
+	var files []*gitFile
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		// <mode> <type> <sha> <size>\t<path>
+		info, filePath, ok := strings.Cut(scanner.Text(), "\t")
+		fields := strings.Fields(info)
+		if !ok || len(fields) != 4 || fields[1] != "blob" {
+			continue
+		}
+		size, _ := strconv.Atoi(fields[3])
+		files = append(files, &gitFile{
+			Path: filePath,
+			SHA:  fields[2],
+			Size: size,
+		})
+	}
+	return files, scanner.Err()
+}
+
+// Content returns the content of the file.
+func (g *gitLocal) Content(ctx context.Context, file *gitFile) ([]byte, error) {
+	return g.git(ctx, "cat-file", "blob", file.SHA)
+}
+
+// Issues returns nothing, a local clone has no issues and pull requests.
+func (g *gitLocal) Issues(ctx context.Context, pullRequests bool) ([]*gitIssue, error) {
+	return nil, nil
+}
+
+// Comments returns nothing, a local clone has no issues and pull requests.
+func (g *gitLocal) Comments(ctx context.Context, issue *gitIssue) ([]*gitComment, error) {
+	return nil, nil
+}
+
+// git runs the git command in the repository and returns its output.
+func (g *gitLocal) git(ctx context.Context, args ...string) ([]byte, error) {
+	dir, err := g.dir()
+	if err != nil {
+		return nil, err
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s : %s %s", args[0], err.Error(), strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
+
+// dir returns the path of the repository with resolved symbolic links.
+// The repository must be in the local root, local repositories can not be read without it.
+func (g *gitLocal) dir() (string, error) {
+	if g.root == "" {
+		return "", fmt.Errorf("local repositories are not enabled")
+	}
+	root, err := resolvePath(g.root)
+	if err != nil {
+		return "", err
+	}
+	dir, err := resolvePath(g.path)
+	if err != nil {
+		return "", err
+	}
+	if rel, err := filepath.Rel(root, dir); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("local path %s is not in %s", g.path, g.root)
+	}
+	return dir, nil
+}
+
+// resolvePath returns the absolute path without symbolic links.
+func resolvePath(value string) (string, error) {
+	value, err := filepath.Abs(value)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(value)
+}
+
+// NewGit creates new instance of the connector for GitHub and GitLab repositories
+func NewGit(connector *model.Connector,
+	connectorRepo repository.ConnectorRepository) (Connector, error) {
+	conn := Git{
+		Base: Base{
+			connectorRepo: connectorRepo,
+		},
+		param: &GitParameters{},
+	}
+	conn.Base.Config(connector)
+
+	if err := connector.ConnectorSpecificConfig.ToStruct(conn.param); err != nil {
+		return nil, err
+	}
+	if err := conn.Validate(); err != nil {
+		return nil, err
+	}
+	switch {
+	case conn.param.LocalPath != "":
+		ref := conn.param.Branch
+		if ref == "" {
+			ref = "HEAD"
+		}
+		conn.provider = &gitLocal{path: conn.param.LocalPath, ref: ref}
+	case connector.Type == model.SourceTypeGitlab:
+		conn.provider = newGitLab(conn.param)
+	default:
+		conn.provider = newGitHub(conn.param)
+	}
+	return &conn, nil
+}
@@ -0,0 +1,222 @@
+package connector
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newGitTestConnector(t *testing.T, sourceType model.SourceType, config model.JSONMap) *model.Connector {
+	return &model.Connector{
+		ID:                      decimal.NewFromInt(1),
+		Type:                    sourceType,
+		ConnectorSpecificConfig: config,
+		DocsMap:                 make(map[string]*model.Document),
+		User:                    &model.User{EmbeddingModel: &model.EmbeddingModel{TenantID: uuid.New()}},
+	}
+}
+
+func executeGit(t *testing.T, connectorModel *model.Connector, params map[string]string) map[string]*Response {
+	for _, doc := range connectorModel.DocsMap {
+		doc.IsExists = false
+	}
+	conn, err := New(connectorModel, nil, "")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	responses := make(map[string]*Response)
+	for response := range conn.Execute(context.Background(), params) {
+		responses[response.SourceID] = response
+	}
+	// documents are stored by the executor
+	for _, doc := range connectorModel.DocsMap {
+		if doc.ID.IntPart() == 0 {
+			doc.ID = decimal.NewFromInt(int64(len(connectorModel.DocsMap) + 100))
+		}
+	}
+	return responses
+}
+
+func TestGit_LocalClone(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@test.com"}, args...)...)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %s", args, output)
+		}
+	}
+	write := func(name, content string) {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	git("init", "-q")
+	write("README.md", "# Readme")
+	write("docs/guide.txt", "guide")
+	write("main.go", "package main")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+
+	connectorModel := newGitTestConnector(t, model.SourceTypeGithub, model.JSONMap{
+		"local_path": dir,
+	})
+	// local repositories are read only in the local root of the connector service
+	responses := executeGit(t, connectorModel, nil)
+	assert.Len(t, responses, 0)
+	responses = executeGit(t, connectorModel, map[string]string{model.ParamLocalRoot: t.TempDir()})
+	assert.Len(t, responses, 0)
+
+	params := map[string]string{model.ParamLocalRoot: filepath.Dir(dir)}
+	responses = executeGit(t, connectorModel, params)
+	assert.Len(t, responses, 2)
+	readme, ok := responses["github:file:README.md"]
+	if assert.True(t, ok) {
+		assert.Equal(t, "# Readme", string(readme.Content.Body))
+		assert.Equal(t, "text/markdown", readme.MimeType)
+	}
+	_, ok = responses["github:file:docs/guide.txt"]
+	assert.True(t, ok)
+
+	// unchanged files are skipped
+	responses = executeGit(t, connectorModel, params)
+	assert.Len(t, responses, 0)
+	assert.True(t, connectorModel.DocsMap["github:file:README.md"].IsExists)
+
+	// changed files are loaded again, deleted files are not marked as existing
+	write("README.md", "# Changed")
+	git("rm", "-q", "docs/guide.txt")
+	git("add", "-A")
+	git("commit", "-q", "-m", "change")
+	responses = executeGit(t, connectorModel, params)
+	assert.Len(t, responses, 1)
+	assert.Equal(t, "# Changed", string(responses["github:file:README.md"].Content.Body))
+	assert.False(t, connectorModel.DocsMap["github:file:docs/guide.txt"].IsExists)
+}
+
+func TestGit_GitHub(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var body interface{}
+		switch r.URL.Path {
+		case "/repos/owner/repo":
+			body = map[string]string{"default_branch": "main", "html_url": "https://github.com/owner/repo"}
+		case "/repos/owner/repo/git/trees/main":
+			body = map[string]interface{}{"tree": []map[string]interface{}{
+				{"path": "README.md", "type": "blob", "sha": "sha1", "size": 8},
+				{"path": "docs", "type": "tree", "sha": "sha2"},
+			}}
+		case "/repos/owner/repo/git/blobs/sha1":
+			_, _ = w.Write([]byte("# Readme"))
+			return
+		case "/repos/owner/repo/issues":
+			body = []map[string]interface{}{
+				{"number": 1, "title": "Bug", "body": "It fails", "state": "open", "user": map[string]string{"login": "alice"},
+					"html_url": "https://github.com/owner/repo/issues/1", "updated_at": "2024-07-01T10:00:00Z"},
+				{"number": 2, "title": "Fix", "body": "Fixes #1", "state": "closed", "user": map[string]string{"login": "bob"},
+					"html_url": "https://github.com/owner/repo/pull/2", "updated_at": "2024-07-02T10:00:00Z",
+					"pull_request": map[string]string{"url": "https://api.github.com/repos/owner/repo/pulls/2"}},
+			}
+		case "/repos/owner/repo/issues/1/comments", "/repos/owner/repo/issues/2/comments":
+			body = []map[string]interface{}{
+				{"body": "Confirmed", "user": map[string]string{"login": "bob"}, "created_at": "2024-07-01T11:00:00Z"},
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	connectorModel := newGitTestConnector(t, model.SourceTypeGithub, model.JSONMap{
+		"repository":         "owner/repo",
+		"token":              "token",
+		"base_url":           server.URL,
+		"load_issues":        true,
+		"load_pull_requests": true,
+	})
+	connectorModel.DocsMap["github:issue:3"] = &model.Document{ID: decimal.NewFromInt(3), SourceID: "github:issue:3"}
+	responses := executeGit(t, connectorModel, nil)
+	assert.Len(t, responses, 3)
+
+	readme, ok := responses["github:file:README.md"]
+	if assert.True(t, ok) {
+		assert.Equal(t, "sha1", readme.Signature)
+		assert.Equal(t, "https://github.com/owner/repo/blob/main/README.md",
+			connectorModel.DocsMap[readme.SourceID].OriginalURL)
+	}
+	issue, ok := responses["github:issue:1"]
+	if assert.True(t, ok) {
+		assert.Equal(t, "2024-07-01T10:00:00Z", issue.Signature)
+		assert.Equal(t, fmt.Sprintf("# #1 Bug\n\nState: open, author: alice\n\nIt fails\n\n## bob, %s\n\nConfirmed",
+			"2024-07-01T11:00:00Z"), string(issue.Content.Body))
+	}
+	_, ok = responses["github:pull:2"]
+	assert.True(t, ok)
+	assert.False(t, connectorModel.DocsMap["github:issue:3"].IsExists)
+}
+
+func TestGit_GitHubTruncatedTree(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+		switch {
+		case r.URL.Path == "/repos/owner/repo":
+			body = map[string]string{"default_branch": "main", "html_url": "https://github.com/owner/repo"}
+		case r.URL.Path == "/repos/owner/repo/git/trees/main" && r.URL.Query().Get("recursive") != "":
+			body = map[string]interface{}{"truncated": true, "tree": []map[string]interface{}{
+				{"path": "README.md", "type": "blob", "sha": "sha1", "size": 8},
+			}}
+		case r.URL.Path == "/repos/owner/repo/git/trees/main":
+			body = map[string]interface{}{"tree": []map[string]interface{}{
+				{"path": "README.md", "type": "blob", "sha": "sha1", "size": 8},
+				{"path": "docs", "type": "tree", "sha": "sha2"},
+			}}
+		case r.URL.Path == "/repos/owner/repo/git/trees/sha2":
+			body = map[string]interface{}{"tree": []map[string]interface{}{
+				{"path": "guide.md", "type": "blob", "sha": "sha3", "size": 5},
+			}}
+		case strings.HasPrefix(r.URL.Path, "/repos/owner/repo/git/blobs/"):
+			_, _ = w.Write([]byte("# Content"))
+			return
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	connectorModel := newGitTestConnector(t, model.SourceTypeGithub, model.JSONMap{
+		"repository": "owner/repo",
+		"base_url":   server.URL,
+	})
+	responses := executeGit(t, connectorModel, nil)
+	assert.Len(t, responses, 2)
+	_, ok := responses["github:file:docs/guide.md"]
+	assert.True(t, ok)
+}
+
+func TestMatchPatterns(t *testing.T) {
+	patterns := model.StringSlice{"*.md", "docs/**", "src/**/*.go"}
+	assert.True(t, matchPatterns(patterns, "README.md"))
+	assert.True(t, matchPatterns(patterns, "pkg/CHANGELOG.md"))
+	assert.True(t, matchPatterns(patterns, "docs/guide/intro.rst"))
+	assert.True(t, matchPatterns(patterns, "src/main.go"))
+	assert.True(t, matchPatterns(patterns, "src/core/model.go"))
+	assert.False(t, matchPatterns(patterns, "main.go"))
+	assert.False(t, matchPatterns(patterns, "src/core/model.ts"))
+}
@@ -0,0 +1,241 @@
+package connector
+
+import (
+	"cognix.ch/api/v2/core/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	gitHubAPIURL   = "https://api.github.com"
+	gitHubRepo     = "/repos/%s"
+	gitHubTree     = "/repos/%s/git/trees/%s"
+	gitHubBlob     = "/repos/%s/git/blobs/%s"
+	gitHubIssues   = "/repos/%s/issues"
+	gitHubComments = "/repos/%s/issues/%d/comments"
+)
+
+type (
+	// gitHub reads the repository with the GitHub REST API.
+	gitHub struct {
+		param  *GitParameters
+		client *resty.Client
+		branch string
+		webURL string
+	}
+	gitHubUser struct {
+		Login string `json:"login"`
+	}
+	gitHubRepository struct {
+		DefaultBranch string `json:"default_branch"`
+		HTMLURL       string `json:"html_url"`
+	}
+	gitHubTreeResponse struct {
+		Tree []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+			SHA  string `json:"sha"`
+			Size int    `json:"size"`
+		} `json:"tree"`
+		Truncated bool `json:"truncated"`
+	}
+	gitHubIssue struct {
+		Number      int             `json:"number"`
+		Title       string          `json:"title"`
+		Body        string          `json:"body"`
+		State       string          `json:"state"`
+		User        gitHubUser      `json:"user"`
+		HTMLURL     string          `json:"html_url"`
+		UpdatedAt   string          `json:"updated_at"`
+		PullRequest json.RawMessage `json:"pull_request"`
+	}
+	gitHubComment struct {
+		Body      string     `json:"body"`
+		User      gitHubUser `json:"user"`
+		CreatedAt string     `json:"created_at"`
+	}
+)
+
+// Files returns files of the branch with hashes of their content.
+// The recursive tree of large repositories is truncated by GitHub, the tree is walked directory by directory then.
+func (g *gitHub) Files(ctx context.Context) ([]*gitFile, error) {
+	if err := g.init(ctx); err != nil {
+		return nil, err
+	}
+	var tree gitHubTreeResponse
+	if err := g.requestAndParse(ctx, fmt.Sprintf(gitHubTree, g.param.Repository, g.branch),
+		map[string]string{"recursive": "1"}, &tree); err != nil {
+		return nil, err
+	}
+	if tree.Truncated {
+		return g.walkTree(ctx, g.branch, "")
+	}
+	var files []*gitFile
+	for _, item := range tree.Tree {
+		if item.Type != "blob" {
+			continue
+		}
+		files = append(files, g.file(item.Path, item.SHA, item.Size))
+	}
+	return files, nil
+}
+
+// walkTree returns files of the tree and of its subtrees. The tree is not truncated
+// if it is requested without subtrees, dir is the path of the tree in the repository.
+func (g *gitHub) walkTree(ctx context.Context, sha, dir string) ([]*gitFile, error) {
+	var tree gitHubTreeResponse
+	if err := g.requestAndParse(ctx, fmt.Sprintf(gitHubTree, g.param.Repository, sha), nil, &tree); err != nil {
+		return nil, err
+	}
+	if tree.Truncated {
+		return nil, fmt.Errorf("tree %s of %s is truncated", sha, g.param.Repository)
+	}
+	var files []*gitFile
+	for _, item := range tree.Tree {
+		filePath := path.Join(dir, item.Path)
+		switch item.Type {
+		case "blob":
+			files = append(files, g.file(filePath, item.SHA, item.Size))
+		case "tree":
+			subtree, err := g.walkTree(ctx, item.SHA, filePath)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, subtree...)
+		}
+	}
+	return files, nil
+}
+
+// file returns the file of the branch with the link to its page on GitHub.
+func (g *gitHub) file(filePath, sha string, size int) *gitFile {
+	return &gitFile{
+		Path: filePath,
+		SHA:  sha,
+		Size: size,
+		URL:  fmt.Sprintf("%s/blob/%s/%s", g.webURL, g.branch, filePath),
+	}
+}
+
+// Content returns the raw content of the file.
+func (g *gitHub) Content(ctx context.Context, file *gitFile) ([]byte, error) {
+	response, err := g.client.R().SetContext(ctx).
+		SetHeader("Accept", "application/vnd.github.raw").
+		Get(fmt.Sprintf(gitHubBlob, g.param.Repository, file.SHA))
+	if err = utils.WrapRestyError(response, err); err != nil {
+		return nil, err
+	}
+	return response.Body(), nil
+}
+
+// Issues returns issues or pull requests of the repository. The issues API of GitHub returns both of them.
+func (g *gitHub) Issues(ctx context.Context, pullRequests bool) ([]*gitIssue, error) {
+	var issues []*gitIssue
+	for page := 1; ; page++ {
+		var items []*gitHubIssue
+		if err := g.requestAndParse(ctx, fmt.Sprintf(gitHubIssues, g.param.Repository), map[string]string{
+			"state":    "all",
+			"per_page": strconv.Itoa(gitPerPage),
+			"page":     strconv.Itoa(page),
+		}, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if isPull := len(item.PullRequest) > 0 && string(item.PullRequest) != "null"; isPull != pullRequests {
+				continue
+			}
+			issues = append(issues, &gitIssue{
+				Number:      item.Number,
+				Title:       item.Title,
+				Body:        item.Body,
+				State:       item.State,
+				Author:      item.User.Login,
+				URL:         item.HTMLURL,
+				UpdatedAt:   item.UpdatedAt,
+				PullRequest: pullRequests,
+			})
+		}
+		if len(items) < gitPerPage {
+			break
+		}
+	}
+	return issues, nil
+}
+
+// Comments returns the discussion of the issue or the pull request.
+func (g *gitHub) Comments(ctx context.Context, issue *gitIssue) ([]*gitComment, error) {
+	var comments []*gitComment
+	for page := 1; ; page++ {
+		var items []*gitHubComment
+		if err := g.requestAndParse(ctx, fmt.Sprintf(gitHubComments, g.param.Repository, issue.Number), map[string]string{
+			"per_page": strconv.Itoa(gitPerPage),
+			"page":     strconv.Itoa(page),
+		}, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			comments = append(comments, &gitComment{
+				Author:    item.User.Login,
+				Body:      item.Body,
+				CreatedAt: item.CreatedAt,
+			})
+		}
+		if len(items) < gitPerPage {
+			break
+		}
+	}
+	return comments, nil
+}
+
+// init loads the default branch and the web URL of the repository.
+func (g *gitHub) init(ctx context.Context) error {
+	if g.webURL != "" {
+		return nil
+	}
+	var repository gitHubRepository
+	if err := g.requestAndParse(ctx, fmt.Sprintf(gitHubRepo, g.param.Repository), nil, &repository); err != nil {
+		return err
+	}
+	g.webURL = strings.TrimSuffix(repository.HTMLURL, "/")
+	if g.branch == "" {
+		g.branch = repository.DefaultBranch
+	}
+	return nil
+}
+
+// requestAndParse sends a GET request to the GitHub API and parses the response into the result.
+func (g *gitHub) requestAndParse(ctx context.Context, url string, params map[string]string, result interface{}) error {
+	response, err := g.client.R().SetContext(ctx).
+		SetQueryParams(params).
+		Get(url)
+	if err = utils.WrapRestyError(response, err); err != nil {
+		return err
+	}
+	return json.Unmarshal(response.Body(), result)
+}
+
+// newGitHub creates a reader of the repository with the GitHub REST API.
+func newGitHub(param *GitParameters) *gitHub {
+	baseURL := param.BaseURL
+	if baseURL == "" {
+		baseURL = gitHubAPIURL
+	}
+	client := resty.New().
+		SetTimeout(time.Minute).
+		SetBaseURL(strings.TrimSuffix(baseURL, "/")).
+		SetHeader("Accept", "application/vnd.github+json")
+	if param.Token != "" {
+		client.SetAuthToken(param.Token)
+	}
+	return &gitHub{
+		param:  param,
+		client: client,
+		branch: param.Branch,
+	}
+}
@@ -0,0 +1,222 @@
+package connector
+
+import (
+	"cognix.ch/api/v2/core/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	gitLabAPIURL       = "https://gitlab.com/api/v4"
+	gitLabProject      = "/projects/%s"
+	gitLabTree         = "/projects/%s/repository/tree"
+	gitLabBlob         = "/projects/%s/repository/blobs/%s/raw"
+	gitLabIssues       = "/projects/%s/issues"
+	gitLabMergeRequest = "/projects/%s/merge_requests"
+	gitLabNotes        = "/projects/%s/%s/%d/notes"
+)
+
+type (
+	// gitLab reads the repository with the GitLab REST API.
+	gitLab struct {
+		param     *GitParameters
+		client    *resty.Client
+		projectID string
+		branch    string
+		webURL    string
+	}
+	gitLabUser struct {
+		Username string `json:"username"`
+	}
+	gitLabProjectResponse struct {
+		DefaultBranch string `json:"default_branch"`
+		WebURL        string `json:"web_url"`
+	}
+	gitLabTreeItem struct {
+		ID   string `json:"id"`
+		Path string `json:"path"`
+		Type string `json:"type"`
+	}
+	gitLabIssue struct {
+		IID         int        `json:"iid"`
+		Title       string     `json:"title"`
+		Description string     `json:"description"`
+		State       string     `json:"state"`
+		Author      gitLabUser `json:"author"`
+		WebURL      string     `json:"web_url"`
+		UpdatedAt   string     `json:"updated_at"`
+	}
+	gitLabNote struct {
+		Body      string     `json:"body"`
+		Author    gitLabUser `json:"author"`
+		CreatedAt string     `json:"created_at"`
+		System    bool       `json:"system"`
+	}
+)
+
+// Files returns files of the branch with hashes of their content.
+// GitLab does not return sizes of files in the tree, the size limit is not applied.
+func (g *gitLab) Files(ctx context.Context) ([]*gitFile, error) {
+	if err := g.init(ctx); err != nil {
+		return nil, err
+	}
+	var files []*gitFile
+	for page := 1; ; page++ {
+		var items []*gitLabTreeItem
+		if err := g.requestAndParse(ctx, fmt.Sprintf(gitLabTree, g.projectID), map[string]string{
+			"ref":       g.branch,
+			"recursive": "true",
+			"per_page":  strconv.Itoa(gitPerPage),
+			"page":      strconv.Itoa(page),
+		}, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item.Type != "blob" {
+				continue
+			}
+			files = append(files, &gitFile{
+				Path: item.Path,
+				SHA:  item.ID,
+				URL:  fmt.Sprintf("%s/-/blob/%s/%s", g.webURL, g.branch, item.Path),
+			})
+		}
+		if len(items) < gitPerPage {
+			break
+		}
+	}
+	return files, nil
+}
+
+// Content returns the raw content of the file.
+func (g *gitLab) Content(ctx context.Context, file *gitFile) ([]byte, error) {
+	response, err := g.client.R().SetContext(ctx).
+		Get(fmt.Sprintf(gitLabBlob, g.projectID, file.SHA))
+	if err = utils.WrapRestyError(response, err); err != nil {
+		return nil, err
+	}
+	return response.Body(), nil
+}
+
+// Issues returns issues or merge requests of the project.
+func (g *gitLab) Issues(ctx context.Context, pullRequests bool) ([]*gitIssue, error) {
+	endpoint := gitLabIssues
+	if pullRequests {
+		endpoint = gitLabMergeRequest
+	}
+	var issues []*gitIssue
+	for page := 1; ; page++ {
+		var items []*gitLabIssue
+		if err := g.requestAndParse(ctx, fmt.Sprintf(endpoint, g.projectID), map[string]string{
+			"scope":    "all",
+			"state":    "all",
+			"per_page": strconv.Itoa(gitPerPage),
+			"page":     strconv.Itoa(page),
+		}, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			issues = append(issues, &gitIssue{
+				Number:      item.IID,
+				Title:       item.Title,
+				Body:        item.Description,
+				State:       item.State,
+				Author:      item.Author.Username,
+				URL:         item.WebURL,
+				UpdatedAt:   item.UpdatedAt,
+				PullRequest: pullRequests,
+			})
+		}
+		if len(items) < gitPerPage {
+			break
+		}
+	}
+	return issues, nil
+}
+
+// Comments returns notes of the issue or the merge request. System notes are skipped.
+func (g *gitLab) Comments(ctx context.Context, issue *gitIssue) ([]*gitComment, error) {
+	kind := "issues"
+	if issue.PullRequest {
+		kind = "merge_requests"
+	}
+	var comments []*gitComment
+	for page := 1; ; page++ {
+		var items []*gitLabNote
+		if err := g.requestAndParse(ctx, fmt.Sprintf(gitLabNotes, g.projectID, kind, issue.Number), map[string]string{
+			"sort":     "asc",
+			"per_page": strconv.Itoa(gitPerPage),
+			"page":     strconv.Itoa(page),
+		}, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item.System {
+				continue
+			}
+			comments = append(comments, &gitComment{
+				Author:    item.Author.Username,
+				Body:      item.Body,
+				CreatedAt: item.CreatedAt,
+			})
+		}
+		if len(items) < gitPerPage {
+			break
+		}
+	}
+	return comments, nil
+}
+
+// init loads the default branch and the web URL of the project.
+func (g *gitLab) init(ctx context.Context) error {
+	if g.webURL != "" {
+		return nil
+	}
+	var project gitLabProjectResponse
+	if err := g.requestAndParse(ctx, fmt.Sprintf(gitLabProject, g.projectID), nil, &project); err != nil {
+		return err
+	}
+	g.webURL = strings.TrimSuffix(project.WebURL, "/")
+	if g.branch == "" {
+		g.branch = project.DefaultBranch
+	}
+	return nil
+}
+
+// requestAndParse sends a GET request to the GitLab API and parses the response into the result.
+func (g *gitLab) requestAndParse(ctx context.Context, url string, params map[string]string, result interface{}) error {
+	response, err := g.client.R().SetContext(ctx).
+		SetQueryParams(params).
+		Get(url)
+	if err = utils.WrapRestyError(response, err); err != nil {
+		return err
+	}
+	return json.Unmarshal(response.Body(), result)
+}
+
+// newGitLab creates a reader of the project with the GitLab REST API.
+// The path of the project is used as its id.
+func newGitLab(param *GitParameters) *gitLab {
+	baseURL := param.BaseURL
+	if baseURL == "" {
+		baseURL = gitLabAPIURL
+	}
+	client := resty.New().
+		SetTimeout(time.Minute).
+		SetBaseURL(strings.TrimSuffix(baseURL, "/"))
+	if param.Token != "" {
+		client.SetHeader("PRIVATE-TOKEN", param.Token)
+	}
+	return &gitLab{
+		param:     param,
+		client:    client,
+		projectID: url.PathEscape(param.Repository),
+		branch:    param.Branch,
+	}
+}
@@ -0,0 +1,131 @@
+package connector
+
+import (
+	"cognix.ch/api/v2/core/utils"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	gmailUser      = "me"
+	gmailThreadURL = "https://mail.google.com/mail/u/0/#all/%s"
+)
+
+// gmailProvider reads messages of labels with the Gmail API.
+type gmailProvider struct {
+	client *gmail.Service
+	labels map[string]string
+}
+
+// Threads returns threads with messages of the label received after the time of the state.
+// Gmail accepts label ids and names of user labels.
+func (p *gmailProvider) Threads(ctx context.Context, folder string, state *MailFolderState) ([]*mailThread, error) {
+	labelID, err := p.labelID(ctx, folder)
+	if err != nil {
+		return nil, err
+	}
+	call := p.client.Users.Messages.List(gmailUser).LabelIds(labelID)
+	if state.After > 0 {
+		call = call.Q(fmt.Sprintf("after:%d", state.After))
+	}
+	threadIDs := make(map[string]bool)
+	if err = call.Pages(ctx, func(response *gmail.ListMessagesResponse) error {
+		for _, message := range response.Messages {
+			threadIDs[message.ThreadId] = true
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	var threads []*mailThread
+	for threadID := range threadIDs {
+		thread, err := p.client.Users.Threads.Get(gmailUser, threadID).Format("minimal").Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		result := &mailThread{
+			ID:  threadID,
+			URL: fmt.Sprintf(gmailThreadURL, threadID),
+		}
+		for _, item := range thread.Messages {
+			message, err := p.message(ctx, item.Id)
+			if err != nil {
+				return nil, err
+			}
+			result.Messages = append(result.Messages, message)
+			if seconds := item.InternalDate / 1000; seconds > state.After {
+				state.After = seconds
+			}
+		}
+		threads = append(threads, result)
+	}
+	sort.Slice(threads, func(i, j int) bool {
+		return threads[i].ID < threads[j].ID
+	})
+	return threads, nil
+}
+
+// Close does nothing, requests of the Gmail API do not hold connections.
+func (p *gmailProvider) Close() error {
+	return nil
+}
+
+// message loads the message in the raw format and parses it.
+func (p *gmailProvider) message(ctx context.Context, id string) (*mailMessage, error) {
+	item, err := p.client.Users.Messages.Get(gmailUser, id).Format("raw").Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := base64.URLEncoding.DecodeString(item.Raw)
+	if err != nil {
+		if raw, err = base64.RawURLEncoding.DecodeString(item.Raw); err != nil {
+			return nil, err
+		}
+	}
+	message, err := parseMail(raw)
+	if err != nil {
+		return nil, err
+	}
+	message.ID = id
+	return message, nil
+}
+
+// labelID returns the id of the label by its id or name.
+func (p *gmailProvider) labelID(ctx context.Context, label string) (string, error) {
+	if p.labels == nil {
+		response, err := p.client.Users.Labels.List(gmailUser).Context(ctx).Do()
+		if err != nil {
+			return "", err
+		}
+		p.labels = make(map[string]string)
+		for _, item := range response.Labels {
+			p.labels[item.Id] = item.Id
+			p.labels[strings.ToLower(item.Name)] = item.Id
+		}
+	}
+	if id, ok := p.labels[label]; ok {
+		return id, nil
+	}
+	if id, ok := p.labels[strings.ToLower(label)]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("label %s not found", label)
+}
+
+// newGmail creates a reader of the mailbox with the Gmail API.
+func newGmail(token *oauth2.Token) (*gmailProvider, error) {
+	client, err := gmail.NewService(context.Background(),
+		option.WithHTTPClient(&http.Client{Transport: utils.NewTransport(token)}))
+	if err != nil {
+		return nil, err
+	}
+	return &gmailProvider{client: client}, nil
+}
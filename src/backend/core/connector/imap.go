@@ -0,0 +1,333 @@
+package connector
+
+import (
+	"bufio"
+	"cognix.ch/api/v2/core/model"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	imapPort       = 993
+	imapPortPlain  = 143
+	imapTimeout    = time.Minute
+	imapFetchBatch = 50
+)
+
+var (
+	imapLiteral     = regexp.MustCompile(`\{(\d+)\}$`)
+	imapUIDValidity = regexp.MustCompile(`\[UIDVALIDITY (\d+)\]`)
+	imapUIDNext     = regexp.MustCompile(`\[UIDNEXT (\d+)\]`)
+	imapFetchUID    = regexp.MustCompile(`\bUID (\d+)`)
+)
+
+type (
+	// imapProvider reads messages of folders over IMAP. Folders are opened read-only.
+	// Messages larger than sizeLimit bytes fail the fetch.
+	imapProvider struct {
+		param     *MailParameters
+		client    *imapClient
+		sizeLimit int
+	}
+	// imapClient is a minimal IMAP4rev1 client that supports commands needed to read messages.
+	// Literals sent by the server can not be larger than literalLimit bytes.
+	imapClient struct {
+		conn         net.Conn
+		reader       *bufio.Reader
+		tag          int
+		literalLimit int
+	}
+	// imapResponse is an untagged response. Literals of the response are replaced with their sizes in the line.
+	imapResponse struct {
+		Line     string
+		Literals [][]byte
+	}
+)
+
+// Threads returns threads with messages added to the folder after UIDNEXT of the state.
+// Threads contain all messages of the folder that refer to the same first message.
+func (p *imapProvider) Threads(ctx context.Context, folder string, state *MailFolderState) ([]*mailThread, error) {
+	if err := p.connect(ctx); err != nil {
+		return nil, err
+	}
+	responses, err := p.client.command("EXAMINE %s", imapQuote(folder))
+	if err != nil {
+		return nil, err
+	}
+	var uidValidity, uidNext uint32
+	for _, response := range responses {
+		if match := imapUIDValidity.FindStringSubmatch(response.Line); match != nil {
+			uidValidity = parseUint32(match[1])
+		}
+		if match := imapUIDNext.FindStringSubmatch(response.Line); match != nil {
+			uidNext = parseUint32(match[1])
+		}
+	}
+	if state.UIDValidity != uidValidity || state.UIDNext == 0 {
+		// UIDs are reassigned, load the folder again
+		state.UIDValidity, state.UIDNext = uidValidity, 1
+	}
+	uids, err := p.search(fmt.Sprintf("UID %d:*", state.UIDNext))
+	if err != nil {
+		return nil, err
+	}
+	// n:* matches the last message even if its UID is below n
+	newUIDs := uids[:0]
+	for _, uid := range uids {
+		if uid >= state.UIDNext {
+			newUIDs = append(newUIDs, uid)
+		}
+	}
+	messages, err := p.fetch(newUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	threads := make(map[string]*mailThread)
+	loaded := make(map[uint32]bool)
+	for uid, message := range messages {
+		loaded[uid] = true
+		if uid >= state.UIDNext {
+			state.UIDNext = uid + 1
+		}
+		threadID := message.threadID()
+		if _, ok := threads[threadID]; !ok {
+			threads[threadID] = &mailThread{ID: threadID}
+		}
+	}
+	if uidNext > state.UIDNext {
+		state.UIDNext = uidNext
+	}
+	// load previous messages of threads
+	var result []*mailThread
+	for threadID, thread := range threads {
+		uids, err = p.search(fmt.Sprintf("OR HEADER Message-ID %s HEADER References %s",
+			imapQuote(threadID), imapQuote(threadID)))
+		if err != nil {
+			return nil, err
+		}
+		var previous []uint32
+		for _, uid := range uids {
+			if !loaded[uid] {
+				previous = append(previous, uid)
+			}
+		}
+		previousMessages, err := p.fetch(previous)
+		if err != nil {
+			return nil, err
+		}
+		for uid, message := range previousMessages {
+			messages[uid] = message
+			loaded[uid] = true
+		}
+		result = append(result, thread)
+	}
+	for _, message := range messages {
+		if thread, ok := threads[message.threadID()]; ok {
+			thread.Messages = append(thread.Messages, message)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// Close logs out and closes the connection.
+func (p *imapProvider) Close() error {
+	if p.client == nil {
+		return nil
+	}
+	_, _ = p.client.command("LOGOUT")
+	err := p.client.conn.Close()
+	p.client = nil
+	return err
+}
+
+// connect opens the connection and logs in if it is not opened yet.
+func (p *imapProvider) connect(ctx context.Context) error {
+	if p.client != nil {
+		return nil
+	}
+	port := p.param.Port
+	if port == 0 {
+		port = imapPort
+		if p.param.Insecure {
+			port = imapPortPlain
+		}
+	}
+	client, err := dialIMAP(ctx, p.param.Host, port, p.param.Insecure, p.sizeLimit)
+	if err != nil {
+		return err
+	}
+	if _, err = client.command("LOGIN %s %s", imapQuote(p.param.Username), imapQuote(p.param.Password)); err != nil {
+		client.conn.Close()
+		return err
+	}
+	p.client = client
+	return nil
+}
+
+// search returns UIDs of messages of the opened folder that match the criteria.
+func (p *imapProvider) search(criteria string) ([]uint32, error) {
+	responses, err := p.client.command("UID SEARCH %s", criteria)
+	if err != nil {
+		return nil, err
+	}
+	var uids []uint32
+	for _, response := range responses {
+		fields := strings.Fields(response.Line)
+		if len(fields) < 2 || fields[0] != "*" || fields[1] != "SEARCH" {
+			continue
+		}
+		for _, field := range fields[2:] {
+			uids = append(uids, parseUint32(field))
+		}
+	}
+	return uids, nil
+}
+
+// fetch returns parsed messages by their UIDs. Messages are fetched without setting the \Seen flag.
+func (p *imapProvider) fetch(uids []uint32) (map[uint32]*mailMessage, error) {
+	messages := make(map[uint32]*mailMessage)
+	for start := 0; start < len(uids); start += imapFetchBatch {
+		end := min(start+imapFetchBatch, len(uids))
+		set := make([]string, 0, end-start)
+		for _, uid := range uids[start:end] {
+			set = append(set, strconv.FormatUint(uint64(uid), 10))
+		}
+		responses, err := p.client.command("UID FETCH %s (UID BODY.PEEK[])", strings.Join(set, ","))
+		if err != nil {
+			return nil, err
+		}
+		for _, response := range responses {
+			match := imapFetchUID.FindStringSubmatch(response.Line)
+			if match == nil || len(response.Literals) == 0 {
+				continue
+			}
+			message, err := parseMail(response.Literals[0])
+			if err != nil {
+				return nil, err
+			}
+			uid := parseUint32(match[1])
+			if message.ID == "" {
+				message.ID = fmt.Sprintf("uid-%d", uid)
+			}
+			messages[uid] = message
+		}
+	}
+	return messages, nil
+}
+
+// dialIMAP connects to the IMAP server and reads the greeting.
+func dialIMAP(ctx context.Context, host string, port int, insecure bool, literalLimit int) (*imapClient, error) {
+	dialer := &net.Dialer{Timeout: imapTimeout}
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	var conn net.Conn
+	var err error
+	if insecure {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host}}).DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	client := &imapClient{
+		conn:         conn,
+		reader:       bufio.NewReader(conn),
+		literalLimit: literalLimit,
+	}
+	greeting, err := client.readResponse()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !strings.HasPrefix(greeting.Line, "* OK") && !strings.HasPrefix(greeting.Line, "* PREAUTH") {
+		conn.Close()
+		return nil, fmt.Errorf("imap greeting: %s", greeting.Line)
+	}
+	return client, nil
+}
+
+// command sends the tagged command and returns untagged responses.
+// It returns an error if the command is not completed with OK. The connection is closed
+// if the response can not be read, the rest of the response would be taken for the next one.
+func (c *imapClient) command(format string, args ...interface{}) ([]*imapResponse, error) {
+	c.tag++
+	tag := fmt.Sprintf("a%d", c.tag)
+	command := fmt.Sprintf(format, args...)
+	_ = c.conn.SetDeadline(time.Now().Add(imapTimeout))
+	if _, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, command); err != nil {
+		return nil, err
+	}
+	var responses []*imapResponse
+	for {
+		response, err := c.readResponse()
+		if err != nil {
+			c.conn.Close()
+			return nil, err
+		}
+		if !strings.HasPrefix(response.Line, tag+" ") {
+			responses = append(responses, response)
+			continue
+		}
+		status := strings.TrimPrefix(response.Line, tag+" ")
+		if !strings.HasPrefix(status, "OK") {
+			name, _, _ := strings.Cut(command, " ")
+			return nil, fmt.Errorf("imap %s : %s", name, status)
+		}
+		return responses, nil
+	}
+}
+
+// readResponse reads the response line with its literals. Literals larger than the limit are not read.
+func (c *imapClient) readResponse() (*imapResponse, error) {
+	response := &imapResponse{}
+	for {
+		line, err := c.reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		response.Line += line
+		match := imapLiteral.FindStringSubmatch(line)
+		if match == nil {
+			return response, nil
+		}
+		size, err := strconv.Atoi(match[1])
+		if err != nil || size > c.literalLimit {
+			return nil, fmt.Errorf("imap literal of %s bytes exceeds the limit of %d bytes", match[1], c.literalLimit)
+		}
+		literal := make([]byte, size)
+		if _, err = io.ReadFull(c.reader, literal); err != nil {
+			return nil, err
+		}
+		response.Literals = append(response.Literals, literal)
+	}
+}
+
+// imapQuote returns the value as an IMAP quoted string.
+func imapQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// parseUint32 parses the unsigned number, it returns 0 for invalid numbers.
+func parseUint32(value string) uint32 {
+	number, _ := strconv.ParseUint(value, 10, 32)
+	return uint32(number)
+}
+
+// newIMAP creates a reader of the mailbox over IMAP. The connection is opened on the first request.
+// The size of messages is limited to the default file size limit until the connector is executed.
+func newIMAP(param *MailParameters) *imapProvider {
+	return &imapProvider{param: param, sizeLimit: model.GB}
+}
@@ -0,0 +1,40 @@
+package connector
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"context"
+	"github.com/google/uuid"
+)
+
+// IngestionAPI is a connector whose documents are pushed in through the ingestion endpoints
+// of the api service instead of being loaded from an external source.
+type IngestionAPI struct {
+	Base
+}
+
+// Validate always succeeds, the ingestion API connector has no parameters.
+func (c *IngestionAPI) Validate() error {
+	return nil
+}
+
+// PrepareTask does nothing, documents are sent to the semantic service when they are pushed.
+func (c *IngestionAPI) PrepareTask(ctx context.Context, sessionID uuid.UUID, task Task) error {
+	return nil
+}
+
+// Execute marks all pushed documents as existing so that a run of the connector
+// does not delete them, and closes the result channel without results.
+func (c *IngestionAPI) Execute(ctx context.Context, param map[string]string) chan *Response {
+	for _, doc := range c.model.DocsMap {
+		doc.IsExists = true
+	}
+	close(c.resultCh)
+	return c.resultCh
+}
+
+// NewIngestionAPI creates a new instance of the IngestionAPI connector.
+func NewIngestionAPI(connector *model.Connector) (Connector, error) {
+	conn := IngestionAPI{}
+	conn.Base.Config(connector)
+	return &conn, nil
+}
@@ -0,0 +1,459 @@
+package connector
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"cognix.ch/api/v2/core/proto"
+	"cognix.ch/api/v2/core/repository"
+	"cognix.ch/api/v2/core/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/go-pg/pg/v10"
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	jiraSearch   = "/rest/api/2/search"
+	jiraFields   = "/rest/api/2/field"
+	jiraComments = "/rest/api/2/issue/%s/comment"
+	jiraBrowse   = "%s/browse/%s"
+
+	jiraPageLen = 50
+	// jiraTimeLayout is the layout of dates returned by the Jira API.
+	jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+	// jiraQueryLayout is the layout of dates in JQL, dates are interpreted in the time zone of the user.
+	jiraQueryLayout = "2006/01/02 15:04"
+	// jiraWatermarkOverlap covers the difference between UTC and the time zone of the user
+	// in the JQL filter by the update date. Issues that were not updated are skipped by their signature.
+	jiraWatermarkOverlap = 24 * time.Hour
+)
+
+var (
+	jiraOrderBy        = regexp.MustCompile(`(?i)\s*\border\s+by\b.*$`)
+	jiraDefaultFields  = []string{"summary", "description", "status", "assignee", "reporter", "updated", "comment"}
+	jiraValueAttribute = []string{"value", "name", "displayName", "key"}
+)
+
+// Jira is a struct that represents the Jira connector.
+//
+// The struct contains the following fields:
+// - Base: a struct that represents the base properties and methods needed for various connectors.
+// - param: a pointer to the JiraParameters struct that contains the connector parameters.
+// - state: a pointer to the JiraState struct that stores the update date of the last loaded issue.
+// - client: a pointer to the resty.Client struct for making requests to the Jira REST API.
+// - fieldNames: names of custom fields by their ids.
+// - sessionID: a uuid.NullUUID representing the session ID.
+type (
+	Jira struct {
+		Base
+		param      *JiraParameters
+		state      *JiraState
+		client     *resty.Client
+		fieldNames map[string]string
+		sessionID  uuid.NullUUID
+	}
+	// JiraParameters contains the URL of the Jira site, the JQL query selecting issues,
+	// ids of custom fields rendered with issues and credentials.
+	// Jira Cloud uses the email with the API token, Jira Server uses the personal access token only.
+	JiraParameters struct {
+		BaseURL      string            `json:"base_url"`
+		JQL          string            `json:"jql"`
+		CustomFields model.StringSlice `json:"custom_fields"`
+		Email        string            `json:"email"`
+		Token        string            `json:"token"`
+	}
+	// JiraState stores the update date of the last loaded issue. It is used as the watermark for incremental sync.
+	JiraState struct {
+		Updated string `json:"updated"`
+	}
+
+	jiraUser struct {
+		DisplayName string `json:"displayName"`
+	}
+	jiraComment struct {
+		Author  jiraUser `json:"author"`
+		Body    string   `json:"body"`
+		Created string   `json:"created"`
+	}
+	jiraCommentsResponse struct {
+		Comments   []*jiraComment `json:"comments"`
+		Total      int            `json:"total"`
+		StartAt    int            `json:"startAt"`
+		MaxResults int            `json:"maxResults"`
+	}
+	jiraIssue struct {
+		Key    string                     `json:"key"`
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	jiraIssueFields struct {
+		Summary     string    `json:"summary"`
+		Description string    `json:"description"`
+		Updated     string    `json:"updated"`
+		Assignee    *jiraUser `json:"assignee"`
+		Reporter    *jiraUser `json:"reporter"`
+		Status      struct {
+			Name string `json:"name"`
+		} `json:"status"`
+		Comment jiraCommentsResponse `json:"comment"`
+	}
+	jiraSearchResponse struct {
+		Issues     []*jiraIssue `json:"issues"`
+		StartAt    int          `json:"startAt"`
+		MaxResults int          `json:"maxResults"`
+		Total      int          `json:"total"`
+	}
+	jiraField struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+)
+
+// Validate checks if the JiraParameters struct is valid.
+// It returns an error if the URL of the site, the query or the token is missing.
+func (p JiraParameters) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.BaseURL, validation.Required, is.URL),
+		validation.Field(&p.JQL, validation.Required),
+		validation.Field(&p.Token, validation.Required),
+	)
+}
+
+// Validate checks if the Jira parameter is valid.
+func (c *Jira) Validate() error {
+	if c.param == nil {
+		return fmt.Errorf("jira parameter is required")
+	}
+	return c.param.Validate()
+}
+
+// PrepareTask sends the connector request with the session ID to the connector service.
+func (c *Jira) PrepareTask(ctx context.Context, sessionID uuid.UUID, task Task) error {
+	params := make(map[string]string)
+	params[model.ParamSessionID] = sessionID.String()
+	return task.RunConnector(ctx, &proto.ConnectorRequest{
+		Id:     c.model.ID.IntPart(),
+		Params: params,
+	})
+}
+
+// Execute executes the Jira connector with the given context and parameters. It returns a channel of Response
+// objects. Only issues updated since the previous execution are requested, so documents loaded before are kept.
+func (c *Jira) Execute(ctx context.Context, param map[string]string) chan *Response {
+	paramSessionID, _ := param[model.ParamSessionID]
+	if uuidSessionID, err := uuid.Parse(paramSessionID); err != nil {
+		c.sessionID = uuid.NullUUID{uuid.New(), true}
+	} else {
+		c.sessionID = uuid.NullUUID{uuidSessionID, true}
+	}
+	for _, doc := range c.model.DocsMap {
+		doc.IsExists = true
+	}
+	go func() {
+		defer close(c.resultCh)
+		if err := c.execute(ctx); err != nil {
+			zap.S().Errorf("execute %s ", err.Error())
+		}
+	}()
+	return c.resultCh
+}
+
+// execute loads updated issues and saves the watermark in the state of the connector.
+func (c *Jira) execute(ctx context.Context) error {
+	if err := c.loadIssues(ctx); err != nil {
+		return err
+	}
+	zap.S().Infof("save connector state.")
+	if err := c.model.State.FromStruct(c.state); err == nil {
+		return c.connectorRepo.Update(ctx, c.model)
+	}
+	return nil
+}
+
+// loadIssues requests issues matching the query and updated after the watermark in the order of update.
+// The watermark is moved to the update date of loaded issues until the first issue that failed to load,
+// so the failed issue is requested again by the next execution.
+func (c *Jira) loadIssues(ctx context.Context) error {
+	if err := c.loadFieldNames(ctx); err != nil {
+		return err
+	}
+	fields := append(append([]string{}, jiraDefaultFields...), c.param.CustomFields...)
+	jql := c.query()
+	failed := false
+	for startAt := 0; ; {
+		var response jiraSearchResponse
+		if err := c.requestAndParse(ctx, jiraSearch, map[string]string{
+			"jql":        jql,
+			"fields":     strings.Join(fields, ","),
+			"startAt":    strconv.Itoa(startAt),
+			"maxResults": strconv.Itoa(jiraPageLen),
+		}, &response); err != nil {
+			return err
+		}
+		for _, issue := range response.Issues {
+			updated, err := c.loadIssue(ctx, issue)
+			if err != nil {
+				zap.S().Errorf("error loading issue %s : %s", issue.Key, err.Error())
+				failed = true
+				continue
+			}
+			if !failed {
+				c.moveWatermark(updated)
+			}
+		}
+		startAt += len(response.Issues)
+		if len(response.Issues) == 0 || startAt >= response.Total {
+			break
+		}
+	}
+	return nil
+}
+
+// loadIssue renders the issue as markdown and sends it to the result channel if it was updated.
+// The update date is used as a signature. It returns the update date of the issue.
+func (c *Jira) loadIssue(ctx context.Context, issue *jiraIssue) (string, error) {
+	var fields jiraIssueFields
+	if err := remarshal(issue.Fields, &fields); err != nil {
+		return "", err
+	}
+	sourceID := "jira:" + issue.Key
+	doc, ok := c.model.DocsMap[sourceID]
+	if ok && doc.Signature == fields.Updated {
+		return fields.Updated, nil
+	}
+	comments := fields.Comment.Comments
+	if len(comments) < fields.Comment.Total {
+		var err error
+		if comments, err = c.getComments(ctx, issue.Key); err != nil {
+			return "", err
+		}
+	}
+
+	fileName := ""
+	if !ok {
+		doc = &model.Document{
+			SourceID:     sourceID,
+			ConnectorID:  c.model.ID,
+			CreationDate: time.Now().UTC(),
+		}
+		c.model.DocsMap[sourceID] = doc
+	} else {
+		// rewrite the file of the existing document
+		minioFile := strings.Split(doc.URL, ":")
+		if len(minioFile) == 3 && minioFile[0] == "minio" {
+			fileName = minioFile[2]
+		}
+	}
+	if fileName == "" {
+		fileName = utils.StripFileName(c.model.BuildFileName(fmt.Sprintf("%s-%s.md", uuid.New().String(), issue.Key)))
+	}
+	doc.Signature = fields.Updated
+	doc.ChunkingSession = c.sessionID
+	doc.LastUpdate = pg.NullTime{time.Now().UTC()}
+	doc.OriginalURL = fmt.Sprintf(jiraBrowse, strings.TrimSuffix(c.param.BaseURL, "/"), issue.Key)
+	doc.IsExists = true
+
+	c.resultCh <- &Response{
+		URL:        doc.URL,
+		Name:       fileName,
+		SourceID:   sourceID,
+		DocumentID: doc.ID.IntPart(),
+		MimeType:   "text/markdown",
+		FileType:   proto.FileType_MD,
+		Signature:  fields.Updated,
+		Content: &Content{
+			Bucket: model.BucketName(c.model.User.EmbeddingModel.TenantID),
+			Body:   []byte(c.buildMDIssue(issue, &fields, comments)),
+		},
+	}
+	return fields.Updated, nil
+}
+
+// moveWatermark sets the watermark to the update date of the issue if it is newer.
+func (c *Jira) moveWatermark(updated string) {
+	updatedTime, err := time.Parse(jiraTimeLayout, updated)
+	if err != nil {
+		return
+	}
+	if watermark, err := time.Parse(time.RFC3339, c.state.Updated); err != nil || updatedTime.After(watermark) {
+		c.state.Updated = updatedTime.UTC().Format(time.RFC3339)
+	}
+}
+
+// buildMDIssue renders the issue with selected custom fields and comments as markdown.
+func (c *Jira) buildMDIssue(issue *jiraIssue, fields *jiraIssueFields, comments []*jiraComment) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("# %s: %s\n\n", issue.Key, fields.Summary))
+	builder.WriteString(fmt.Sprintf("- Status: %s\n", fields.Status.Name))
+	builder.WriteString(fmt.Sprintf("- Assignee: %s\n", jiraUserName(fields.Assignee, "Unassigned")))
+	builder.WriteString(fmt.Sprintf("- Reporter: %s\n", jiraUserName(fields.Reporter, "")))
+	builder.WriteString(fmt.Sprintf("- Updated: %s\n", fields.Updated))
+	for _, id := range c.param.CustomFields {
+		var value interface{}
+		if raw, ok := issue.Fields[id]; !ok || json.Unmarshal(raw, &value) != nil {
+			continue
+		}
+		if text := jiraFieldValue(value); text != "" {
+			builder.WriteString(fmt.Sprintf("- %s: %s\n", c.fieldName(id), text))
+		}
+	}
+	if fields.Description != "" {
+		builder.WriteString("\n## Description\n\n" + fields.Description + "\n")
+	}
+	if len(comments) > 0 {
+		builder.WriteString("\n## Comments\n")
+		for _, comment := range comments {
+			builder.WriteString(fmt.Sprintf("\n### %s, %s\n\n%s\n", comment.Author.DisplayName, comment.Created, comment.Body))
+		}
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+// query returns the JQL query of the connector limited to issues updated after the watermark.
+func (c *Jira) query() string {
+	jql := jiraOrderBy.ReplaceAllString(c.param.JQL, "")
+	if watermark, err := time.Parse(time.RFC3339, c.state.Updated); err == nil {
+		jql = fmt.Sprintf(`(%s) AND updated >= "%s"`, jql,
+			watermark.Add(-jiraWatermarkOverlap).Format(jiraQueryLayout))
+	}
+	return jql + " ORDER BY updated ASC"
+}
+
+// getComments returns all comments of the issue.
+func (c *Jira) getComments(ctx context.Context, key string) ([]*jiraComment, error) {
+	var comments []*jiraComment
+	for startAt := 0; ; {
+		var response jiraCommentsResponse
+		if err := c.requestAndParse(ctx, fmt.Sprintf(jiraComments, key), map[string]string{
+			"startAt":    strconv.Itoa(startAt),
+			"maxResults": strconv.Itoa(jiraPageLen),
+		}, &response); err != nil {
+			return nil, err
+		}
+		comments = append(comments, response.Comments...)
+		startAt += len(response.Comments)
+		if len(response.Comments) == 0 || startAt >= response.Total {
+			break
+		}
+	}
+	return comments, nil
+}
+
+// loadFieldNames loads names of custom fields rendered with issues.
+func (c *Jira) loadFieldNames(ctx context.Context) error {
+	if len(c.param.CustomFields) == 0 {
+		return nil
+	}
+	var fields []*jiraField
+	if err := c.requestAndParse(ctx, jiraFields, nil, &fields); err != nil {
+		return err
+	}
+	for _, field := range fields {
+		c.fieldNames[field.ID] = field.Name
+	}
+	return nil
+}
+
+// fieldName returns the name of the custom field or its id if the name is unknown.
+func (c *Jira) fieldName(id string) string {
+	if name, ok := c.fieldNames[id]; ok {
+		return name
+	}
+	return id
+}
+
+// requestAndParse sends a GET request to the Jira REST API and parses the response into the result.
+func (c *Jira) requestAndParse(ctx context.Context, url string, params map[string]string, result interface{}) error {
+	response, err := c.client.R().SetContext(ctx).
+		SetQueryParams(params).
+		Get(url)
+	if err = utils.WrapRestyError(response, err); err != nil {
+		return err
+	}
+	return json.Unmarshal(response.Body(), result)
+}
+
+// jiraUserName returns the display name of the user or the default value if the user is not set.
+func jiraUserName(user *jiraUser, defaultValue string) string {
+	if user == nil || user.DisplayName == "" {
+		return defaultValue
+	}
+	return user.DisplayName
+}
+
+// jiraFieldValue renders the value of the custom field. Options, users and other objects
+// are rendered by their value, name or key, lists are joined by commas.
+func jiraFieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case map[string]interface{}:
+		for _, attribute := range jiraValueAttribute {
+			if text, ok := v[attribute]; ok {
+				return jiraFieldValue(text)
+			}
+		}
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if text := jiraFieldValue(item); text != "" {
+				values = append(values, text)
+			}
+		}
+		return strings.Join(values, ", ")
+	}
+	return ""
+}
+
+// remarshal converts raw fields of the issue to the struct.
+func remarshal(fields map[string]json.RawMessage, result interface{}) error {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
+
+// NewJira creates new instance of Jira connector
+func NewJira(connector *model.Connector,
+	connectorRepo repository.ConnectorRepository) (Connector, error) {
+	conn := Jira{
+		Base: Base{
+			connectorRepo: connectorRepo,
+		},
+		param:      &JiraParameters{},
+		state:      &JiraState{},
+		fieldNames: make(map[string]string),
+	}
+	conn.Base.Config(connector)
+
+	if err := connector.ConnectorSpecificConfig.ToStruct(conn.param); err != nil {
+		return nil, err
+	}
+	if err := conn.Validate(); err != nil {
+		return nil, err
+	}
+	if err := connector.State.ToStruct(conn.state); err != nil {
+		zap.S().Infof("can not parse state %v", err)
+	}
+	conn.client = resty.New().
+		SetTimeout(time.Minute).
+		SetBaseURL(strings.TrimSuffix(conn.param.BaseURL, "/"))
+	if conn.param.Email != "" {
+		conn.client.SetBasicAuth(conn.param.Email, conn.param.Token)
+	} else {
+		conn.client.SetAuthToken(conn.param.Token)
+	}
+	return &conn, nil
+}
@@ -0,0 +1,95 @@
+package connector
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJira_LoadIssues(t *testing.T) {
+	var jql string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+		switch r.URL.Path {
+		case jiraFields:
+			body = []map[string]string{{"id": "customfield_100", "name": "Team"}}
+		case jiraSearch:
+			jql = r.URL.Query().Get("jql")
+			body = map[string]interface{}{"total": 2, "startAt": 0, "issues": []map[string]interface{}{
+				{"key": "DOC-1", "fields": map[string]interface{}{
+					"summary": "Unchanged", "updated": "2024-07-01T10:00:00.000+0000",
+					"status": map[string]string{"name": "Done"},
+				}},
+				{"key": "DOC-2", "fields": map[string]interface{}{
+					"summary":         "Login fails",
+					"description":     "Steps to reproduce",
+					"updated":         "2024-07-02T12:30:00.000+0200",
+					"status":          map[string]string{"name": "In Progress"},
+					"reporter":        map[string]string{"displayName": "Alice"},
+					"customfield_100": []map[string]string{{"value": "Backend"}, {"value": "Security"}},
+					"comment":         map[string]interface{}{"total": 2, "comments": []map[string]interface{}{}},
+				}},
+			}}
+		case "/rest/api/2/issue/DOC-2/comment":
+			body = map[string]interface{}{"total": 2, "comments": []map[string]interface{}{
+				{"author": map[string]string{"displayName": "Bob"}, "body": "Reproduced", "created": "2024-07-02"},
+				{"author": map[string]string{"displayName": "Alice"}, "body": "Thanks", "created": "2024-07-02"},
+			}}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	conn, err := NewJira(&model.Connector{
+		ID:   decimal.NewFromInt(1),
+		Type: model.SourceTypeJira,
+		ConnectorSpecificConfig: model.JSONMap{
+			"base_url":      server.URL,
+			"jql":           "project = DOC ORDER BY created DESC",
+			"custom_fields": []string{"customfield_100"},
+			"token":         "token",
+		},
+		State: model.JSONMap{"updated": "2024-07-01T10:00:00Z"},
+		DocsMap: map[string]*model.Document{
+			"jira:DOC-1": {ID: decimal.NewFromInt(1), SourceID: "jira:DOC-1", Signature: "2024-07-01T10:00:00.000+0000"},
+		},
+		User: &model.User{EmbeddingModel: &model.EmbeddingModel{TenantID: uuid.New()}},
+	}, nil)
+	assert.NoError(t, err)
+	c := conn.(*Jira)
+
+	go func() {
+		defer close(c.resultCh)
+		assert.NoError(t, c.loadIssues(context.Background()))
+	}()
+	var responses []*Response
+	for response := range c.resultCh {
+		responses = append(responses, response)
+	}
+
+	assert.Equal(t, `(project = DOC) AND updated >= "2024/06/30 10:00" ORDER BY updated ASC`, jql)
+	if assert.Len(t, responses, 1) {
+		assert.Equal(t, "jira:DOC-2", responses[0].SourceID)
+		assert.Equal(t, "# DOC-2: Login fails\n\n"+
+			"- Status: In Progress\n"+
+			"- Assignee: Unassigned\n"+
+			"- Reporter: Alice\n"+
+			"- Updated: 2024-07-02T12:30:00.000+0200\n"+
+			"- Team: Backend, Security\n\n"+
+			"## Description\n\nSteps to reproduce\n\n"+
+			"## Comments\n\n"+
+			"### Bob, 2024-07-02\n\nReproduced\n\n"+
+			"### Alice, 2024-07-02\n\nThanks", string(responses[0].Content.Body))
+		assert.Equal(t, server.URL+"/browse/DOC-2", c.model.DocsMap["jira:DOC-2"].OriginalURL)
+	}
+	assert.Equal(t, "2024-07-02T10:30:00Z", c.state.Updated)
+}
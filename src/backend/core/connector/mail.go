@@ -0,0 +1,473 @@
+package connector
+
+import (
+	"bytes"
+	"cognix.ch/api/v2/core/model"
+	"cognix.ch/api/v2/core/proto"
+	"cognix.ch/api/v2/core/repository"
+	"cognix.ch/api/v2/core/utils"
+	"context"
+	"encoding/base64"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-pg/pg/v10"
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"io"
+	"jaytaylor.com/html2text"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mailDefaultFolder is the folder analyzed if no folders are configured.
+const mailDefaultFolder = "INBOX"
+
+// Mail is a struct that represents the mailbox connector. Messages are read over IMAP
+// or with the Gmail API if the connector has the Google OAuth token.
+//
+// The struct contains the following fields:
+// - Base: a struct that represents the base properties and methods needed for various connectors.
+// - param: a pointer to the MailParameters struct that contains the connector parameters.
+// - state: a pointer to the MailState struct that stores positions of folders after each execution.
+// - provider: the mailProvider used to read the mailbox.
+// - fileSizeLimit: an integer representing the maximum file size limit.
+// - sessionID: a uuid.NullUUID representing the session ID.
+type (
+	Mail struct {
+		Base
+		param         *MailParameters
+		state         *MailState
+		provider      mailProvider
+		fileSizeLimit int
+		sessionID     uuid.NullUUID
+	}
+	// MailParameters contains folders (labels for Gmail) to analyze and credentials.
+	// The IMAP server uses TLS unless the connection is insecure.
+	// If the token is set, the Gmail API is used instead of IMAP.
+	MailParameters struct {
+		Host     string            `json:"host"`
+		Port     int               `json:"port"`
+		Username string            `json:"username"`
+		Password string            `json:"password"`
+		Insecure bool              `json:"insecure"`
+		Folders  model.StringSlice `json:"folders"`
+		Token    *oauth2.Token     `json:"token"`
+	}
+	// MailState stores positions of folders after each execution.
+	MailState struct {
+		Folders map[string]*MailFolderState `json:"folders"`
+	}
+	// MailFolderState stores UIDVALIDITY and UIDNEXT of the IMAP folder.
+	// Messages with UIDs below UIDNext were loaded. If UIDVALIDITY changes, UIDs are reassigned
+	// by the server and the folder is loaded again. For Gmail, After is the time of the newest loaded message.
+	MailFolderState struct {
+		UIDValidity uint32 `json:"uid_validity"`
+		UIDNext     uint32 `json:"uid_next"`
+		After       int64  `json:"after"`
+	}
+
+	// mailProvider reads threads with new messages of the folder and moves the position of the folder.
+	mailProvider interface {
+		Threads(ctx context.Context, folder string, state *MailFolderState) ([]*mailThread, error)
+		Close() error
+	}
+	mailThread struct {
+		ID       string
+		URL      string
+		Messages []*mailMessage
+	}
+	mailMessage struct {
+		ID          string
+		InReplyTo   string
+		References  []string
+		From        string
+		To          string
+		Subject     string
+		Date        time.Time
+		Text        string
+		HTML        string
+		Attachments []*mailAttachment
+	}
+	mailAttachment struct {
+		Name     string
+		MimeType string
+		Content  []byte
+	}
+)
+
+// Validate checks if the MailParameters struct is valid.
+// The host and credentials of the IMAP server are required if the Gmail token is not set.
+func (p MailParameters) Validate() error {
+	imap := p.Token == nil
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.Host, validation.When(imap, validation.Required)),
+		validation.Field(&p.Username, validation.When(imap, validation.Required)),
+		validation.Field(&p.Password, validation.When(imap, validation.Required)),
+		validation.Field(&p.Port, validation.Min(0), validation.Max(65535)),
+		validation.Field(&p.Token, validation.By(func(value interface{}) error {
+			if p.Token != nil && (p.Token.AccessToken == "" || p.Token.RefreshToken == "" ||
+				p.Token.TokenType == "") {
+				return fmt.Errorf("wrong token")
+			}
+			return nil
+		})),
+	)
+}
+
+// Validate checks if the Mail parameter is valid.
+func (c *Mail) Validate() error {
+	if c.param == nil {
+		return fmt.Errorf("mail parameter is required")
+	}
+	return c.param.Validate()
+}
+
+// PrepareTask sends the connector request with the session ID to the connector service.
+func (c *Mail) PrepareTask(ctx context.Context, sessionID uuid.UUID, task Task) error {
+	params := make(map[string]string)
+	params[model.ParamSessionID] = sessionID.String()
+	return task.RunConnector(ctx, &proto.ConnectorRequest{
+		Id:     c.model.ID.IntPart(),
+		Params: params,
+	})
+}
+
+// Execute executes the mailbox connector with the given context and parameters. It returns a channel of Response
+// objects. Only new messages are requested, so documents loaded before are kept.
+func (c *Mail) Execute(ctx context.Context, param map[string]string) chan *Response {
+	var fileSizeLimit int
+	if size, ok := param[model.ParamFileLimit]; ok {
+		fileSizeLimit, _ = strconv.Atoi(size)
+	}
+	if fileSizeLimit == 0 {
+		fileSizeLimit = 1
+	}
+	c.fileSizeLimit = fileSizeLimit * model.GB
+	if provider, ok := c.provider.(*imapProvider); ok {
+		provider.sizeLimit = c.fileSizeLimit
+	}
+	paramSessionID, _ := param[model.ParamSessionID]
+	if uuidSessionID, err := uuid.Parse(paramSessionID); err != nil {
+		c.sessionID = uuid.NullUUID{uuid.New(), true}
+	} else {
+		c.sessionID = uuid.NullUUID{uuidSessionID, true}
+	}
+	for _, doc := range c.model.DocsMap {
+		doc.IsExists = true
+	}
+	go func() {
+		defer close(c.resultCh)
+		if err := c.execute(ctx); err != nil {
+			zap.S().Errorf("execute %s ", err.Error())
+		}
+	}()
+	return c.resultCh
+}
+
+// execute loads new messages of folders and saves the state of the connector.
+func (c *Mail) execute(ctx context.Context) error {
+	c.loadFolders(ctx)
+	zap.S().Infof("save connector state.")
+	if err := c.model.State.FromStruct(c.state); err == nil {
+		return c.connectorRepo.Update(ctx, c.model)
+	}
+	return nil
+}
+
+// loadFolders sends threads with new messages of the folders. The position of the folder
+// is not moved if the folder can not be loaded.
+func (c *Mail) loadFolders(ctx context.Context) {
+	defer c.provider.Close()
+	folders := c.param.Folders
+	if len(folders) == 0 {
+		folders = model.StringSlice{mailDefaultFolder}
+	}
+	for _, folder := range folders {
+		state, ok := c.state.Folders[folder]
+		if !ok {
+			state = &MailFolderState{}
+		}
+		next := *state
+		threads, err := c.provider.Threads(ctx, folder, &next)
+		if err != nil {
+			zap.S().Errorf("error loading folder %s : %s", folder, err.Error())
+			continue
+		}
+		for _, thread := range threads {
+			c.sendThread(thread)
+		}
+		c.state.Folders[folder] = &next
+	}
+}
+
+// sendThread sends the thread as a markdown document and its attachments of supported types.
+// The id of the last message is used as a signature of the thread.
+func (c *Mail) sendThread(thread *mailThread) {
+	if len(thread.Messages) == 0 {
+		return
+	}
+	sort.SliceStable(thread.Messages, func(i, j int) bool {
+		return thread.Messages[i].Date.Before(thread.Messages[j].Date)
+	})
+	last := thread.Messages[len(thread.Messages)-1]
+	c.send(c.sourceID("thread:"+thread.ID), "thread.md", last.ID, thread.URL,
+		"text/markdown", proto.FileType_MD, []byte(buildMailThreadMD(thread)))
+
+	for _, message := range thread.Messages {
+		for _, attachment := range message.Attachments {
+			sourceID := c.sourceID(fmt.Sprintf("attachment:%s:%s", message.ID, attachment.Name))
+			if _, ok := c.model.DocsMap[sourceID]; ok || len(attachment.Content) > c.fileSizeLimit {
+				continue
+			}
+			mimeType, fileType, ok := mailAttachmentType(attachment)
+			if !ok {
+				continue
+			}
+			c.send(sourceID, attachment.Name, message.ID, thread.URL, mimeType, fileType, attachment.Content)
+		}
+	}
+}
+
+// send creates or updates the document and sends its content to the result channel.
+// The file of the existing document is overwritten.
+func (c *Mail) send(sourceID, name, signature, originalURL, mimeType string, fileType proto.FileType, content []byte) {
+	doc, ok := c.model.DocsMap[sourceID]
+	fileName := ""
+	if !ok {
+		doc = &model.Document{
+			SourceID:     sourceID,
+			ConnectorID:  c.model.ID,
+			CreationDate: time.Now().UTC(),
+		}
+		c.model.DocsMap[sourceID] = doc
+	} else {
+		minioFile := strings.Split(doc.URL, ":")
+		if len(minioFile) == 3 && minioFile[0] == "minio" {
+			fileName = minioFile[2]
+		}
+	}
+	if fileName == "" {
+		fileName = utils.StripFileName(c.model.BuildFileName(uuid.New().String() + "-" + name))
+	}
+	doc.Signature = signature
+	doc.ChunkingSession = c.sessionID
+	doc.LastUpdate = pg.NullTime{time.Now().UTC()}
+	doc.OriginalURL = originalURL
+	doc.IsExists = true
+
+	c.resultCh <- &Response{
+		URL:        doc.URL,
+		Name:       fileName,
+		SourceID:   sourceID,
+		DocumentID: doc.ID.IntPart(),
+		MimeType:   mimeType,
+		FileType:   fileType,
+		Signature:  signature,
+		Content: &Content{
+			Bucket: model.BucketName(c.model.User.EmbeddingModel.TenantID),
+			Body:   content,
+		},
+	}
+}
+
+// sourceID returns the source id of the document prefixed with the source type.
+func (c *Mail) sourceID(id string) string {
+	return string(c.model.Type) + ":" + id
+}
+
+// buildMailThreadMD renders messages of the thread as markdown.
+func buildMailThreadMD(thread *mailThread) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("# %s\n", thread.Messages[0].Subject))
+	for _, message := range thread.Messages {
+		builder.WriteString(fmt.Sprintf("\n## %s, %s\n\n", message.From, message.Date.UTC().Format(time.RFC1123)))
+		if message.To != "" {
+			builder.WriteString(fmt.Sprintf("To: %s\n\n", message.To))
+		}
+		builder.WriteString(strings.TrimSpace(message.Text) + "\n")
+		for _, attachment := range message.Attachments {
+			builder.WriteString(fmt.Sprintf("\nAttachment: %s\n", attachment.Name))
+		}
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+// mailAttachmentType returns the type of the attachment by its mime type or by the extension of its name.
+func mailAttachmentType(attachment *mailAttachment) (string, proto.FileType, bool) {
+	if fileType, ok := model.SupportedMimeTypes[attachment.MimeType]; ok {
+		return attachment.MimeType, fileType, true
+	}
+	ext := strings.ToUpper(strings.TrimPrefix(path.Ext(attachment.Name), "."))
+	if mimeType, ok := model.SupportedExtensions[ext]; ok {
+		return mimeType, model.SupportedMimeTypes[mimeType], true
+	}
+	return "", proto.FileType_UNKNOWN, false
+}
+
+// threadID returns the id of the thread of the message: the first message of references
+// or the message it replies to. A message without references starts a new thread.
+func (m *mailMessage) threadID() string {
+	if len(m.References) > 0 {
+		return m.References[0]
+	}
+	if m.InReplyTo != "" {
+		return m.InReplyTo
+	}
+	return m.ID
+}
+
+// parseMail parses the message in the RFC 5322 format. The plain text part is used as the text of the message,
+// the html part is converted to text if there is no plain text part. Parts with file names are attachments.
+func parseMail(raw []byte) (*mailMessage, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	message := &mailMessage{
+		ID:         trimMessageID(msg.Header.Get("Message-Id")),
+		InReplyTo:  trimMessageID(msg.Header.Get("In-Reply-To")),
+		From:       decodeMailHeader(msg.Header.Get("From")),
+		To:         decodeMailHeader(msg.Header.Get("To")),
+		Subject:    decodeMailHeader(msg.Header.Get("Subject")),
+		References: strings.Fields(msg.Header.Get("References")),
+	}
+	for i, reference := range message.References {
+		message.References[i] = trimMessageID(reference)
+	}
+	message.Date, _ = msg.Header.Date()
+	if err = message.parsePart(textproto.MIMEHeader(msg.Header), msg.Body); err != nil {
+		return nil, err
+	}
+	if message.Text == "" && message.HTML != "" {
+		if message.Text, err = html2text.FromString(message.HTML, html2text.Options{
+			PrettyTables: true,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return message, nil
+}
+
+// parsePart parses the part of the message and its nested parts.
+func (m *mailMessage) parsePart(header textproto.MIMEHeader, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err = m.parsePart(part.Header, part); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	fileName := decodeMailHeader(dispositionParams["filename"])
+	if fileName == "" {
+		fileName = decodeMailHeader(params["name"])
+	}
+	switch {
+	case disposition == "attachment" || (fileName != "" && !strings.HasPrefix(mediaType, "text/")):
+		if fileName == "" {
+			fileName = "attachment"
+		}
+		m.Attachments = append(m.Attachments, &mailAttachment{
+			Name:     fileName,
+			MimeType: mediaType,
+			Content:  data,
+		})
+	case mediaType == "text/plain" && m.Text == "":
+		m.Text = string(data)
+	case mediaType == "text/html" && m.HTML == "":
+		m.HTML = string(data)
+	}
+	return nil
+}
+
+// decodeMailHeader decodes encoded words of the header value.
+func decodeMailHeader(value string) string {
+	decoded, err := new(mime.WordDecoder).DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// trimMessageID removes angle brackets around the message id.
+func trimMessageID(id string) string {
+	return strings.Trim(strings.TrimSpace(id), "<>")
+}
+
+// NewMail creates new instance of the mailbox connector
+func NewMail(connector *model.Connector,
+	connectorRepo repository.ConnectorRepository,
+	oauthURL string) (Connector, error) {
+	conn := Mail{
+		Base: Base{
+			connectorRepo: connectorRepo,
+			oauthClient: resty.New().
+				SetTimeout(time.Minute).
+				SetBaseURL(oauthURL),
+		},
+		param: &MailParameters{},
+		state: &MailState{},
+	}
+	conn.Base.Config(connector)
+
+	if err := connector.ConnectorSpecificConfig.ToStruct(conn.param); err != nil {
+		return nil, err
+	}
+	if err := conn.Validate(); err != nil {
+		return nil, err
+	}
+	if err := connector.State.ToStruct(conn.state); err != nil {
+		zap.S().Infof("can not parse state %v", err)
+	}
+	if conn.state.Folders == nil {
+		conn.state.Folders = make(map[string]*MailFolderState)
+	}
+	if conn.param.Token == nil {
+		conn.provider = newIMAP(conn.param)
+		return &conn, nil
+	}
+	newToken, err := conn.refreshToken(conn.param.Token)
+	if err != nil {
+		return nil, err
+	}
+	if newToken != nil {
+		conn.param.Token = newToken
+	}
+	if conn.provider, err = newGmail(conn.param.Token); err != nil {
+		return nil, err
+	}
+	return &conn, nil
+}
@@ -0,0 +1,216 @@
+package connector
+
+import (
+	"bufio"
+	"cognix.ch/api/v2/core/model"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var imapTestMessages = map[uint32]string{
+	1: "Message-ID: <a@test>\r\n" +
+		"From: Alice <alice@test.com>\r\n" +
+		"To: team@test.com\r\n" +
+		"Subject: Release\r\n" +
+		"Date: Mon, 01 Jul 2024 10:00:00 +0000\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" +
+		"Release is planned for Friday.\r\n",
+	2: "Message-ID: <b@test>\r\n" +
+		"In-Reply-To: <a@test>\r\n" +
+		"References: <a@test>\r\n" +
+		"From: Bob <bob@test.com>\r\n" +
+		"Subject: Re: Release\r\n" +
+		"Date: Mon, 01 Jul 2024 11:00:00 +0000\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"boundary\"\r\n" +
+		"\r\n" +
+		"--boundary\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" +
+		"Notes are attached.\r\n" +
+		"--boundary\r\n" +
+		"Content-Type: text/plain; name=\"notes.txt\"\r\n" +
+		"Content-Disposition: attachment; filename=\"notes.txt\"\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"\r\n" +
+		"cmVsZWFzZSBub3Rlcw==\r\n" +
+		"--boundary--\r\n",
+	3: "Message-ID: <c@test>\r\n" +
+		"From: Carol <carol@test.com>\r\n" +
+		"Subject: =?UTF-8?Q?Caf=C3=A9?=\r\n" +
+		"Date: Mon, 01 Jul 2024 12:00:00 +0000\r\n" +
+		"Content-Type: text/html; charset=utf-8\r\n" +
+		"Content-Transfer-Encoding: quoted-printable\r\n" +
+		"\r\n" +
+		"<p>Lunch at <b>noon</b>=3F</p>\r\n",
+}
+
+// serveIMAP is a stand-in of the IMAP server that supports commands used by the connector.
+func serveIMAP(t *testing.T, listener net.Listener, uidValidity int) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	fmt.Fprintf(conn, "* OK IMAP ready\r\n")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		tag, command, _ := strings.Cut(strings.TrimSpace(line), " ")
+		switch {
+		case strings.HasPrefix(command, "LOGIN"):
+			if command != `LOGIN "user" "password"` {
+				fmt.Fprintf(conn, "%s NO invalid credentials\r\n", tag)
+				continue
+			}
+		case strings.HasPrefix(command, "EXAMINE"):
+			fmt.Fprintf(conn, "* OK [UIDVALIDITY %d] UIDs valid\r\n* OK [UIDNEXT 4] Predicted next UID\r\n", uidValidity)
+		case strings.HasPrefix(command, "UID SEARCH UID "):
+			from, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(command, "UID SEARCH UID "), ":*"))
+			var uids []string
+			for uid := uint32(1); uid <= 3; uid++ {
+				if int(uid) >= from {
+					uids = append(uids, strconv.Itoa(int(uid)))
+				}
+			}
+			fmt.Fprintf(conn, "* SEARCH %s\r\n", strings.Join(uids, " "))
+		case strings.HasPrefix(command, "UID SEARCH OR HEADER Message-ID "):
+			id := strings.Split(command, `"`)[1]
+			var uids []string
+			for uid := uint32(1); uid <= 3; uid++ {
+				if strings.Contains(imapTestMessages[uid], "<"+id+">") {
+					uids = append(uids, strconv.Itoa(int(uid)))
+				}
+			}
+			fmt.Fprintf(conn, "* SEARCH %s\r\n", strings.Join(uids, " "))
+		case strings.HasPrefix(command, "UID FETCH "):
+			set := strings.Fields(command)[2]
+			for i, uid := range strings.Split(set, ",") {
+				number, _ := strconv.Atoi(uid)
+				message := imapTestMessages[uint32(number)]
+				fmt.Fprintf(conn, "* %d FETCH (UID %s BODY[] {%d}\r\n%s)\r\n", i+1, uid, len(message), message)
+			}
+		case command == "LOGOUT":
+			fmt.Fprintf(conn, "* BYE\r\n%s OK LOGOUT completed\r\n", tag)
+			return
+		default:
+			fmt.Fprintf(conn, "%s BAD unknown command\r\n", tag)
+			continue
+		}
+		fmt.Fprintf(conn, "%s OK completed\r\n", tag)
+	}
+}
+
+func newIMAPTestConnector(t *testing.T, address string, state model.JSONMap) *Mail {
+	host, portValue, _ := net.SplitHostPort(address)
+	port, _ := strconv.Atoi(portValue)
+	conn, err := NewMail(&model.Connector{
+		ID:   decimal.NewFromInt(1),
+		Type: model.SourceTypeGMAIL,
+		ConnectorSpecificConfig: model.JSONMap{
+			"host":     host,
+			"port":     port,
+			"username": "user",
+			"password": "password",
+			"insecure": true,
+		},
+		State:   state,
+		DocsMap: make(map[string]*model.Document),
+		User:    &model.User{EmbeddingModel: &model.EmbeddingModel{TenantID: uuid.New()}},
+	}, nil, "")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	c := conn.(*Mail)
+	c.fileSizeLimit = model.GB
+	return c
+}
+
+func loadMailFolders(c *Mail) map[string]*Response {
+	go func() {
+		defer close(c.resultCh)
+		c.loadFolders(context.Background())
+	}()
+	responses := make(map[string]*Response)
+	for response := range c.resultCh {
+		responses[response.SourceID] = response
+	}
+	return responses
+}
+
+func TestMail_IMAP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	go serveIMAP(t, listener, 7)
+
+	c := newIMAPTestConnector(t, listener.Addr().String(), model.JSONMap{
+		"folders": map[string]interface{}{"INBOX": map[string]interface{}{"uid_validity": 7, "uid_next": 2}},
+	})
+	responses := loadMailFolders(c)
+	assert.Len(t, responses, 3)
+
+	thread, ok := responses["gmail:thread:a@test"]
+	if assert.True(t, ok) {
+		assert.Equal(t, "b@test", thread.Signature)
+		assert.Equal(t, "# Release\n\n"+
+			"## Alice <alice@test.com>, Mon, 01 Jul 2024 10:00:00 UTC\n\n"+
+			"To: team@test.com\n\n"+
+			"Release is planned for Friday.\n\n"+
+			"## Bob <bob@test.com>, Mon, 01 Jul 2024 11:00:00 UTC\n\n"+
+			"Notes are attached.\n\n"+
+			"Attachment: notes.txt", string(thread.Content.Body))
+	}
+	html, ok := responses["gmail:thread:c@test"]
+	if assert.True(t, ok) {
+		assert.Contains(t, string(html.Content.Body), "# Café")
+		assert.Contains(t, string(html.Content.Body), "Lunch at *noon*")
+	}
+	attachment, ok := responses["gmail:attachment:b@test:notes.txt"]
+	if assert.True(t, ok) {
+		assert.Equal(t, "release notes", string(attachment.Content.Body))
+		assert.Equal(t, "text/plain", attachment.MimeType)
+	}
+	assert.Equal(t, &MailFolderState{UIDValidity: 7, UIDNext: 4}, c.state.Folders["INBOX"])
+}
+
+func TestMail_IMAPSizeLimit(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	go serveIMAP(t, listener, 7)
+
+	c := newIMAPTestConnector(t, listener.Addr().String(), model.JSONMap{
+		"folders": map[string]interface{}{"INBOX": map[string]interface{}{"uid_validity": 7, "uid_next": 2}},
+	})
+	// messages larger than the limit fail the fetch, the folder is not moved
+	c.provider.(*imapProvider).sizeLimit = 10
+	responses := loadMailFolders(c)
+	assert.Len(t, responses, 0)
+	assert.Equal(t, &MailFolderState{UIDValidity: 7, UIDNext: 2}, c.state.Folders["INBOX"])
+}
+
+func TestMail_IMAPUIDValidityChanged(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	go serveIMAP(t, listener, 8)
+
+	c := newIMAPTestConnector(t, listener.Addr().String(), model.JSONMap{
+		"folders": map[string]interface{}{"INBOX": map[string]interface{}{"uid_validity": 7, "uid_next": 4}},
+	})
+	// UIDs were reassigned, all messages are loaded again
+	responses := loadMailFolders(c)
+	assert.Len(t, responses, 3)
+	assert.Equal(t, &MailFolderState{UIDValidity: 8, UIDNext: 4}, c.state.Folders["INBOX"])
+}
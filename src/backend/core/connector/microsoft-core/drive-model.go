@@ -6,7 +6,8 @@ import (
 )
 
 type DriveResponse struct {
-	Value []*DriveChildBody `json:"value"`
+	Value    []*DriveChildBody `json:"value"`
+	NextLink string            `json:"@odata.nextLink"`
 }
 
 type DriveChildBody struct {
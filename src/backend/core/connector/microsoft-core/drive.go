@@ -81,16 +81,7 @@ func NewMSDrive(param *MSDriveParam,
 // it returns the error. Otherwise, it returns nil.
 func (c *MSDrive) Execute(ctx context.Context, fileSizeLimit int) error {
 	c.fileSizeLimit = fileSizeLimit
-	body, err := c.request(ctx, c.baseURL)
-	if err != nil {
-		return err
-	}
-	if body != nil {
-		if err = c.handleItems(ctx, "", body.Value); err != nil {
-			return err
-		}
-	}
-	return nil
+	return c.handleFolder(ctx, "", c.baseURL)
 }
 
 // DownloadItem performs the download of a specific item from the MSDrive instance.
@@ -219,11 +210,23 @@ func (c *MSDrive) recognizeFiletype(item *DriveChildBody) (string, proto.FileTyp
 // It makes a request to the folderURL with the provided ID and gets the response body.
 // If there is an error during the request, it returns the error. Otherwise, it calls the handleItems method passing the folder and response body.
 func (c *MSDrive) getFolder(ctx context.Context, folder string, id string) error {
-	body, err := c.request(ctx, fmt.Sprintf(c.folderURL, id))
-	if err != nil {
-		return err
+	return c.handleFolder(ctx, folder, fmt.Sprintf(c.folderURL, id))
+}
+
+// handleFolder requests items of the folder page by page following @odata.nextLink
+// and handles items of each page.
+func (c *MSDrive) handleFolder(ctx context.Context, folder string, url string) error {
+	for url != "" {
+		body, err := c.request(ctx, url)
+		if err != nil {
+			return err
+		}
+		if err = c.handleItems(ctx, folder, body.Value); err != nil {
+			return err
+		}
+		url = body.NextLink
 	}
-	return c.handleItems(ctx, folder, body.Value)
+	return nil
 }
 
 // handleItems handles the items of a Microsoft Drive.
@@ -196,8 +196,10 @@ func (c *MSTeams) Execute(ctx context.Context, param map[string]string) chan *Re
 // If the AnalyzeChats flag is disabled, it skips the chat loading step.
 //
 // If the teamID parameter is provided, it loads the channels for the specified team.
+// If the parameter is not provided but the team is configured, like for a sync started by a user,
+// the team ID is requested first.
 // If any error occurs during loading channels, it logs the error and continues execution.
-// If the teamID parameter is not provided, it skips the channel loading step.
+// If no team is configured, it skips the channel loading step.
 //
 // After executing the above steps, it saves the current state of the connector.
 // If the state saving is successful, it updates the connector in the connector repository.
@@ -224,7 +226,19 @@ func (c *MSTeams) execute(ctx context.Context, param map[string]string) error {
 		}
 	}
 
-	if teamID, ok := param[msTeamsParamTeamID]; ok {
+	teamID, ok := param[msTeamsParamTeamID]
+	if !ok && c.param.Team != "" {
+		var err error
+		if teamID, err = c.getTeamID(ctx); err != nil {
+			// channels can not be checked, keep their documents
+			for _, doc := range c.model.DocsMap {
+				doc.IsExists = true
+			}
+			return fmt.Errorf("get teamID : %s", err.Error())
+		}
+		ok = true
+	}
+	if ok {
 		if err := c.loadChannels(ctx, teamID); err != nil {
 			zap.S().Errorf("error loading channels : %s ", err.Error())
 			//return fmt.Errorf("load channels : %s", err.Error())
@@ -0,0 +1,463 @@
+package connector
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// notionRichText is a segment of the rich text with its formatting.
+	notionRichText struct {
+		PlainText   string `json:"plain_text"`
+		Href        string `json:"href"`
+		Annotations struct {
+			Bold          bool `json:"bold"`
+			Italic        bool `json:"italic"`
+			Strikethrough bool `json:"strikethrough"`
+			Code          bool `json:"code"`
+		} `json:"annotations"`
+	}
+	// notionBlock is a block of the page. Content contains attributes of the block stored under the key of its type.
+	notionBlock struct {
+		ID          string `json:"id"`
+		Type        string `json:"type"`
+		HasChildren bool   `json:"has_children"`
+		Content     notionBlockContent
+		Children    []*notionBlock
+	}
+	notionBlockContent struct {
+		RichText        []*notionRichText   `json:"rich_text"`
+		Caption         []*notionRichText   `json:"caption"`
+		Checked         bool                `json:"checked"`
+		Language        string              `json:"language"`
+		URL             string              `json:"url"`
+		Expression      string              `json:"expression"`
+		Cells           [][]*notionRichText `json:"cells"`
+		HasColumnHeader bool                `json:"has_column_header"`
+		Icon            *struct {
+			Emoji string `json:"emoji"`
+		} `json:"icon"`
+	}
+
+	notionOption struct {
+		Name string `json:"name"`
+	}
+	notionUser struct {
+		Name string `json:"name"`
+	}
+	notionDate struct {
+		Start string  `json:"start"`
+		End   *string `json:"end"`
+	}
+	// notionProperty is the value of the property of the database row.
+	notionProperty struct {
+		Type           string            `json:"type"`
+		Title          []*notionRichText `json:"title"`
+		RichText       []*notionRichText `json:"rich_text"`
+		Number         *float64          `json:"number"`
+		Select         *notionOption     `json:"select"`
+		Status         *notionOption     `json:"status"`
+		MultiSelect    []*notionOption   `json:"multi_select"`
+		Date           *notionDate       `json:"date"`
+		People         []*notionUser     `json:"people"`
+		Checkbox       bool              `json:"checkbox"`
+		URL            *string           `json:"url"`
+		Email          *string           `json:"email"`
+		PhoneNumber    *string           `json:"phone_number"`
+		CreatedTime    string            `json:"created_time"`
+		LastEditedTime string            `json:"last_edited_time"`
+		CreatedBy      *notionUser       `json:"created_by"`
+		LastEditedBy   *notionUser       `json:"last_edited_by"`
+		Files          []*notionOption   `json:"files"`
+		Relation       []struct {
+			ID string `json:"id"`
+		} `json:"relation"`
+		Formula *struct {
+			Type    string      `json:"type"`
+			String  *string     `json:"string"`
+			Number  *float64    `json:"number"`
+			Boolean *bool       `json:"boolean"`
+			Date    *notionDate `json:"date"`
+		} `json:"formula"`
+		Rollup *struct {
+			Type   string            `json:"type"`
+			Number *float64          `json:"number"`
+			Date   *notionDate       `json:"date"`
+			Array  []*notionProperty `json:"array"`
+		} `json:"rollup"`
+		UniqueID *struct {
+			Prefix *string `json:"prefix"`
+			Number int     `json:"number"`
+		} `json:"unique_id"`
+	}
+)
+
+// UnmarshalJSON decodes common attributes of the block and attributes stored under the key of its type.
+func (b *notionBlock) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for key, target := range map[string]interface{}{"id": &b.ID, "type": &b.Type, "has_children": &b.HasChildren} {
+		if value, ok := fields[key]; ok {
+			if err := json.Unmarshal(value, target); err != nil {
+				return err
+			}
+		}
+	}
+	if content, ok := fields[b.Type]; ok {
+		return json.Unmarshal(content, &b.Content)
+	}
+	return nil
+}
+
+// notionBlocksToMarkdown renders blocks with their children as markdown.
+// Items of the same list are separated by a line break, other blocks by an empty line.
+func notionBlocksToMarkdown(blocks []*notionBlock) string {
+	var builder strings.Builder
+	number := 0
+	previousList := false
+	for _, block := range blocks {
+		if block.Type == "numbered_list_item" {
+			number++
+		} else {
+			number = 0
+		}
+		text := notionBlockToMarkdown(block, number)
+		if text == "" {
+			continue
+		}
+		list := notionListBlock(block.Type)
+		if builder.Len() > 0 {
+			if list && previousList {
+				builder.WriteString("\n")
+			} else {
+				builder.WriteString("\n\n")
+			}
+		}
+		builder.WriteString(text)
+		previousList = list
+	}
+	return builder.String()
+}
+
+// notionBlockToMarkdown renders the block. Children of list items and toggles are indented,
+// children of other blocks are rendered after the block.
+func notionBlockToMarkdown(block *notionBlock, number int) string {
+	text := notionRichTextToMarkdown(block.Content.RichText)
+	children := notionBlocksToMarkdown(block.Children)
+	var result string
+	switch block.Type {
+	case "paragraph":
+		result = text
+	case "heading_1", "heading_2", "heading_3":
+		// the title of the page is the first level heading
+		result = strings.Repeat("#", int(block.Type[len(block.Type)-1]-'0')+1) + " " + text
+	case "bulleted_list_item", "toggle":
+		return notionListItem("- "+text, children)
+	case "numbered_list_item":
+		return notionListItem(strconv.Itoa(number)+". "+text, children)
+	case "to_do":
+		check := " "
+		if block.Content.Checked {
+			check = "x"
+		}
+		return notionListItem(fmt.Sprintf("- [%s] %s", check, text), children)
+	case "quote":
+		return notionQuote(joinNotEmpty(text, children))
+	case "callout":
+		if block.Content.Icon != nil && block.Content.Icon.Emoji != "" {
+			text = block.Content.Icon.Emoji + " " + text
+		}
+		return notionQuote(joinNotEmpty(text, children))
+	case "code":
+		var code strings.Builder
+		for _, segment := range block.Content.RichText {
+			code.WriteString(segment.PlainText)
+		}
+		language := block.Content.Language
+		if language == "plain text" {
+			language = ""
+		}
+		result = fmt.Sprintf("```%s\n%s\n```", language, code.String())
+	case "equation":
+		result = "$$" + block.Content.Expression + "$$"
+	case "divider":
+		result = "---"
+	case "table":
+		return notionTable(block)
+	case "bookmark", "embed", "link_preview":
+		if caption := notionRichTextToMarkdown(block.Content.Caption); caption != "" {
+			result = fmt.Sprintf("[%s](%s)", caption, block.Content.URL)
+		} else {
+			result = block.Content.URL
+		}
+	case "image", "video", "file", "pdf", "audio":
+		result = notionRichTextToMarkdown(block.Content.Caption)
+	case "child_page", "child_database":
+		// child pages and databases are loaded as separate documents
+		return ""
+	default:
+		// column lists, columns, synced blocks and unsupported blocks
+		result = text
+	}
+	return joinNotEmpty(result, children)
+}
+
+// notionListItem renders the item of the list with its children indented.
+func notionListItem(item, children string) string {
+	if children == "" {
+		return item
+	}
+	return item + "\n" + indentLines(children, "  ")
+}
+
+// notionQuote prefixes lines of the text with the quote marker.
+func notionQuote(text string) string {
+	if text == "" {
+		return ""
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// notionTable renders rows of the table. The empty header is added if the table has no column header.
+func notionTable(block *notionBlock) string {
+	var rows []string
+	columns := 0
+	for _, row := range block.Children {
+		var cells []string
+		for _, cell := range row.Content.Cells {
+			cells = append(cells, strings.ReplaceAll(notionRichTextToMarkdown(cell), "|", `\|`))
+		}
+		columns = max(columns, len(cells))
+		rows = append(rows, "| "+strings.Join(cells, " | ")+" |")
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+	separator := strings.Repeat("| --- ", columns) + "|"
+	if !block.Content.HasColumnHeader {
+		return strings.Join(append([]string{strings.Repeat("| ", columns) + "|", separator}, rows...), "\n")
+	}
+	return strings.Join(append([]string{rows[0], separator}, rows[1:]...), "\n")
+}
+
+// notionRichTextToMarkdown renders segments of the rich text with their formatting and links.
+func notionRichTextToMarkdown(segments []*notionRichText) string {
+	var builder strings.Builder
+	for _, segment := range segments {
+		text := segment.PlainText
+		if strings.TrimSpace(text) != "" {
+			if segment.Annotations.Code {
+				text = "`" + text + "`"
+			}
+			if segment.Annotations.Bold {
+				text = "**" + text + "**"
+			}
+			if segment.Annotations.Italic {
+				text = "*" + text + "*"
+			}
+			if segment.Annotations.Strikethrough {
+				text = "~~" + text + "~~"
+			}
+			if segment.Href != "" {
+				text = fmt.Sprintf("[%s](%s)", text, segment.Href)
+			}
+		}
+		builder.WriteString(text)
+	}
+	return builder.String()
+}
+
+// notionPlainText returns the text of the rich text without formatting.
+func notionPlainText(segments []*notionRichText) string {
+	var builder strings.Builder
+	for _, segment := range segments {
+		builder.WriteString(segment.PlainText)
+	}
+	return builder.String()
+}
+
+// notionPropertiesToMarkdown renders properties of the database row except the title as a list sorted by name.
+func notionPropertiesToMarkdown(properties map[string]*notionProperty) string {
+	names := make([]string, 0, len(properties))
+	for name, property := range properties {
+		if property.Type != "title" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var lines []string
+	for _, name := range names {
+		if value := notionPropertyValue(properties[name]); value != "" {
+			lines = append(lines, fmt.Sprintf("- %s: %s", name, value))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// notionPropertyValue renders the value of the property as text. Lists are joined by commas.
+func notionPropertyValue(property *notionProperty) string {
+	switch property.Type {
+	case "title":
+		return notionPlainText(property.Title)
+	case "rich_text":
+		return notionRichTextToMarkdown(property.RichText)
+	case "number":
+		return notionNumber(property.Number)
+	case "select":
+		if property.Select != nil {
+			return property.Select.Name
+		}
+	case "status":
+		if property.Status != nil {
+			return property.Status.Name
+		}
+	case "multi_select":
+		var values []string
+		for _, option := range property.MultiSelect {
+			values = append(values, option.Name)
+		}
+		return strings.Join(values, ", ")
+	case "date":
+		return notionDateValue(property.Date)
+	case "people":
+		var values []string
+		for _, user := range property.People {
+			values = append(values, user.Name)
+		}
+		return strings.Join(values, ", ")
+	case "files":
+		var values []string
+		for _, file := range property.Files {
+			values = append(values, file.Name)
+		}
+		return strings.Join(values, ", ")
+	case "checkbox":
+		return strconv.FormatBool(property.Checkbox)
+	case "url":
+		return notionString(property.URL)
+	case "email":
+		return notionString(property.Email)
+	case "phone_number":
+		return notionString(property.PhoneNumber)
+	case "created_time":
+		return property.CreatedTime
+	case "last_edited_time":
+		return property.LastEditedTime
+	case "created_by":
+		if property.CreatedBy != nil {
+			return property.CreatedBy.Name
+		}
+	case "last_edited_by":
+		if property.LastEditedBy != nil {
+			return property.LastEditedBy.Name
+		}
+	case "relation":
+		if len(property.Relation) > 0 {
+			return fmt.Sprintf("%d linked pages", len(property.Relation))
+		}
+	case "formula":
+		if formula := property.Formula; formula != nil {
+			switch formula.Type {
+			case "string":
+				return notionString(formula.String)
+			case "number":
+				return notionNumber(formula.Number)
+			case "boolean":
+				if formula.Boolean != nil {
+					return strconv.FormatBool(*formula.Boolean)
+				}
+			case "date":
+				return notionDateValue(formula.Date)
+			}
+		}
+	case "rollup":
+		if rollup := property.Rollup; rollup != nil {
+			switch rollup.Type {
+			case "number":
+				return notionNumber(rollup.Number)
+			case "date":
+				return notionDateValue(rollup.Date)
+			case "array":
+				var values []string
+				for _, item := range rollup.Array {
+					if value := notionPropertyValue(item); value != "" {
+						values = append(values, value)
+					}
+				}
+				return strings.Join(values, ", ")
+			}
+		}
+	case "unique_id":
+		if id := property.UniqueID; id != nil {
+			if id.Prefix != nil && *id.Prefix != "" {
+				return fmt.Sprintf("%s-%d", *id.Prefix, id.Number)
+			}
+			return strconv.Itoa(id.Number)
+		}
+	}
+	return ""
+}
+
+func notionNumber(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+func notionString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func notionDateValue(date *notionDate) string {
+	if date == nil {
+		return ""
+	}
+	if date.End != nil && *date.End != "" {
+		return date.Start + " - " + *date.End
+	}
+	return date.Start
+}
+
+// notionListBlock checks if blocks of the type are rendered as items of the list.
+func notionListBlock(blockType string) bool {
+	switch blockType {
+	case "bulleted_list_item", "numbered_list_item", "to_do", "toggle":
+		return true
+	}
+	return false
+}
+
+// indentLines prefixes not empty lines of the text with the indent.
+func indentLines(text, indent string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// joinNotEmpty joins not empty parts with an empty line.
+func joinNotEmpty(parts ...string) string {
+	var result []string
+	for _, part := range parts {
+		if part != "" {
+			result = append(result, part)
+		}
+	}
+	return strings.Join(result, "\n\n")
+}
@@ -0,0 +1,462 @@
+package connector
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"cognix.ch/api/v2/core/proto"
+	"cognix.ch/api/v2/core/repository"
+	"cognix.ch/api/v2/core/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-pg/pg/v10"
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	notionAPIURL         = "https://api.notion.com/v1"
+	notionVersion        = "2022-06-28"
+	notionSearch         = "/search"
+	notionDatabaseQuery  = "/databases/%s/query"
+	notionBlockURL       = "/blocks/%s"
+	notionBlockChildren  = "/blocks/%s/children"
+	notionPageLen        = 100
+	notionMaxParentDepth = 10
+)
+
+// Notion is a struct that represents the Notion connector.
+//
+// The struct contains the following fields:
+// - Base: a struct that represents the base properties and methods needed for various connectors.
+// - param: a pointer to the NotionParameters struct that contains the connector parameters.
+// - client: a pointer to the resty.Client struct for making requests to the Notion API.
+// - sessionID: a uuid.NullUUID representing the session ID.
+type (
+	Notion struct {
+		Base
+		param     *NotionParameters
+		client    *resty.Client
+		sessionID uuid.NullUUID
+	}
+	// NotionParameters contains the token of the internal integration. Only pages and databases
+	// shared with the integration are loaded. BaseURL overrides the URL of the Notion API.
+	NotionParameters struct {
+		Token   string `json:"token"`
+		BaseURL string `json:"base_url"`
+	}
+
+	notionParent struct {
+		Type       string `json:"type"`
+		PageID     string `json:"page_id"`
+		DatabaseID string `json:"database_id"`
+		BlockID    string `json:"block_id"`
+	}
+	// notionObject is a page or a database. Properties of pages contain values, properties of databases
+	// contain their schema, so they are decoded only when pages are rendered.
+	notionObject struct {
+		Object         string                     `json:"object"`
+		ID             string                     `json:"id"`
+		URL            string                     `json:"url"`
+		LastEditedTime string                     `json:"last_edited_time"`
+		Archived       bool                       `json:"archived"`
+		InTrash        bool                       `json:"in_trash"`
+		Parent         notionParent               `json:"parent"`
+		Title          []*notionRichText          `json:"title"`
+		Description    []*notionRichText          `json:"description"`
+		Properties     map[string]json.RawMessage `json:"properties"`
+		parentSourceID string
+	}
+	notionListResponse struct {
+		Results    []*notionObject `json:"results"`
+		HasMore    bool            `json:"has_more"`
+		NextCursor string          `json:"next_cursor"`
+	}
+	notionBlockResponse struct {
+		Results    []*notionBlock `json:"results"`
+		HasMore    bool           `json:"has_more"`
+		NextCursor string         `json:"next_cursor"`
+	}
+	notionError struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+)
+
+// Validate checks if the NotionParameters struct is valid.
+func (p NotionParameters) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.Token, validation.Required),
+	)
+}
+
+// Validate checks if the Notion parameter is valid.
+func (c *Notion) Validate() error {
+	if c.param == nil {
+		return fmt.Errorf("notion parameter is required")
+	}
+	return c.param.Validate()
+}
+
+// PrepareTask sends the connector request with the session ID to the connector service.
+func (c *Notion) PrepareTask(ctx context.Context, sessionID uuid.UUID, task Task) error {
+	params := make(map[string]string)
+	params[model.ParamSessionID] = sessionID.String()
+	return task.RunConnector(ctx, &proto.ConnectorRequest{
+		Id:     c.model.ID.IntPart(),
+		Params: params,
+	})
+}
+
+// Execute executes the Notion connector with the given context and parameters. It returns a channel of Response
+// objects. Pages are loaded again only if their last edited time has changed. Documents of pages that are
+// no longer shared with the integration are not marked as existing, so the executor deletes them.
+func (c *Notion) Execute(ctx context.Context, param map[string]string) chan *Response {
+	paramSessionID, _ := param[model.ParamSessionID]
+	if uuidSessionID, err := uuid.Parse(paramSessionID); err != nil {
+		c.sessionID = uuid.NullUUID{uuid.New(), true}
+	} else {
+		c.sessionID = uuid.NullUUID{uuidSessionID, true}
+	}
+	if len(c.model.DocsMap) == 0 {
+		c.model.DocsMap = make(map[string]*model.Document)
+	}
+	go func() {
+		defer close(c.resultCh)
+		if err := c.execute(ctx); err != nil {
+			zap.S().Errorf("execute %s ", err.Error())
+			// the list of pages is incomplete, keep all documents
+			for _, doc := range c.model.DocsMap {
+				doc.IsExists = true
+			}
+		}
+	}()
+	return c.resultCh
+}
+
+// execute enumerates shared pages, databases and rows of databases. Objects are sent after their parents,
+// so the executor stores parent documents first and can link children to them.
+func (c *Notion) execute(ctx context.Context) error {
+	objects, err := c.getObjects(ctx)
+	if err != nil {
+		return err
+	}
+	if err = c.resolveParents(ctx, objects); err != nil {
+		return err
+	}
+	depth := notionDepths(objects)
+	sort.SliceStable(objects, func(i, j int) bool {
+		return depth[objects[i].ID] < depth[objects[j].ID]
+	})
+	for _, object := range objects {
+		if err = c.loadObject(ctx, object); err != nil {
+			zap.S().Errorf("error loading %s %s : %s ", object.Object, object.ID, err.Error())
+			// keep the previous version of the page
+			if doc, ok := c.model.DocsMap[notionSourceID(object.ID)]; ok {
+				doc.IsExists = true
+			}
+		}
+	}
+	return nil
+}
+
+// getObjects returns pages and databases shared with the integration together with rows of databases.
+// Search does not guarantee to return all rows of databases, so databases are queried as well.
+func (c *Notion) getObjects(ctx context.Context) ([]*notionObject, error) {
+	found := make(map[string]bool)
+	var objects []*notionObject
+	add := func(items []*notionObject) {
+		for _, object := range items {
+			if object.Archived || object.InTrash || found[object.ID] {
+				continue
+			}
+			found[object.ID] = true
+			objects = append(objects, object)
+		}
+	}
+	items, err := c.list(ctx, notionSearch)
+	if err != nil {
+		return nil, err
+	}
+	add(items)
+	for i := 0; i < len(objects); i++ {
+		if objects[i].Object != "database" {
+			continue
+		}
+		if items, err = c.list(ctx, fmt.Sprintf(notionDatabaseQuery, objects[i].ID)); err != nil {
+			return nil, err
+		}
+		add(items)
+	}
+	return objects, nil
+}
+
+// list requests all pages of the paginated search or the database query.
+func (c *Notion) list(ctx context.Context, url string) ([]*notionObject, error) {
+	var objects []*notionObject
+	body := map[string]interface{}{"page_size": notionPageLen}
+	for {
+		var response notionListResponse
+		if err := c.request(ctx, http.MethodPost, url, body, &response); err != nil {
+			return nil, err
+		}
+		objects = append(objects, response.Results...)
+		if !response.HasMore || response.NextCursor == "" {
+			break
+		}
+		body["start_cursor"] = response.NextCursor
+	}
+	return objects, nil
+}
+
+// resolveParents sets source ids of parent pages and databases. Pages nested into blocks
+// (columns, toggles) refer to the block, so parents of blocks are requested until the page is found.
+func (c *Notion) resolveParents(ctx context.Context, objects []*notionObject) error {
+	blockParents := make(map[string]notionParent)
+	for _, object := range objects {
+		parent := object.Parent
+		for i := 0; parent.Type == "block_id" && i < notionMaxParentDepth; i++ {
+			next, ok := blockParents[parent.BlockID]
+			if !ok {
+				var block struct {
+					Parent notionParent `json:"parent"`
+				}
+				if err := c.request(ctx, http.MethodGet, fmt.Sprintf(notionBlockURL, parent.BlockID), nil, &block); err != nil {
+					return err
+				}
+				next = block.Parent
+				blockParents[parent.BlockID] = next
+			}
+			parent = next
+		}
+		switch parent.Type {
+		case "page_id":
+			object.parentSourceID = notionSourceID(parent.PageID)
+		case "database_id":
+			object.parentSourceID = notionSourceID(parent.DatabaseID)
+		}
+	}
+	return nil
+}
+
+// loadObject loads blocks of the page if its last edited time has changed and sends it as a markdown document.
+func (c *Notion) loadObject(ctx context.Context, object *notionObject) error {
+	sourceID := notionSourceID(object.ID)
+	doc, ok := c.model.DocsMap[sourceID]
+	if ok {
+		doc.IsExists = true
+		if doc.Signature == object.LastEditedTime {
+			return nil
+		}
+	}
+
+	title, content, err := c.buildMD(ctx, object)
+	if err != nil {
+		return err
+	}
+
+	fileName := ""
+	if !ok {
+		doc = &model.Document{
+			SourceID:     sourceID,
+			ConnectorID:  c.model.ID,
+			CreationDate: time.Now().UTC(),
+		}
+		c.model.DocsMap[sourceID] = doc
+	} else {
+		// rewrite the file of the existing document
+		minioFile := strings.Split(doc.URL, ":")
+		if len(minioFile) == 3 && minioFile[0] == "minio" {
+			fileName = minioFile[2]
+		}
+	}
+	if fileName == "" {
+		fileName = utils.StripFileName(c.model.BuildFileName(fmt.Sprintf("%s-%s.md", uuid.New().String(), title)))
+	}
+	doc.Signature = object.LastEditedTime
+	doc.ChunkingSession = c.sessionID
+	doc.LastUpdate = pg.NullTime{time.Now().UTC()}
+	doc.OriginalURL = object.URL
+	doc.IsExists = true
+
+	c.resultCh <- &Response{
+		URL:            doc.URL,
+		Name:           fileName,
+		SourceID:       sourceID,
+		ParentSourceID: object.parentSourceID,
+		DocumentID:     doc.ID.IntPart(),
+		MimeType:       "text/markdown",
+		FileType:       proto.FileType_MD,
+		Signature:      object.LastEditedTime,
+		Content: &Content{
+			Bucket: model.BucketName(c.model.User.EmbeddingModel.TenantID),
+			Body:   []byte(content),
+		},
+	}
+	return nil
+}
+
+// buildMD renders the page or the database as markdown. Rows of databases start with their properties.
+// Databases are rendered by their title and description, their rows are separate documents.
+func (c *Notion) buildMD(ctx context.Context, object *notionObject) (string, string, error) {
+	if object.Object == "database" {
+		title := notionPlainText(object.Title)
+		if title == "" {
+			title = "Untitled"
+		}
+		return title, joinNotEmpty("# "+title, notionRichTextToMarkdown(object.Description)), nil
+	}
+
+	properties := make(map[string]*notionProperty)
+	title := ""
+	for name, raw := range object.Properties {
+		var property notionProperty
+		if err := json.Unmarshal(raw, &property); err != nil {
+			return "", "", fmt.Errorf("property %s : %w", name, err)
+		}
+		if property.Type == "title" {
+			title = notionPlainText(property.Title)
+		}
+		properties[name] = &property
+	}
+	if title == "" {
+		title = "Untitled"
+	}
+	blocks, err := c.getBlocks(ctx, object.ID)
+	if err != nil {
+		return "", "", err
+	}
+	propertiesMD := ""
+	if object.Parent.Type == "database_id" {
+		propertiesMD = notionPropertiesToMarkdown(properties)
+	}
+	return title, joinNotEmpty("# "+title, propertiesMD, notionBlocksToMarkdown(blocks)), nil
+}
+
+// getBlocks returns blocks of the page or the block with their nested blocks.
+// Child pages and databases are separate documents, their blocks are not loaded.
+func (c *Notion) getBlocks(ctx context.Context, id string) ([]*notionBlock, error) {
+	var blocks []*notionBlock
+	params := map[string]string{"page_size": strconv.Itoa(notionPageLen)}
+	for {
+		var response notionBlockResponse
+		if err := c.request(ctx, http.MethodGet, fmt.Sprintf(notionBlockChildren, id), params, &response); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, response.Results...)
+		if !response.HasMore || response.NextCursor == "" {
+			break
+		}
+		params["start_cursor"] = response.NextCursor
+	}
+	for _, block := range blocks {
+		if !block.HasChildren || block.Type == "child_page" || block.Type == "child_database" {
+			continue
+		}
+		children, err := c.getBlocks(ctx, block.ID)
+		if err != nil {
+			return nil, err
+		}
+		block.Children = children
+	}
+	return blocks, nil
+}
+
+// request sends the request to the Notion API and parses the response into the result.
+// Parameters are sent as the query of GET requests and as the JSON body of POST requests.
+func (c *Notion) request(ctx context.Context, method, url string, params interface{}, result interface{}) error {
+	request := c.client.R().SetContext(ctx)
+	if method == http.MethodGet {
+		if query, ok := params.(map[string]string); ok {
+			request.SetQueryParams(query)
+		}
+	} else {
+		request.SetBody(params)
+	}
+	response, err := request.Execute(method, url)
+	if err != nil {
+		return err
+	}
+	if response.IsError() {
+		var payload notionError
+		if err = json.Unmarshal(response.Body(), &payload); err == nil && payload.Message != "" {
+			return fmt.Errorf("notion %s : %s", payload.Code, payload.Message)
+		}
+		return utils.WrapRestyError(response, nil)
+	}
+	return json.Unmarshal(response.Body(), result)
+}
+
+// notionDepths returns the number of ancestors of objects among loaded objects.
+func notionDepths(objects []*notionObject) map[string]int {
+	parents := make(map[string]string)
+	for _, object := range objects {
+		parents[notionSourceID(object.ID)] = object.parentSourceID
+	}
+	depth := make(map[string]int)
+	for _, object := range objects {
+		sourceID := notionSourceID(object.ID)
+		for i := 0; i < len(objects); i++ {
+			parent, ok := parents[sourceID]
+			if !ok || parent == "" {
+				break
+			}
+			if _, ok = parents[parent]; !ok {
+				break
+			}
+			depth[object.ID]++
+			sourceID = parent
+		}
+	}
+	return depth
+}
+
+// notionSourceID returns the source id of the document of the page or the database.
+func notionSourceID(id string) string {
+	return "notion:" + id
+}
+
+// NewNotion creates new instance of Notion connector
+func NewNotion(connector *model.Connector,
+	connectorRepo repository.ConnectorRepository) (Connector, error) {
+	conn := Notion{
+		Base: Base{
+			connectorRepo: connectorRepo,
+		},
+		param: &NotionParameters{},
+	}
+	conn.Base.Config(connector)
+
+	if err := connector.ConnectorSpecificConfig.ToStruct(conn.param); err != nil {
+		return nil, err
+	}
+	if err := conn.Validate(); err != nil {
+		return nil, err
+	}
+	baseURL := conn.param.BaseURL
+	if baseURL == "" {
+		baseURL = notionAPIURL
+	}
+	conn.client = resty.New().
+		SetTimeout(time.Minute).
+		SetBaseURL(baseURL).
+		SetRetryCount(3).
+		AddRetryCondition(func(response *resty.Response, err error) bool {
+			return response != nil && response.StatusCode() == http.StatusTooManyRequests
+		}).
+		SetRetryAfter(func(client *resty.Client, response *resty.Response) (time.Duration, error) {
+			if seconds, err := strconv.Atoi(response.Header().Get("Retry-After")); err == nil {
+				return time.Duration(seconds) * time.Second, nil
+			}
+			return time.Second, nil
+		}).
+		SetHeader("Notion-Version", notionVersion).
+		SetHeader(utils.AuthorizationHeader, fmt.Sprintf("Bearer %s", conn.param.Token))
+	return &conn, nil
+}
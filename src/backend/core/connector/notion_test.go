@@ -0,0 +1,165 @@
+package connector
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func notionText(text string) []map[string]interface{} {
+	return []map[string]interface{}{{"plain_text": text}}
+}
+
+func notionTitle(title string) map[string]interface{} {
+	return map[string]interface{}{"Name": map[string]interface{}{"type": "title", "title": notionText(title)}}
+}
+
+func TestNotion_Execute(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+		switch r.URL.Path {
+		case "/search":
+			var request map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			if request["start_cursor"] == nil {
+				body = map[string]interface{}{"has_more": true, "next_cursor": "next", "results": []map[string]interface{}{
+					{"object": "page", "id": "child", "last_edited_time": "2024-07-02T00:00:00.000Z",
+						"parent": map[string]string{"type": "block_id", "block_id": "column"}, "properties": notionTitle("Child")},
+					{"object": "page", "id": "home", "last_edited_time": "2024-07-01T00:00:00.000Z", "url": "https://notion.so/home",
+						"parent": map[string]string{"type": "workspace"}, "properties": notionTitle("Home")},
+				}}
+				break
+			}
+			body = map[string]interface{}{"results": []map[string]interface{}{
+				{"object": "database", "id": "tasks", "last_edited_time": "2024-07-01T00:00:00.000Z",
+					"title": notionText("Tasks"), "description": notionText("Team tasks"),
+					"parent":     map[string]string{"type": "page_id", "page_id": "home"},
+					"properties": map[string]interface{}{"Done": map[string]interface{}{"type": "checkbox", "checkbox": map[string]string{}}}},
+				{"object": "page", "id": "archived", "archived": true, "parent": map[string]string{"type": "workspace"}},
+			}}
+		case "/databases/tasks/query":
+			body = map[string]interface{}{"results": []map[string]interface{}{
+				{"object": "page", "id": "task1", "last_edited_time": "2024-07-03T00:00:00.000Z",
+					"parent": map[string]string{"type": "database_id", "database_id": "tasks"},
+					"properties": map[string]interface{}{
+						"Name":     map[string]interface{}{"type": "title", "title": notionText("Write docs")},
+						"Done":     map[string]interface{}{"type": "checkbox", "checkbox": true},
+						"Estimate": map[string]interface{}{"type": "number", "number": 1.5},
+						"Tags":     map[string]interface{}{"type": "multi_select", "multi_select": []map[string]string{{"name": "a"}, {"name": "b"}}},
+						"Due":      map[string]interface{}{"type": "date", "date": map[string]interface{}{"start": "2024-07-10"}},
+					}},
+			}}
+		case "/blocks/column":
+			body = map[string]interface{}{"parent": map[string]string{"type": "block_id", "block_id": "columns"}}
+		case "/blocks/columns":
+			body = map[string]interface{}{"parent": map[string]string{"type": "page_id", "page_id": "home"}}
+		case "/blocks/home/children":
+			body = map[string]interface{}{"results": []map[string]interface{}{
+				{"id": "b1", "type": "heading_1", "heading_1": map[string]interface{}{"rich_text": notionText("Welcome")}},
+				{"id": "b2", "type": "paragraph", "paragraph": map[string]interface{}{"rich_text": []map[string]interface{}{
+					{"plain_text": "Read "}, {"plain_text": "this", "annotations": map[string]bool{"bold": true}, "href": "https://x"},
+				}}},
+				{"id": "b3", "type": "toggle", "has_children": true, "toggle": map[string]interface{}{"rich_text": notionText("More")}},
+				{"id": "b4", "type": "code", "code": map[string]interface{}{"language": "go", "rich_text": notionText("fmt.Println()")}},
+				{"id": "b5", "type": "table", "has_children": true, "table": map[string]interface{}{"has_column_header": true}},
+				{"id": "b6", "type": "child_database", "has_children": true, "child_database": map[string]string{"title": "Tasks"}},
+			}}
+		case "/blocks/b3/children":
+			body = map[string]interface{}{"results": []map[string]interface{}{
+				{"id": "b31", "type": "toggle", "has_children": true, "toggle": map[string]interface{}{"rich_text": notionText("Nested")}},
+			}}
+		case "/blocks/b31/children":
+			body = map[string]interface{}{"results": []map[string]interface{}{
+				{"id": "b311", "type": "paragraph", "paragraph": map[string]interface{}{"rich_text": notionText("Hidden text")}},
+			}}
+		case "/blocks/b5/children":
+			body = map[string]interface{}{"results": []map[string]interface{}{
+				{"id": "r1", "type": "table_row", "table_row": map[string]interface{}{"cells": [][]map[string]interface{}{notionText("Key"), notionText("Value")}}},
+				{"id": "r2", "type": "table_row", "table_row": map[string]interface{}{"cells": [][]map[string]interface{}{notionText("a|b"), notionText("1")}}},
+			}}
+		case "/blocks/task1/children":
+			body = map[string]interface{}{"results": []map[string]interface{}{}}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"object_not_found","message":"not found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	conn, err := NewNotion(&model.Connector{
+		ID:   decimal.NewFromInt(1),
+		Type: model.SourceTypeNotion,
+		ConnectorSpecificConfig: model.JSONMap{
+			"token":    "secret",
+			"base_url": server.URL,
+		},
+		DocsMap: map[string]*model.Document{
+			"notion:child":   {ID: decimal.NewFromInt(1), SourceID: "notion:child", Signature: "2024-07-02T00:00:00.000Z"},
+			"notion:removed": {ID: decimal.NewFromInt(2), SourceID: "notion:removed"},
+		},
+		User: &model.User{EmbeddingModel: &model.EmbeddingModel{TenantID: uuid.New()}},
+	}, nil)
+	assert.NoError(t, err)
+	c := conn.(*Notion)
+
+	var responses []*Response
+	for response := range c.Execute(context.Background(), map[string]string{}) {
+		responses = append(responses, response)
+	}
+
+	if assert.Len(t, responses, 3) {
+		// parents are sent before their children
+		assert.Equal(t, "notion:home", responses[0].SourceID)
+		assert.Equal(t, "", responses[0].ParentSourceID)
+		assert.Equal(t, "# Home\n\n"+
+			"## Welcome\n\n"+
+			"Read [**this**](https://x)\n\n"+
+			"- More\n"+
+			"  - Nested\n"+
+			"    Hidden text\n\n"+
+			"```go\nfmt.Println()\n```\n\n"+
+			"| Key | Value |\n"+
+			"| --- | --- |\n"+
+			"| a\\|b | 1 |", string(responses[0].Content.Body))
+
+		assert.Equal(t, "notion:tasks", responses[1].SourceID)
+		assert.Equal(t, "notion:home", responses[1].ParentSourceID)
+		assert.Equal(t, "# Tasks\n\nTeam tasks", string(responses[1].Content.Body))
+
+		assert.Equal(t, "notion:task1", responses[2].SourceID)
+		assert.Equal(t, "notion:tasks", responses[2].ParentSourceID)
+		assert.Equal(t, "# Write docs\n\n"+
+			"- Done: true\n"+
+			"- Due: 2024-07-10\n"+
+			"- Estimate: 1.5\n"+
+			"- Tags: a, b", string(responses[2].Content.Body))
+		assert.Equal(t, "2024-07-03T00:00:00.000Z", c.model.DocsMap["notion:task1"].Signature)
+	}
+	assert.True(t, c.model.DocsMap["notion:child"].IsExists)
+	assert.False(t, c.model.DocsMap["notion:removed"].IsExists)
+}
+
+func TestNotionBlocksToMarkdown(t *testing.T) {
+	blocks := []*notionBlock{
+		{Type: "numbered_list_item", Content: notionBlockContent{RichText: []*notionRichText{{PlainText: "one"}}}},
+		{Type: "numbered_list_item", Content: notionBlockContent{RichText: []*notionRichText{{PlainText: "two"}}}},
+		{Type: "to_do", Content: notionBlockContent{Checked: true, RichText: []*notionRichText{{PlainText: "done"}}}},
+		{Type: "quote", Content: notionBlockContent{RichText: []*notionRichText{{PlainText: "quoted\ntext"}}}},
+		{Type: "divider"},
+		{Type: "table", Children: []*notionBlock{
+			{Type: "table_row", Content: notionBlockContent{Cells: [][]*notionRichText{{{PlainText: "a"}}, {{PlainText: "b"}}}}},
+		}},
+	}
+	assert.Equal(t, "1. one\n2. two\n- [x] done\n\n"+
+		"> quoted\n> text\n\n"+
+		"---\n\n"+
+		"| | |\n| --- | --- |\n| a | b |", notionBlocksToMarkdown(blocks))
+}
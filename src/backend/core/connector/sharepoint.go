@@ -0,0 +1,477 @@
+package connector
+
+import (
+	microsoft_core "cognix.ch/api/v2/core/connector/microsoft-core"
+	"cognix.ch/api/v2/core/model"
+	"cognix.ch/api/v2/core/proto"
+	"cognix.ch/api/v2/core/repository"
+	"cognix.ch/api/v2/core/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-pg/pg/v10"
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// These constants represent the Microsoft Graph API endpoints used by the SharePoint connector.
+// Paths are relative to graphAPI.
+const (
+	graphAPI                = "https://graph.microsoft.com/v1.0"
+	sharePointSearchSites   = "/sites?search=*"
+	sharePointSiteDrives    = "/sites/%s/drives"
+	sharePointDriveRoot     = "/drives/%s/root/children"
+	sharePointDriveFolder   = "/drives/%s/items/%%s/children"
+	sharePointSitePages     = "/sites/%s/pages/microsoft.graph.sitePage"
+	sharePointSitePage      = "/sites/%s/pages/%s/microsoft.graph.sitePage?$expand=canvasLayout"
+	sharePointDocumentDrive = "documentLibrary"
+	sharePointPageSourceID  = "sharepoint:page:%s"
+	sharePointTextWebPart   = "#microsoft.graph.textWebPart"
+)
+
+// SharePoint is a struct that represents the SharePoint connector.
+//
+// The struct contains the following fields:
+// - Base: a struct that represents the base properties and methods needed for various connectors.
+// - param: a pointer to the SharePointParameters struct that contains the connector parameters.
+// - client: a pointer to the resty.Client struct for making requests to the Microsoft Graph API.
+// - graphURL: the base URL of the Microsoft Graph API.
+// - fileSizeLimit: an integer representing the file size limit.
+// - sessionID: a uuid.NullUUID representing the session ID.
+type (
+	SharePoint struct {
+		Base
+		param         *SharePointParameters
+		client        *resty.Client
+		graphURL      string
+		fileSizeLimit int
+		sessionID     uuid.NullUUID
+	}
+	// SharePointParameters contains sites to analyze as URLs (https://<tenant>.sharepoint.com/sites/<site>),
+	// names of document libraries (all libraries of the site if empty) and the Microsoft OAuth token.
+	// Folder and Recursive select folders of libraries the same way as for OneDrive.
+	// LoadPages enables indexing of site pages.
+	SharePointParameters struct {
+		microsoft_core.MSDriveParam
+		Sites     model.StringSlice `json:"sites"`
+		Libraries model.StringSlice `json:"libraries"`
+		LoadPages bool              `json:"load_pages"`
+		Token     *oauth2.Token     `json:"token"`
+	}
+
+	sharePointSite struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"displayName"`
+		WebURL      string `json:"webUrl"`
+	}
+	sharePointSitesResponse struct {
+		Value    []*sharePointSite `json:"value"`
+		NextLink string            `json:"@odata.nextLink"`
+	}
+	sharePointDrive struct {
+		ID        string `json:"id"`
+		Name      string `json:"name"`
+		DriveType string `json:"driveType"`
+	}
+	sharePointDrivesResponse struct {
+		Value    []*sharePointDrive `json:"value"`
+		NextLink string             `json:"@odata.nextLink"`
+	}
+	sharePointWebPart struct {
+		Type      string `json:"@odata.type"`
+		InnerHTML string `json:"innerHtml"`
+	}
+	sharePointPage struct {
+		ID           string `json:"id"`
+		Name         string `json:"name"`
+		Title        string `json:"title"`
+		Description  string `json:"description"`
+		WebURL       string `json:"webUrl"`
+		ETag         string `json:"eTag"`
+		CanvasLayout *struct {
+			HorizontalSections []struct {
+				Columns []struct {
+					WebParts []*sharePointWebPart `json:"webparts"`
+				} `json:"columns"`
+			} `json:"horizontalSections"`
+			VerticalSection *struct {
+				WebParts []*sharePointWebPart `json:"webparts"`
+			} `json:"verticalSection"`
+		} `json:"canvasLayout"`
+	}
+	sharePointPagesResponse struct {
+		Value    []*sharePointPage `json:"value"`
+		NextLink string            `json:"@odata.nextLink"`
+	}
+)
+
+// Validate checks if the provided SharePointParameters object is valid.
+// It checks if the Token field is present and if its AccessToken, RefreshToken, and TokenType are not empty.
+func (p SharePointParameters) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.Token, validation.By(func(value interface{}) error {
+			if p.Token == nil {
+				return fmt.Errorf("missing token")
+			}
+			if p.Token.AccessToken == "" || p.Token.RefreshToken == "" ||
+				p.Token.TokenType == "" {
+				return fmt.Errorf("wrong token")
+			}
+			return nil
+		})),
+	)
+}
+
+// Validate checks if the SharePoint parameter is valid.
+func (c *SharePoint) Validate() error {
+	if c.param == nil {
+		return fmt.Errorf("sharepoint parameter is required")
+	}
+	return c.param.Validate()
+}
+
+// PrepareTask sends the connector request with the session ID to the connector service.
+func (c *SharePoint) PrepareTask(ctx context.Context, sessionID uuid.UUID, task Task) error {
+	return task.RunConnector(ctx, &proto.ConnectorRequest{
+		Id: c.model.ID.IntPart(),
+		Params: map[string]string{
+			model.ParamSessionID: sessionID.String(),
+		},
+	})
+}
+
+// Execute executes the SharePoint connector with the given context and parameters. It returns a channel of Response
+// objects. Files of document libraries are walked with MSDrive, site pages are sent as markdown documents.
+// Documents that no longer exist are not marked as existing, so the executor deletes them.
+func (c *SharePoint) Execute(ctx context.Context, param map[string]string) chan *Response {
+	var fileSizeLimit int
+	if size, ok := param[model.ParamFileLimit]; ok {
+		fileSizeLimit, _ = strconv.Atoi(size)
+	}
+	if fileSizeLimit == 0 {
+		fileSizeLimit = 1
+	}
+	c.fileSizeLimit = fileSizeLimit * model.GB
+
+	paramSessionID, _ := param[model.ParamSessionID]
+	if uuidSessionID, err := uuid.Parse(paramSessionID); err != nil {
+		c.sessionID = uuid.NullUUID{uuid.New(), true}
+	} else {
+		c.sessionID = uuid.NullUUID{uuidSessionID, true}
+	}
+	if len(c.model.DocsMap) == 0 {
+		c.model.DocsMap = make(map[string]*model.Document)
+	}
+
+	go func() {
+		defer close(c.resultCh)
+		if err := c.execute(ctx); err != nil {
+			zap.S().Errorf("execute %s ", err.Error())
+			// the list of documents is incomplete, keep all documents
+			for _, doc := range c.model.DocsMap {
+				doc.IsExists = true
+			}
+		}
+	}()
+	return c.resultCh
+}
+
+// execute resolves configured sites and loads their document libraries and pages.
+func (c *SharePoint) execute(ctx context.Context) error {
+	sites, err := c.getSites(ctx)
+	if err != nil {
+		return err
+	}
+	for _, site := range sites {
+		drives, err := c.getDrives(ctx, site)
+		if err != nil {
+			return err
+		}
+		for _, drive := range drives {
+			msDrive := microsoft_core.NewMSDrive(
+				&c.param.MSDriveParam,
+				c.model,
+				c.sessionID,
+				c.client,
+				c.graphURL+fmt.Sprintf(sharePointDriveRoot, drive.ID),
+				c.graphURL+fmt.Sprintf(sharePointDriveFolder, drive.ID),
+				c.getFile,
+			)
+			if err = msDrive.Execute(ctx, c.fileSizeLimit); err != nil {
+				return fmt.Errorf("library %s of site %s : %w", drive.Name, site.DisplayName, err)
+			}
+		}
+		if c.param.LoadPages {
+			if err = c.loadPages(ctx, site); err != nil {
+				return fmt.Errorf("pages of site %s : %w", site.DisplayName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// getSites returns configured sites or all sites available to the user if no site is configured.
+func (c *SharePoint) getSites(ctx context.Context) ([]*sharePointSite, error) {
+	var sites []*sharePointSite
+	if len(c.param.Sites) == 0 {
+		for url := c.graphURL + sharePointSearchSites; url != ""; {
+			var response sharePointSitesResponse
+			if err := c.requestAndParse(ctx, url, &response); err != nil {
+				return nil, err
+			}
+			sites = append(sites, response.Value...)
+			url = response.NextLink
+		}
+		return sites, nil
+	}
+	for _, name := range c.param.Sites {
+		var site sharePointSite
+		if err := c.requestAndParse(ctx, c.graphURL+sharePointSitePath(name), &site); err != nil {
+			return nil, fmt.Errorf("site %s : %w", name, err)
+		}
+		sites = append(sites, &site)
+	}
+	return sites, nil
+}
+
+// getDrives returns document libraries of the site filtered by configured library names.
+func (c *SharePoint) getDrives(ctx context.Context, site *sharePointSite) ([]*sharePointDrive, error) {
+	var drives []*sharePointDrive
+	for url := c.graphURL + fmt.Sprintf(sharePointSiteDrives, site.ID); url != ""; {
+		var response sharePointDrivesResponse
+		if err := c.requestAndParse(ctx, url, &response); err != nil {
+			return nil, err
+		}
+		for _, drive := range response.Value {
+			if drive.DriveType != sharePointDocumentDrive || !c.isLibraryAnalysing(drive.Name) {
+				continue
+			}
+			drives = append(drives, drive)
+		}
+		url = response.NextLink
+	}
+	return drives, nil
+}
+
+// isLibraryAnalysing checks if the library is configured for analysis. All libraries are analyzed if none is configured.
+func (c *SharePoint) isLibraryAnalysing(name string) bool {
+	if len(c.param.Libraries) == 0 {
+		return true
+	}
+	for _, library := range c.param.Libraries {
+		if strings.EqualFold(library, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadPages loads modern pages of the site.
+func (c *SharePoint) loadPages(ctx context.Context, site *sharePointSite) error {
+	for url := c.graphURL + fmt.Sprintf(sharePointSitePages, site.ID); url != ""; {
+		var response sharePointPagesResponse
+		if err := c.requestAndParse(ctx, url, &response); err != nil {
+			return err
+		}
+		for _, page := range response.Value {
+			if err := c.loadPage(ctx, site, page); err != nil {
+				zap.S().Errorf("error loading page %s : %s ", page.Name, err.Error())
+				// keep the previous version of the page
+				if doc, ok := c.model.DocsMap[fmt.Sprintf(sharePointPageSourceID, page.ID)]; ok {
+					doc.IsExists = true
+				}
+			}
+		}
+		url = response.NextLink
+	}
+	return nil
+}
+
+// loadPage loads web parts of the page if its eTag has changed and sends text web parts as a markdown document.
+func (c *SharePoint) loadPage(ctx context.Context, site *sharePointSite, page *sharePointPage) error {
+	sourceID := fmt.Sprintf(sharePointPageSourceID, page.ID)
+	doc, ok := c.model.DocsMap[sourceID]
+	if ok {
+		doc.IsExists = true
+		if page.ETag != "" && doc.Signature == page.ETag {
+			return nil
+		}
+	}
+
+	var body sharePointPage
+	if err := c.requestAndParse(ctx, c.graphURL+fmt.Sprintf(sharePointSitePage, site.ID, page.ID), &body); err != nil {
+		return err
+	}
+	content, err := buildSharePointPageMD(page, &body)
+	if err != nil {
+		return err
+	}
+
+	fileName := ""
+	if !ok {
+		doc = &model.Document{
+			SourceID:     sourceID,
+			ConnectorID:  c.model.ID,
+			CreationDate: time.Now().UTC(),
+		}
+		c.model.DocsMap[sourceID] = doc
+	} else {
+		// rewrite the file of the existing document
+		minioFile := strings.Split(doc.URL, ":")
+		if len(minioFile) == 3 && minioFile[0] == "minio" {
+			fileName = minioFile[2]
+		}
+	}
+	if fileName == "" {
+		fileName = utils.StripFileName(c.model.BuildFileName(fmt.Sprintf("%s-%s.md", uuid.New().String(), page.Name)))
+	}
+	doc.Signature = page.ETag
+	doc.ChunkingSession = c.sessionID
+	doc.LastUpdate = pg.NullTime{time.Now().UTC()}
+	doc.OriginalURL = sharePointPageURL(site, page)
+	doc.IsExists = true
+
+	c.resultCh <- &Response{
+		URL:        doc.URL,
+		Name:       fileName,
+		SourceID:   sourceID,
+		DocumentID: doc.ID.IntPart(),
+		MimeType:   "text/markdown",
+		FileType:   proto.FileType_MD,
+		Signature:  page.ETag,
+		Content: &Content{
+			Bucket: model.BucketName(c.model.User.EmbeddingModel.TenantID),
+			Body:   []byte(content),
+		},
+	}
+	return nil
+}
+
+// getFile sends the file found by MSDrive to the result channel.
+func (c *SharePoint) getFile(payload *microsoft_core.Response) {
+	c.resultCh <- &Response{
+		URL:        payload.URL,
+		Name:       payload.Name,
+		SourceID:   payload.SourceID,
+		DocumentID: payload.DocumentID,
+		MimeType:   payload.MimeType,
+		FileType:   payload.FileType,
+		Signature:  payload.Signature,
+		Content: &Content{
+			Bucket: model.BucketName(c.model.User.EmbeddingModel.TenantID),
+			URL:    payload.URL,
+		},
+	}
+}
+
+// requestAndParse sends a GET request to the Microsoft Graph API and parses the response into the result.
+func (c *SharePoint) requestAndParse(ctx context.Context, url string, result interface{}) error {
+	response, err := c.client.R().SetContext(ctx).Get(url)
+	if err = utils.WrapRestyError(response, err); err != nil {
+		return err
+	}
+	return json.Unmarshal(response.Body(), result)
+}
+
+// buildSharePointPageMD renders the title, the description and text web parts of the page as markdown.
+// Other web parts (images, lists, embedded content) have no text to index and are skipped.
+func buildSharePointPageMD(page *sharePointPage, body *sharePointPage) (string, error) {
+	title := page.Title
+	if title == "" {
+		title = strings.TrimSuffix(page.Name, ".aspx")
+	}
+	parts := []string{"# " + title}
+	if page.Description != "" {
+		parts = append(parts, page.Description)
+	}
+	if body.CanvasLayout == nil {
+		return strings.Join(parts, "\n\n"), nil
+	}
+	var webParts []*sharePointWebPart
+	for _, section := range body.CanvasLayout.HorizontalSections {
+		for _, column := range section.Columns {
+			webParts = append(webParts, column.WebParts...)
+		}
+	}
+	if body.CanvasLayout.VerticalSection != nil {
+		webParts = append(webParts, body.CanvasLayout.VerticalSection.WebParts...)
+	}
+	for _, webPart := range webParts {
+		if webPart.Type != sharePointTextWebPart || webPart.InnerHTML == "" {
+			continue
+		}
+		text, err := storageToMarkdown(webPart.InnerHTML)
+		if err != nil {
+			return "", err
+		}
+		if text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// sharePointSitePath returns the Graph API path of the site by its URL (https://<tenant>.sharepoint.com/sites/<site>),
+// host name with the server relative path or the site id.
+func sharePointSitePath(site string) string {
+	site = strings.TrimPrefix(strings.TrimPrefix(site, "https://"), "http://")
+	host, path, _ := strings.Cut(strings.Trim(site, "/"), "/")
+	if path == "" {
+		return "/sites/" + host
+	}
+	return fmt.Sprintf("/sites/%s:/%s", host, path)
+}
+
+// sharePointPageURL returns the link to the page. Graph API returns links of pages relative to the site.
+func sharePointPageURL(site *sharePointSite, page *sharePointPage) string {
+	if strings.HasPrefix(page.WebURL, "http://") || strings.HasPrefix(page.WebURL, "https://") {
+		return page.WebURL
+	}
+	return strings.TrimSuffix(site.WebURL, "/") + "/" + strings.TrimPrefix(page.WebURL, "/")
+}
+
+// NewSharePoint creates a new instance of the SharePoint connector by initializing the connector struct and validating its parameters.
+// The access token is refreshed with the Microsoft OAuth provider if it has expired.
+func NewSharePoint(connector *model.Connector,
+	connectorRepo repository.ConnectorRepository,
+	oauthURL string) (Connector, error) {
+	conn := SharePoint{
+		Base: Base{
+			connectorRepo: connectorRepo,
+			oauthClient: resty.New().
+				SetTimeout(time.Minute).
+				SetBaseURL(oauthURL),
+		},
+		param:    &SharePointParameters{},
+		graphURL: graphAPI,
+	}
+
+	conn.Base.Config(connector)
+	if err := connector.ConnectorSpecificConfig.ToStruct(conn.param); err != nil {
+		return nil, err
+	}
+	if err := conn.Validate(); err != nil {
+		return nil, err
+	}
+
+	newToken, err := conn.refreshToken(conn.param.Token)
+	if err != nil {
+		return nil, err
+	}
+	if newToken != nil {
+		conn.param.Token = newToken
+	}
+
+	conn.client = resty.New().
+		SetTimeout(time.Minute).
+		SetHeader(utils.AuthorizationHeader, fmt.Sprintf("%s %s",
+			conn.param.Token.TokenType,
+			conn.param.Token.AccessToken))
+	return &conn, nil
+}
@@ -0,0 +1,114 @@
+package connector
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSharePoint_Execute(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+		switch r.URL.Path {
+		case "/sites/test.sharepoint.com:/sites/intranet":
+			body = map[string]string{"id": "site1", "displayName": "Intranet", "webUrl": "https://test.sharepoint.com/sites/intranet"}
+		case "/sites/site1/drives":
+			body = map[string]interface{}{"value": []map[string]string{
+				{"id": "drive1", "name": "Documents", "driveType": "documentLibrary"},
+				{"id": "drive2", "name": "Archive", "driveType": "documentLibrary"},
+			}}
+		case "/drives/drive1/root/children":
+			if r.URL.Query().Get("page") == "" {
+				body = map[string]interface{}{
+					"value": []map[string]interface{}{
+						{"id": "file1", "name": "policy.pdf", "size": 10, "webUrl": "https://test/policy.pdf",
+							"@microsoft.graph.downloadUrl": "https://download/file1",
+							"file":                         map[string]interface{}{"hashes": map[string]string{"quickXorHash": "hash1"}}},
+					},
+					"@odata.nextLink": server.URL + "/drives/drive1/root/children?page=2",
+				}
+				break
+			}
+			body = map[string]interface{}{"value": []map[string]interface{}{
+				{"id": "folder1", "name": "HR", "folder": map[string]int{"childCount": 1}},
+			}}
+		case "/drives/drive1/items/folder1/children":
+			body = map[string]interface{}{"value": []map[string]interface{}{
+				{"id": "file2", "name": "handbook.docx", "size": 10, "webUrl": "https://test/handbook.docx",
+					"@microsoft.graph.downloadUrl": "https://download/file2",
+					"file":                         map[string]interface{}{"hashes": map[string]string{"quickXorHash": "hash2"}}},
+			}}
+		case "/sites/site1/pages/microsoft.graph.sitePage":
+			body = map[string]interface{}{"value": []map[string]string{
+				{"id": "page1", "name": "Home.aspx", "title": "Home", "webUrl": "SitePages/Home.aspx", "eTag": "\"1\""},
+				{"id": "page2", "name": "News.aspx", "title": "News", "webUrl": "SitePages/News.aspx", "eTag": "\"5\""},
+			}}
+		case "/sites/site1/pages/page1/microsoft.graph.sitePage":
+			body = map[string]interface{}{"canvasLayout": map[string]interface{}{
+				"horizontalSections": []map[string]interface{}{{"columns": []map[string]interface{}{{"webparts": []map[string]string{
+					{"@odata.type": "#microsoft.graph.textWebPart", "innerHtml": "<h2>Welcome</h2><p>Read the <strong>policy</strong>.</p>"},
+					{"@odata.type": "#microsoft.graph.standardWebPart"},
+				}}}}},
+			}}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	conn, err := NewSharePoint(&model.Connector{
+		ID:   decimal.NewFromInt(1),
+		Type: model.SourceTypeSharepoint,
+		ConnectorSpecificConfig: model.JSONMap{
+			"sites":      []string{"https://test.sharepoint.com/sites/intranet"},
+			"libraries":  []string{"documents"},
+			"recursive":  true,
+			"load_pages": true,
+			"token": map[string]interface{}{
+				"access_token":  "access",
+				"refresh_token": "refresh",
+				"token_type":    "Bearer",
+				"expiry":        "2100-01-01T00:00:00Z",
+			},
+		},
+		DocsMap: map[string]*model.Document{
+			"sharepoint:page:page2": {ID: decimal.NewFromInt(2), SourceID: "sharepoint:page:page2", Signature: "\"5\""},
+			"removed":               {ID: decimal.NewFromInt(3), SourceID: "removed"},
+		},
+		User: &model.User{EmbeddingModel: &model.EmbeddingModel{TenantID: uuid.New()}},
+	}, nil, "")
+	assert.NoError(t, err)
+	c := conn.(*SharePoint)
+	c.graphURL = server.URL
+
+	responses := make(map[string]*Response)
+	for response := range c.Execute(context.Background(), map[string]string{}) {
+		responses[response.SourceID] = response
+	}
+
+	assert.Len(t, responses, 3)
+	assert.Equal(t, "https://download/file1", responses["file1"].Content.URL)
+	assert.Equal(t, "https://download/file2", responses["file2"].Content.URL)
+	if page, ok := responses["sharepoint:page:page1"]; assert.True(t, ok) {
+		assert.Equal(t, "# Home\n\n## Welcome\n\nRead the **policy**.", string(page.Content.Body))
+		assert.Equal(t, "https://test.sharepoint.com/sites/intranet/SitePages/Home.aspx",
+			c.model.DocsMap["sharepoint:page:page1"].OriginalURL)
+	}
+	assert.True(t, c.model.DocsMap["sharepoint:page:page2"].IsExists)
+	assert.False(t, c.model.DocsMap["removed"].IsExists)
+}
+
+func TestSharePointSitePath(t *testing.T) {
+	assert.Equal(t, "/sites/test.sharepoint.com:/sites/intranet", sharePointSitePath("https://test.sharepoint.com/sites/intranet/"))
+	assert.Equal(t, "/sites/test.sharepoint.com", sharePointSitePath("test.sharepoint.com"))
+	assert.Equal(t, "/sites/test.sharepoint.com,1,2", sharePointSitePath("test.sharepoint.com,1,2"))
+}
@@ -0,0 +1,637 @@
+package connector
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"cognix.ch/api/v2/core/proto"
+	"cognix.ch/api/v2/core/repository"
+	"cognix.ch/api/v2/core/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-pg/pg/v10"
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	slackAPIURL = "https://slack.com/api"
+
+	slackAuthTest             = "/auth.test"
+	slackConversationsList    = "/conversations.list"
+	slackConversationsHistory = "/conversations.history"
+	slackConversationsReplies = "/conversations.replies"
+	slackUsersInfo            = "/users.info"
+
+	slackPageLimit = "200"
+	// slackThreadTrackingPeriod is the period after the start of a thread during which new replies are loaded.
+	slackThreadTrackingPeriod = 30 * 24 * time.Hour
+
+	slackChannelTypesPublic  = "public_channel"
+	slackChannelTypesPrivate = "public_channel,private_channel"
+)
+
+// Slack is a struct that represents the Slack connector.
+//
+// The struct contains the following fields:
+// - Base: a struct that represents the base properties and methods needed for various connectors.
+// - param: a pointer to the SlackParameters struct that contains the connector parameters.
+// - state: a pointer to the SlackState struct that stores cursors of channels after each execution.
+// - client: a pointer to the resty.Client struct for making requests to the Slack Web API.
+// - workspaceURL: the URL of the workspace used to build links to messages.
+// - users: the cache of user names by user ids.
+// - fileSizeLimit: an integer representing the maximum file size limit.
+// - sessionID: a uuid.NullUUID representing the session ID.
+type (
+	Slack struct {
+		Base
+		param         *SlackParameters
+		state         *SlackState
+		client        *resty.Client
+		workspaceURL  string
+		users         map[string]string
+		fileSizeLimit int
+		sessionID     uuid.NullUUID
+	}
+	// SlackParameters contains the bot token, names of channels to analyze (all channels the bot
+	// is a member of if empty), and flags for private channels and file attachments.
+	SlackParameters struct {
+		Token          string            `json:"token"`
+		Channels       model.StringSlice `json:"channels"`
+		IncludePrivate bool              `json:"include_private"`
+		LoadFiles      bool              `json:"load_files"`
+		BaseURL        string            `json:"base_url"`
+	}
+	// SlackState stores cursors of channels after each execution.
+	SlackState struct {
+		Channels map[string]*SlackChannelState `json:"channels"`
+	}
+	// SlackChannelState stores the timestamp of the newest loaded message of the channel
+	// and the timestamps of the newest loaded replies of threads.
+	SlackChannelState struct {
+		LatestTS string            `json:"latest_ts"`
+		Threads  map[string]string `json:"threads"`
+	}
+
+	slackResponse struct {
+		Ok               bool   `json:"ok"`
+		Error            string `json:"error"`
+		ResponseMetadata struct {
+			NextCursor string `json:"next_cursor"`
+		} `json:"response_metadata"`
+	}
+	slackAuthResponse struct {
+		slackResponse
+		URL string `json:"url"`
+	}
+	slackChannel struct {
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		IsMember bool   `json:"is_member"`
+	}
+	slackChannelsResponse struct {
+		slackResponse
+		Channels []*slackChannel `json:"channels"`
+	}
+	slackFile struct {
+		ID                 string `json:"id"`
+		Name               string `json:"name"`
+		MimeType           string `json:"mimetype"`
+		Size               int    `json:"size"`
+		URLPrivateDownload string `json:"url_private_download"`
+		Permalink          string `json:"permalink"`
+		Timestamp          int64  `json:"timestamp"`
+	}
+	slackMessage struct {
+		Type       string       `json:"type"`
+		SubType    string       `json:"subtype"`
+		User       string       `json:"user"`
+		Username   string       `json:"username"`
+		Text       string       `json:"text"`
+		TS         string       `json:"ts"`
+		ThreadTS   string       `json:"thread_ts"`
+		ReplyCount int          `json:"reply_count"`
+		Files      []*slackFile `json:"files"`
+	}
+	slackMessagesResponse struct {
+		slackResponse
+		Messages []*slackMessage `json:"messages"`
+		HasMore  bool            `json:"has_more"`
+	}
+	slackUserResponse struct {
+		slackResponse
+		User struct {
+			Name     string `json:"name"`
+			RealName string `json:"real_name"`
+		} `json:"user"`
+	}
+)
+
+// Validate checks if the SlackParameters struct is valid.
+// It returns an error if the token is missing.
+func (p SlackParameters) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.Token, validation.Required),
+	)
+}
+
+// Validate checks if the Slack parameter is valid.
+func (c *Slack) Validate() error {
+	if c.param == nil {
+		return fmt.Errorf("slack parameter is required")
+	}
+	return c.param.Validate()
+}
+
+// PrepareTask sends the connector request with the session ID to the connector service.
+func (c *Slack) PrepareTask(ctx context.Context, sessionID uuid.UUID, task Task) error {
+	params := make(map[string]string)
+	params[model.ParamSessionID] = sessionID.String()
+	return task.RunConnector(ctx, &proto.ConnectorRequest{
+		Id:     c.model.ID.IntPart(),
+		Params: params,
+	})
+}
+
+// Execute executes the Slack connector with the given context and parameters. It returns a channel of Response
+// objects. Messages are not deleted in Slack history, so documents loaded before are kept.
+func (c *Slack) Execute(ctx context.Context, param map[string]string) chan *Response {
+	var fileSizeLimit int
+	if size, ok := param[model.ParamFileLimit]; ok {
+		fileSizeLimit, _ = strconv.Atoi(size)
+	}
+	if fileSizeLimit == 0 {
+		fileSizeLimit = 1
+	}
+	c.fileSizeLimit = fileSizeLimit * model.GB
+	paramSessionID, _ := param[model.ParamSessionID]
+	if uuidSessionID, err := uuid.Parse(paramSessionID); err != nil {
+		c.sessionID = uuid.NullUUID{uuid.New(), true}
+	} else {
+		c.sessionID = uuid.NullUUID{uuidSessionID, true}
+	}
+	for _, doc := range c.model.DocsMap {
+		doc.IsExists = true
+	}
+	go func() {
+		defer close(c.resultCh)
+		if err := c.execute(ctx); err != nil {
+			zap.S().Errorf("execute %s ", err.Error())
+		}
+	}()
+	return c.resultCh
+}
+
+// execute loads messages of the channels and saves the state of the connector.
+func (c *Slack) execute(ctx context.Context) error {
+	var auth slackAuthResponse
+	if err := c.request(ctx, slackAuthTest, nil, &auth); err != nil {
+		return err
+	}
+	c.workspaceURL = strings.TrimSuffix(auth.URL, "/")
+
+	channels, err := c.getChannels(ctx)
+	if err != nil {
+		return err
+	}
+	for _, channel := range channels {
+		if err = c.loadChannel(ctx, channel); err != nil {
+			zap.S().Errorf("error loading channel %s : %s ", channel.Name, err.Error())
+		}
+	}
+	// save current state
+	zap.S().Infof("save connector state.")
+	if err = c.model.State.FromStruct(c.state); err == nil {
+		return c.connectorRepo.Update(ctx, c.model)
+	}
+	return nil
+}
+
+// loadChannel loads messages posted after the stored cursor of the channel.
+// Thread starters are tracked in the state, so replies added to old threads are loaded as well.
+// Every thread is stored as a document, other messages are grouped into a document per day.
+func (c *Slack) loadChannel(ctx context.Context, channel *slackChannel) error {
+	state, ok := c.state.Channels[channel.ID]
+	if !ok {
+		state = &SlackChannelState{
+			Threads: make(map[string]string),
+		}
+		c.state.Channels[channel.ID] = state
+	}
+	if state.Threads == nil {
+		state.Threads = make(map[string]string)
+	}
+	messages, err := c.getHistory(ctx, channel.ID, state.LatestTS, "")
+	if err != nil {
+		return err
+	}
+	days := make(map[string]bool)
+	for _, msg := range messages {
+		if slackTSAfter(msg.TS, state.LatestTS) {
+			state.LatestTS = msg.TS
+		}
+		if msg.ReplyCount > 0 {
+			if _, ok = state.Threads[msg.TS]; !ok {
+				state.Threads[msg.TS] = ""
+			}
+			continue
+		}
+		days[slackTime(msg.TS).Format(time.DateOnly)] = true
+	}
+	for day := range days {
+		if err = c.loadDay(ctx, channel, day); err != nil {
+			zap.S().Errorf("error loading messages of %s : %s ", day, err.Error())
+		}
+	}
+	for threadTS, latestReplyTS := range state.Threads {
+		if time.Since(slackTime(threadTS)) > slackThreadTrackingPeriod && latestReplyTS != "" {
+			// do not load replies of old threads
+			delete(state.Threads, threadTS)
+			continue
+		}
+		if latestReplyTS, err = c.loadThread(ctx, channel, threadTS, latestReplyTS); err != nil {
+			zap.S().Errorf("error loading thread %s : %s ", threadTS, err.Error())
+			continue
+		}
+		state.Threads[threadTS] = latestReplyTS
+	}
+	return nil
+}
+
+// loadThread loads all messages of the thread if it has replies newer than latestReplyTS
+// and sends the thread as a markdown document. It returns the timestamp of the newest reply.
+func (c *Slack) loadThread(ctx context.Context, channel *slackChannel, threadTS, latestReplyTS string) (string, error) {
+	messages, err := c.getMessages(ctx, slackConversationsReplies, map[string]string{
+		"channel": channel.ID,
+		"ts":      threadTS,
+	})
+	if err != nil {
+		return latestReplyTS, err
+	}
+	newest := latestReplyTS
+	for _, msg := range messages {
+		if slackTSAfter(msg.TS, newest) {
+			newest = msg.TS
+		}
+	}
+	if newest == latestReplyTS {
+		return latestReplyTS, nil
+	}
+	sourceID := fmt.Sprintf("slack:%s:%s", channel.ID, threadTS)
+	c.sendMessages(ctx, channel, sourceID, threadTS, newest, messages)
+	return newest, nil
+}
+
+// loadDay loads messages of the channel posted on the day that are not thread starters
+// and sends them as a markdown document.
+func (c *Slack) loadDay(ctx context.Context, channel *slackChannel, day string) error {
+	start, err := time.Parse(time.DateOnly, day)
+	if err != nil {
+		return err
+	}
+	messages, err := c.getHistory(ctx, channel.ID,
+		slackTimestamp(start), slackTimestamp(start.Add(24*time.Hour)))
+	if err != nil {
+		return err
+	}
+	var dayMessages []*slackMessage
+	newest := ""
+	for _, msg := range messages {
+		if msg.ReplyCount > 0 {
+			continue
+		}
+		if slackTSAfter(msg.TS, newest) {
+			newest = msg.TS
+		}
+		dayMessages = append(dayMessages, msg)
+	}
+	if len(dayMessages) == 0 {
+		return nil
+	}
+	sourceID := fmt.Sprintf("slack:%s:%s", channel.ID, day)
+	c.sendMessages(ctx, channel, sourceID, dayMessages[0].TS, newest, dayMessages)
+	return nil
+}
+
+// sendMessages renders messages in chronological order as markdown and sends them to the result channel.
+// The existing document is overwritten. The timestamp of the newest message is used as a signature.
+// Files attached to the messages are sent as separate documents.
+func (c *Slack) sendMessages(ctx context.Context, channel *slackChannel, sourceID, linkTS, signature string, messages []*slackMessage) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		return slackTSAfter(messages[j].TS, messages[i].TS)
+	})
+	var parts []string
+	for _, msg := range messages {
+		if message := c.buildMDMessage(ctx, msg); message != "" {
+			parts = append(parts, message)
+		}
+		if c.param.LoadFiles {
+			for _, file := range msg.Files {
+				if err := c.loadFile(ctx, file); err != nil {
+					zap.S().Errorf("error loading file %s : %s", file.Name, err.Error())
+				}
+			}
+		}
+	}
+	if len(parts) == 0 {
+		return
+	}
+
+	doc, ok := c.model.DocsMap[sourceID]
+	fileName := ""
+	if !ok {
+		doc = &model.Document{
+			SourceID:     sourceID,
+			ConnectorID:  c.model.ID,
+			CreationDate: time.Now().UTC(),
+		}
+		c.model.DocsMap[sourceID] = doc
+	} else {
+		// rewrite the file of the existing document
+		minioFile := strings.Split(doc.URL, ":")
+		if len(minioFile) == 3 && minioFile[0] == "minio" {
+			fileName = minioFile[2]
+		}
+	}
+	if fileName == "" {
+		fileName = utils.StripFileName(c.model.BuildFileName(fmt.Sprintf("%s_%s.md",
+			uuid.New().String(), strings.ReplaceAll(sourceID, ":", "_"))))
+	}
+	doc.Signature = signature
+	doc.ChunkingSession = c.sessionID
+	doc.LastUpdate = pg.NullTime{time.Now().UTC()}
+	doc.OriginalURL = c.messageLink(channel.ID, linkTS)
+	doc.IsExists = true
+
+	c.resultCh <- &Response{
+		URL:        doc.URL,
+		Name:       fileName,
+		SourceID:   sourceID,
+		DocumentID: doc.ID.IntPart(),
+		MimeType:   "text/markdown",
+		FileType:   proto.FileType_MD,
+		Signature:  signature,
+		Content: &Content{
+			Bucket: model.BucketName(c.model.User.EmbeddingModel.TenantID),
+			Body:   []byte(fmt.Sprintf("# #%s\n%s", channel.Name, strings.Join(parts, "\n"))),
+		},
+	}
+}
+
+// loadFile downloads the file attached to the message with the token of the bot
+// and sends it to the result channel. Files with unsupported types and files loaded before are skipped.
+func (c *Slack) loadFile(ctx context.Context, file *slackFile) error {
+	if file.URLPrivateDownload == "" || file.Size > c.fileSizeLimit {
+		return nil
+	}
+	fileType, ok := model.SupportedMimeTypes[file.MimeType]
+	if !ok {
+		return nil
+	}
+	sourceID := fmt.Sprintf("slack:file:%s", file.ID)
+	signature := strconv.FormatInt(file.Timestamp, 10)
+	doc, ok := c.model.DocsMap[sourceID]
+	if ok && doc.Signature == signature {
+		return nil
+	}
+	if !ok {
+		doc = &model.Document{
+			SourceID:     sourceID,
+			ConnectorID:  c.model.ID,
+			CreationDate: time.Now().UTC(),
+		}
+		c.model.DocsMap[sourceID] = doc
+	}
+	response, err := c.client.R().SetContext(ctx).
+		SetDoNotParseResponse(true).
+		Get(file.URLPrivateDownload)
+	if err = utils.WrapRestyError(response, err); err != nil {
+		if response != nil && response.RawBody() != nil {
+			response.RawBody().Close()
+		}
+		return err
+	}
+	doc.Signature = signature
+	doc.ChunkingSession = c.sessionID
+	doc.OriginalURL = file.Permalink
+	doc.IsExists = true
+	c.resultCh <- &Response{
+		URL:        doc.URL,
+		Name:       utils.StripFileName(c.model.BuildFileName(uuid.New().String() + "-" + file.Name)),
+		SourceID:   sourceID,
+		DocumentID: doc.ID.IntPart(),
+		MimeType:   file.MimeType,
+		FileType:   fileType,
+		Signature:  signature,
+		Content: &Content{
+			Bucket: model.BucketName(c.model.User.EmbeddingModel.TenantID),
+			Reader: response.RawBody(),
+		},
+	}
+	return nil
+}
+
+// buildMDMessage constructs a formatted markdown message from a Slack message.
+// System messages like channel joins are skipped.
+func (c *Slack) buildMDMessage(ctx context.Context, msg *slackMessage) string {
+	if msg.SubType != "" && msg.SubType != "bot_message" && msg.SubType != "thread_broadcast" && msg.SubType != "file_share" {
+		return ""
+	}
+	if msg.Text == "" {
+		return ""
+	}
+	userName := msg.Username
+	if msg.User != "" {
+		userName = c.getUserName(ctx, msg.User)
+	}
+	return fmt.Sprintf(messageTemplate, userName, msg.Text)
+}
+
+// getUserName returns the name of the user. Names are cached during the execution.
+func (c *Slack) getUserName(ctx context.Context, userID string) string {
+	if name, ok := c.users[userID]; ok {
+		return name
+	}
+	name := userID
+	var user slackUserResponse
+	if err := c.request(ctx, slackUsersInfo, map[string]string{"user": userID}, &user); err != nil {
+		zap.S().Errorf("error loading user %s : %s", userID, err.Error())
+	} else if user.User.RealName != "" {
+		name = user.User.RealName
+	} else if user.User.Name != "" {
+		name = user.User.Name
+	}
+	c.users[userID] = name
+	return name
+}
+
+// getChannels returns channels the bot is a member of, filtered by names from parameters.
+func (c *Slack) getChannels(ctx context.Context) ([]*slackChannel, error) {
+	types := slackChannelTypesPublic
+	if c.param.IncludePrivate {
+		types = slackChannelTypesPrivate
+	}
+	var channels []*slackChannel
+	cursor := ""
+	for {
+		var response slackChannelsResponse
+		if err := c.request(ctx, slackConversationsList, map[string]string{
+			"types":            types,
+			"exclude_archived": "true",
+			"limit":            slackPageLimit,
+			"cursor":           cursor,
+		}, &response); err != nil {
+			return nil, err
+		}
+		for _, channel := range response.Channels {
+			if !channel.IsMember {
+				continue
+			}
+			if len(c.param.Channels) == 0 || c.param.Channels.InArray(channel.Name) {
+				channels = append(channels, channel)
+			}
+		}
+		cursor = response.ResponseMetadata.NextCursor
+		if cursor == "" {
+			break
+		}
+	}
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("channel not found")
+	}
+	return channels, nil
+}
+
+// getHistory returns messages of the channel posted after oldest and before latest. Empty values are not limited.
+func (c *Slack) getHistory(ctx context.Context, channelID, oldest, latest string) ([]*slackMessage, error) {
+	return c.getMessages(ctx, slackConversationsHistory, map[string]string{
+		"channel": channelID,
+		"oldest":  oldest,
+		"latest":  latest,
+	})
+}
+
+// getMessages requests all pages of messages from the method of the Slack Web API.
+func (c *Slack) getMessages(ctx context.Context, method string, params map[string]string) ([]*slackMessage, error) {
+	var messages []*slackMessage
+	params["limit"] = slackPageLimit
+	for {
+		var response slackMessagesResponse
+		if err := c.request(ctx, method, params, &response); err != nil {
+			return nil, err
+		}
+		messages = append(messages, response.Messages...)
+		params["cursor"] = response.ResponseMetadata.NextCursor
+		if !response.HasMore || params["cursor"] == "" {
+			break
+		}
+	}
+	return messages, nil
+}
+
+// request sends a GET request to the method of the Slack Web API and parses the response into the result.
+// Slack reports errors with the ok field of the response body.
+func (c *Slack) request(ctx context.Context, method string, params map[string]string, result interface{}) error {
+	query := make(map[string]string)
+	for key, value := range params {
+		if value != "" {
+			query[key] = value
+		}
+	}
+	response, err := c.client.R().SetContext(ctx).
+		SetQueryParams(query).
+		Get(method)
+	if err = utils.WrapRestyError(response, err); err != nil {
+		return err
+	}
+	var status slackResponse
+	if err = json.Unmarshal(response.Body(), &status); err != nil {
+		return err
+	}
+	if !status.Ok {
+		return fmt.Errorf("slack %s : %s", method, status.Error)
+	}
+	return json.Unmarshal(response.Body(), result)
+}
+
+// messageLink returns the link to the message in the workspace.
+func (c *Slack) messageLink(channelID, ts string) string {
+	if c.workspaceURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/archives/%s/p%s", c.workspaceURL, channelID, strings.ReplaceAll(ts, ".", ""))
+}
+
+// slackTime converts the timestamp of the message to time.
+func slackTime(ts string) time.Time {
+	seconds, _ := strconv.ParseFloat(ts, 64)
+	return time.Unix(int64(seconds), 0).UTC()
+}
+
+// slackTimestamp converts time to the timestamp format of Slack.
+func slackTimestamp(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10) + ".000000"
+}
+
+// slackTSAfter returns true if the timestamp ts is newer than the timestamp other.
+// An empty timestamp is older than any other one.
+func slackTSAfter(ts, other string) bool {
+	if other == "" {
+		return ts != ""
+	}
+	a, _ := strconv.ParseFloat(ts, 64)
+	b, _ := strconv.ParseFloat(other, 64)
+	return a > b
+}
+
+// NewSlack creates new instance of Slack connector
+func NewSlack(connector *model.Connector,
+	connectorRepo repository.ConnectorRepository) (Connector, error) {
+	conn := Slack{
+		Base: Base{
+			connectorRepo: connectorRepo,
+		},
+		param: &SlackParameters{},
+		state: &SlackState{},
+		users: make(map[string]string),
+	}
+	conn.Base.Config(connector)
+
+	if err := connector.ConnectorSpecificConfig.ToStruct(conn.param); err != nil {
+		return nil, err
+	}
+	if err := conn.Validate(); err != nil {
+		return nil, err
+	}
+	if err := connector.State.ToStruct(conn.state); err != nil {
+		zap.S().Infof("can not parse state %v", err)
+	}
+	if conn.state.Channels == nil {
+		conn.state.Channels = make(map[string]*SlackChannelState)
+	}
+	baseURL := conn.param.BaseURL
+	if baseURL == "" {
+		baseURL = slackAPIURL
+	}
+	conn.client = resty.New().
+		SetTimeout(time.Minute).
+		SetBaseURL(baseURL).
+		SetRetryCount(3).
+		AddRetryCondition(func(response *resty.Response, err error) bool {
+			return response != nil && response.StatusCode() == http.StatusTooManyRequests
+		}).
+		SetRetryAfter(func(client *resty.Client, response *resty.Response) (time.Duration, error) {
+			if seconds, err := strconv.Atoi(response.Header().Get("Retry-After")); err == nil {
+				return time.Duration(seconds) * time.Second, nil
+			}
+			return time.Second, nil
+		}).
+		SetHeader(utils.AuthorizationHeader, fmt.Sprintf("Bearer %s", conn.param.Token))
+	return &conn, nil
+}
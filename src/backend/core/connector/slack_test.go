@@ -0,0 +1,95 @@
+package connector
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSlack_LoadChannel(t *testing.T) {
+	day := time.Now().UTC().Truncate(24 * time.Hour).Add(-12 * time.Hour)
+	threadTS := strconv.FormatInt(day.Unix(), 10) + ".000100"
+	replyTS := strconv.FormatInt(day.Unix()+60, 10) + ".000100"
+	messageTS := strconv.FormatInt(day.Unix()+120, 10) + ".000100"
+
+	history := []map[string]interface{}{
+		{"type": "message", "user": "U1", "text": "thread question", "ts": threadTS, "thread_ts": threadTS, "reply_count": 1},
+		{"type": "message", "user": "U1", "text": "plain message", "ts": messageTS},
+		{"type": "message", "subtype": "channel_join", "user": "U1", "text": "joined", "ts": messageTS},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		switch r.URL.Path {
+		case slackConversationsHistory:
+			body = map[string]interface{}{"ok": true, "messages": history}
+		case slackConversationsReplies:
+			body = map[string]interface{}{"ok": true, "messages": []map[string]interface{}{
+				history[0],
+				{"type": "message", "user": "U2", "text": "thread answer", "ts": replyTS, "thread_ts": threadTS},
+			}}
+		case slackUsersInfo:
+			body = map[string]interface{}{"ok": true, "user": map[string]string{"real_name": "user " + r.URL.Query().Get("user")}}
+		default:
+			body = map[string]interface{}{"ok": false, "error": "unknown_method"}
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	conn, err := NewSlack(&model.Connector{
+		ID:   decimal.NewFromInt(1),
+		Type: model.SourceTypeSlack,
+		ConnectorSpecificConfig: model.JSONMap{
+			"token":    "xoxb-token",
+			"base_url": server.URL,
+		},
+		DocsMap: make(map[string]*model.Document),
+		User:    &model.User{EmbeddingModel: &model.EmbeddingModel{TenantID: uuid.New()}},
+	}, nil)
+	assert.NoError(t, err)
+	c := conn.(*Slack)
+	c.workspaceURL = "https://team.slack.com"
+	channel := &slackChannel{ID: "C1", Name: "general"}
+
+	go func() {
+		defer close(c.resultCh)
+		assert.NoError(t, c.loadChannel(context.Background(), channel))
+	}()
+	responses := make(map[string]*Response)
+	for response := range c.resultCh {
+		responses[response.SourceID] = response
+	}
+
+	thread, ok := responses[fmt.Sprintf("slack:C1:%s", threadTS)]
+	if assert.True(t, ok) {
+		assert.Equal(t, replyTS, thread.Signature)
+		assert.Contains(t, string(thread.Content.Body), "thread question")
+		assert.Contains(t, string(thread.Content.Body), "user U2")
+	}
+	daily, ok := responses[fmt.Sprintf("slack:C1:%s", day.Format(time.DateOnly))]
+	if assert.True(t, ok) {
+		assert.Contains(t, string(daily.Content.Body), "plain message")
+		assert.NotContains(t, string(daily.Content.Body), "joined")
+		assert.NotContains(t, string(daily.Content.Body), "thread question")
+	}
+	assert.Equal(t, messageTS, c.state.Channels["C1"].LatestTS)
+	assert.Equal(t, replyTS, c.state.Channels["C1"].Threads[threadTS])
+	assert.Equal(t, fmt.Sprintf("https://team.slack.com/archives/C1/p%s", threadTS[:10]+"000100"),
+		c.model.DocsMap[thread.SourceID].OriginalURL)
+}
+
+func TestSlack_TSAfter(t *testing.T) {
+	assert.True(t, slackTSAfter("1700000001.000100", "1700000000.000200"))
+	assert.False(t, slackTSAfter("1700000000.000100", "1700000000.000200"))
+	assert.True(t, slackTSAfter("1700000000.000100", ""))
+	assert.False(t, slackTSAfter("", ""))
+}
@@ -0,0 +1,130 @@
+package connector
+
+import (
+	"bufio"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// webRobots contains the rules of robots.txt that apply to the crawler.
+	webRobots struct {
+		rules    []*webRobotsRule
+		delay    time.Duration
+		sitemaps []string
+	}
+
+	// webRobotsRule is an allow or disallow rule of robots.txt.
+	webRobotsRule struct {
+		allow   bool
+		length  int
+		pattern *regexp.Regexp
+	}
+
+	// webRobotsGroup is a group of rules for the listed user agents.
+	webRobotsGroup struct {
+		agents []string
+		rules  []*webRobotsRule
+		delay  time.Duration
+	}
+)
+
+// parseRobots parses robots.txt and selects the rules of the groups for the user agent.
+// If no group names the user agent, the rules of the * groups are used. A group that names
+// the user agent is used even if it has no rules, as RFC 9309 requires.
+func parseRobots(content, userAgent string) *webRobots {
+	robots := &webRobots{}
+	var groups []*webRobotsGroup
+	var group *webRobotsGroup
+	agentLine := false
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch key {
+		case "user-agent":
+			// consecutive user-agent lines share the same group
+			if group == nil || !agentLine {
+				group = &webRobotsGroup{}
+				groups = append(groups, group)
+			}
+			group.agents = append(group.agents, strings.ToLower(value))
+			agentLine = true
+			continue
+		case "allow", "disallow":
+			// an empty disallow allows everything
+			if group != nil && value != "" {
+				group.rules = append(group.rules, &webRobotsRule{
+					allow:   key == "allow",
+					length:  len(value),
+					pattern: robotsPattern(value),
+				})
+			}
+		case "crawl-delay":
+			if seconds, err := strconv.ParseFloat(value, 64); group != nil && err == nil {
+				group.delay = time.Duration(seconds * float64(time.Second))
+			}
+		case "sitemap":
+			robots.sitemaps = append(robots.sitemaps, value)
+		}
+		agentLine = false
+	}
+
+	userAgent = strings.ToLower(userAgent)
+	for _, wildcard := range []bool{false, true} {
+		matched := false
+		for _, g := range groups {
+			for _, agent := range g.agents {
+				if (!wildcard && agent == userAgent) || (wildcard && agent == "*") {
+					robots.rules = append(robots.rules, g.rules...)
+					robots.delay = max(robots.delay, g.delay)
+					matched = true
+					break
+				}
+			}
+		}
+		if matched {
+			break
+		}
+	}
+	return robots
+}
+
+// Allowed reports whether the crawler may fetch the path with the query.
+// The longest matching rule wins, allow wins if rules are equally long.
+func (r *webRobots) Allowed(path string) bool {
+	if path == "/robots.txt" {
+		return true
+	}
+	allowed, length := true, -1
+	for _, rule := range r.rules {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+		if rule.length > length || (rule.length == length && rule.allow) {
+			allowed, length = rule.allow, rule.length
+		}
+	}
+	return allowed
+}
+
+// robotsPattern converts the path pattern of robots.txt with * and $ wildcards to a regular expression.
+func robotsPattern(pattern string) *regexp.Regexp {
+	anchored := strings.HasSuffix(pattern, "$")
+	expr := regexp.QuoteMeta(strings.TrimSuffix(pattern, "$"))
+	expr = "^" + strings.ReplaceAll(expr, `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
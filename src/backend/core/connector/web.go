@@ -1,43 +1,144 @@
 package connector
 
 import (
+	"bytes"
 	"cognix.ch/api/v2/core/model"
 	"cognix.ch/api/v2/core/proto"
+	"cognix.ch/api/v2/core/utils"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
 	"fmt"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/go-pg/pg/v10"
+	"github.com/go-resty/resty/v2"
 	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/net/html"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 )
 
+const (
+	webUserAgent        = "CognixCrawler/1.0"
+	webRobotsAgent      = "CognixCrawler"
+	webDefaultMaxDepth  = 3
+	webDefaultMaxPages  = 1000
+	webDefaultDelay     = time.Second
+	webMaxSitemaps      = 50
+	webSignatureETag    = "etag:"
+	webSignatureLastMod = "last-modified:"
+	webSignatureSHA     = "sha256:"
+)
+
+// webSkippedExtensions are extensions of links to resources that are never indexed.
+var webSkippedExtensions = map[string]bool{
+	".css": true, ".js": true, ".json": true, ".xml": true, ".ico": true,
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".webp": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".eot": true,
+	".zip": true, ".gz": true, ".tar": true, ".rar": true, ".7z": true, ".exe": true, ".dmg": true,
+}
+
+// webNoiseElements are html elements that do not contain the content of the page.
+var webNoiseElements = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "svg": true, "iframe": true,
+	"nav": true, "header": true, "footer": true, "aside": true, "form": true,
+}
+
 type (
-	// Web struct represents a web connector with base properties and WebParameters.
+	// Web struct represents a web connector that crawls the site of the URL
+	// and creates a document for each crawled page.
 	Web struct {
 		Base
-		param *WebParameters
+		param         *WebParameters
+		client        *resty.Client
+		sessionID     uuid.NullUUID
+		fileSizeLimit int
+
+		root        *url.URL
+		host        string
+		robots      *webRobots
+		delay       time.Duration
+		lastRequest time.Time
+		include     []*regexp.Regexp
+		exclude     []*regexp.Regexp
+		visited     map[string]bool
+		queue       []*webPage
 	}
 
 	// WebParameters struct represents the parameters for a Web connector.
 	//
 	// The struct contains the following fields:
-	// - URL: a string representing the URL. (url:"url")
-	// - SiteMap: a string representing the site map. (json:"site_map")
-	// - SearchForSitemap: a boolean indicating whether to search for a sitemap. (json:"search_for_sitemap")
-	// - URLRecursive: a boolean indicating whether the URL should be fetched recursively. (json:"url_recursive")
+	// - URL: a string representing the URL of the start page. (json:"url")
+	// - SiteMap: a string representing the URL of the site map. (json:"site_map")
+	// - SearchForSitemap: a boolean indicating whether to load site maps listed in robots.txt or /sitemap.xml. (json:"search_for_sitemap")
+	// - URLRecursive: a boolean indicating whether links of the pages should be followed. (json:"url_recursive")
+	// - MaxDepth: the number of links followed from the start page, 3 if not set. (json:"max_depth")
+	// - MaxPages: the maximum number of crawled pages, 1000 if not set. (json:"max_pages")
+	// - IncludePatterns: regular expressions, if set only matching URLs are crawled. (json:"include_patterns")
+	// - ExcludePatterns: regular expressions of URLs that are not crawled. (json:"exclude_patterns")
+	// - Delay: milliseconds between requests, 1 second if not set. A longer crawl-delay of robots.txt wins. (json:"delay")
 	WebParameters struct {
-		URL              string `url:"url"`
-		SiteMap          string `json:"site_map"`
-		SearchForSitemap bool   `json:"search_for_sitemap"`
-		URLRecursive     bool   `json:"url_recursive"`
+		URL              string            `json:"url"`
+		SiteMap          string            `json:"site_map"`
+		SearchForSitemap bool              `json:"search_for_sitemap"`
+		URLRecursive     bool              `json:"url_recursive"`
+		MaxDepth         int               `json:"max_depth"`
+		MaxPages         int               `json:"max_pages"`
+		IncludePatterns  model.StringSlice `json:"include_patterns"`
+		ExcludePatterns  model.StringSlice `json:"exclude_patterns"`
+		Delay            int               `json:"delay"`
+	}
+
+	// webPage is a page in the crawl queue with the number of links followed from the start page.
+	webPage struct {
+		url   *url.URL
+		depth int
+	}
+
+	// webPageContent is the parsed html page.
+	webPageContent struct {
+		title    string
+		markdown string
+		links    []*url.URL
+		noIndex  bool
+		noFollow bool
+	}
+
+	// webSitemap is a sitemap or a sitemap index.
+	webSitemap struct {
+		URLs []struct {
+			Loc string `xml:"loc"`
+		} `xml:"url"`
+		Sitemaps []struct {
+			Loc string `xml:"loc"`
+		} `xml:"sitemap"`
 	}
 )
 
 // Validate method validates the WebParameters struct by checking if the URL field is required and a valid URL using the is.URL validator.
+// Include and exclude patterns must be valid regular expressions.
 // Returns an error if validation fails.
 func (p WebParameters) Validate() error {
 	return validation.ValidateStruct(&p,
 		validation.Field(&p.URL, validation.Required,
 			is.URL),
+		validation.Field(&p.SiteMap, is.URL),
+		validation.Field(&p.MaxDepth, validation.Min(0)),
+		validation.Field(&p.MaxPages, validation.Min(0)),
+		validation.Field(&p.Delay, validation.Min(0)),
+		validation.Field(&p.IncludePatterns, validation.Each(validation.By(validateRegexp))),
+		validation.Field(&p.ExcludePatterns, validation.Each(validation.By(validateRegexp))),
 	)
 }
 
@@ -52,104 +153,526 @@ func (c *Web) Validate() error {
 	return c.param.Validate()
 }
 
-// PrepareTask prepares a task for execution by performing necessary setup and validation.
-// If the Web connector is new, a connectorTask for preparing the document table is run.
-// The task is then executed by calling the RunSemantic method of the task object, passing the necessary data.
-//
-// Parameters:
-//   - ctx: the context.Context object for managing the task execution
-//   - sessionID: the UUID representing the session ID
-//   - task: the Task object that represents the task to be executed
-//
-// Returns:
-//   - error: an error object if any error occurs during the preparation or execution of the task
-//     or nil if the preparation and execution are successful.
+// PrepareTask sends the connector to the connector service which crawls the site.
 func (c *Web) PrepareTask(ctx context.Context, sessionID uuid.UUID, task Task) error {
+	params := make(map[string]string)
+	params[model.ParamSessionID] = sessionID.String()
+	return task.RunConnector(ctx, &proto.ConnectorRequest{
+		Id:     c.model.ID.IntPart(),
+		Params: params,
+	})
+}
 
-	// if this connector new we need to run connectorTask for prepare document table
-	if len(c.model.Docs) == 0 {
-		doc, ok := c.model.DocsMap[c.param.URL]
-		if !ok {
-			doc = &model.Document{
-				SourceID:        c.param.URL,
-				ConnectorID:     c.Base.model.ID,
-				URL:             c.param.URL,
-				Signature:       "",
-				ChunkingSession: uuid.NullUUID{sessionID, true},
-				OriginalURL:     c.param.URL,
+// Execute crawls the site and sends a document for each new or changed page.
+// The start page is the root document, all other pages refer to it as their parent.
+// Pages that are not found anymore are removed by the executor. If the crawl fails
+// before it is completed, all documents are kept.
+func (c *Web) Execute(ctx context.Context, param map[string]string) chan *Response {
+	var fileSizeLimit int
+	if size, ok := param[model.ParamFileLimit]; ok {
+		fileSizeLimit, _ = strconv.Atoi(size)
+	}
+	if fileSizeLimit == 0 {
+		fileSizeLimit = 1
+	}
+	c.fileSizeLimit = fileSizeLimit * model.GB
+	paramSessionID, _ := param[model.ParamSessionID]
+	if uuidSessionID, err := uuid.Parse(paramSessionID); err != nil {
+		c.sessionID = uuid.NullUUID{uuid.New(), true}
+	} else {
+		c.sessionID = uuid.NullUUID{uuidSessionID, true}
+	}
+	if len(c.model.DocsMap) == 0 {
+		c.model.DocsMap = make(map[string]*model.Document)
+	}
+	go func() {
+		defer close(c.resultCh)
+		if err := c.crawl(ctx); err != nil {
+			zap.S().Errorf("crawl %s : %s", c.param.URL, err.Error())
+			for _, doc := range c.model.DocsMap {
+				doc.IsExists = true
 			}
-			c.model.Docs = append(c.model.Docs, doc)
 		}
+	}()
+	return c.resultCh
+}
+
+// crawl loads robots.txt and the site maps and crawls the pages breadth first
+// until the queue is empty or the maximum number of pages is reached.
+func (c *Web) crawl(ctx context.Context) error {
+	root, err := url.Parse(c.param.URL)
+	if err != nil {
+		return err
+	}
+	c.root = normalizeWebURL(root)
+	c.host = c.root.Host
+	c.visited = make(map[string]bool)
+	if err = c.loadRobots(ctx); err != nil {
+		return err
 	}
-	var rootDoc *model.Document
-	for _, doc := range c.model.Docs {
-		if !doc.ParentID.Valid {
-			rootDoc = doc
-			rootDoc.ChunkingSession = uuid.NullUUID{sessionID, true}
-			break
+	if !c.robots.Allowed(c.root.RequestURI()) {
+		return fmt.Errorf("%s is disallowed by robots.txt", c.param.URL)
+	}
+	maxDepth, maxPages := 0, c.param.MaxPages
+	if c.param.URLRecursive {
+		maxDepth = c.param.MaxDepth
+		if maxDepth == 0 {
+			maxDepth = webDefaultMaxDepth
 		}
 	}
-	c.model.Docs = append([]*model.Document{}, rootDoc)
+	if maxPages == 0 {
+		maxPages = webDefaultMaxPages
+	}
 
-	if rootDoc == nil {
-		return fmt.Errorf("root document not found")
+	c.visited[c.root.String()] = true
+	c.queue = []*webPage{{url: c.root}}
+	// the start page is loaded first, pages of site maps are its children
+	if err = c.loadPage(ctx, c.queue[0], maxDepth); err != nil {
+		return err
+	}
+	c.queue = c.queue[1:]
+	for _, page := range c.loadSitemaps(ctx) {
+		c.enqueue(page, min(1, maxDepth))
 	}
 
-	return task.RunSemantic(ctx, &proto.SemanticData{
-		Url:              c.param.URL,
-		SiteMap:          c.param.SiteMap,
-		SearchForSitemap: c.param.SearchForSitemap,
-		UrlRecursive:     c.param.URLRecursive,
-		DocumentId:       rootDoc.ID.IntPart(),
-		ConnectorId:      c.model.ID.IntPart(),
-		FileType:         proto.FileType_URL,
-		CollectionName:   c.model.CollectionName(),
-		ModelName:        c.model.User.EmbeddingModel.ModelID,
-		ModelDimension:   int32(c.model.User.EmbeddingModel.ModelDim),
-	})
+	for pages := 1; len(c.queue) > 0 && pages < maxPages; pages++ {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		page := c.queue[0]
+		c.queue = c.queue[1:]
+		if err = c.loadPage(ctx, page, maxDepth); err != nil {
+			zap.S().Errorf("load page %s : %s", page.url.String(), err.Error())
+			// keep the document of a page that can not be loaded now
+			if doc, ok := c.model.DocsMap[page.url.String()]; ok {
+				doc.IsExists = true
+			}
+		}
+	}
+	if len(c.queue) > 0 {
+		zap.S().Infof("crawl %s stopped after %d pages", c.param.URL, maxPages)
+	}
+	return nil
 }
 
-// Execute runs the web connector to perform a web scraping operation.
-//
-// The function takes in a context and a param map and returns a channel of Response objects.
-// The function launches a goroutine to execute the operation asynchronously.
-// The function fetches the document from the model's DocsMap using the URL from the param map.
-// If the document is not found, a new Document object is created and added to the DocsMap.
-// The function then sends a Response object to the resultCh channel with the relevant information.
-// Finally, the function closes the resultCh channel and returns it.
-// The Response object contains the following fields:
-// - URL: a string representing the URL.
-// - SourceID: a string representing the source ID.
-// - SiteMap: a string representing the site map.
-// - SearchForSitemap: a boolean indicating whether to search for a sitemap.
-// - DocumentID: an int64 representing the document ID.
-// - MimeType: a string representing the mime type.
-// - FileType: a proto.FileType representing the file type (16 for URL).
-// Note: The function does not provide error handling and does not validate the param map.
-func (c *Web) Execute(ctx context.Context, param map[string]string) chan *Response {
-	go func() {
-		doc, ok := c.model.DocsMap[c.param.URL]
-		if !ok {
-			doc = &model.Document{
-				SourceID:    c.param.URL,
-				ConnectorID: c.Base.model.ID,
-				URL:         c.param.URL,
-				Signature:   "",
+// loadPage loads the page, sends it if it is new or changed and queues the links of the page.
+// Only pages on the last level are requested conditionally, the links of other pages are always needed.
+func (c *Web) loadPage(ctx context.Context, page *webPage, maxDepth int) error {
+	sourceID := c.sourceID(page.url)
+	doc, exists := c.model.DocsMap[sourceID]
+	request := c.client.R().SetContext(ctx)
+	if exists && page.depth >= maxDepth {
+		switch {
+		case strings.HasPrefix(doc.Signature, webSignatureETag):
+			request.SetHeader("If-None-Match", strings.TrimPrefix(doc.Signature, webSignatureETag))
+		case strings.HasPrefix(doc.Signature, webSignatureLastMod):
+			request.SetHeader("If-Modified-Since", strings.TrimPrefix(doc.Signature, webSignatureLastMod))
+		}
+	}
+	response, err := c.get(ctx, request, page.url.String())
+	if err != nil {
+		return err
+	}
+	if response.StatusCode() == http.StatusNotModified && exists {
+		doc.IsExists = true
+		return nil
+	}
+	if response.StatusCode() == http.StatusNotFound || response.StatusCode() == http.StatusGone {
+		// the page was removed
+		if page.url == c.root {
+			return webResponseError(response, nil)
+		}
+		return nil
+	}
+	if err = webResponseError(response, nil); err != nil {
+		return err
+	}
+	// redirects to other sites are not followed, except of the redirect of the start page
+	final := page.url
+	if response.RawResponse != nil && response.RawResponse.Request != nil {
+		final = normalizeWebURL(response.RawResponse.Request.URL)
+		if page.url == c.root {
+			c.host = final.Host
+		}
+		if final.Host != c.host {
+			return nil
+		}
+		if final.String() != page.url.String() {
+			if c.visited[final.String()] && page.url != c.root {
+				return nil
 			}
-			c.Base.model.DocsMap[c.param.URL] = doc
-		}
-		c.resultCh <- &Response{
-			URL:              c.param.URL,
-			SourceID:         c.param.URL,
-			SiteMap:          c.param.SiteMap,
-			SearchForSitemap: c.param.SearchForSitemap,
-			DocumentID:       doc.ID.IntPart(),
-			MimeType:         model.MIMEURL,
-			FileType:         proto.FileType_URL,
-		}
-		close(c.resultCh)
+			c.visited[final.String()] = true
+		}
+	}
+
+	mimeType, _, _ := mime.ParseMediaType(response.Header().Get("Content-Type"))
+	signature := webSignature(response.Header(), response.Body())
+	if mimeType != "text/html" && mimeType != "application/xhtml+xml" {
+		fileType, ok := model.SupportedMimeTypes[mimeType]
+		if !ok || fileType == proto.FileType_URL {
+			return nil
+		}
+		c.send(sourceID, page.url, final, signature, mimeType, fileType, response.Body())
+		return nil
+	}
+
+	content, err := parseWebPage(response.Body(), final)
+	if err != nil {
+		return err
+	}
+	if !content.noFollow && page.depth < maxDepth {
+		for _, link := range content.links {
+			c.enqueue(link, page.depth+1)
+		}
+	}
+	if content.noIndex {
+		return nil
+	}
+	body := content.markdown
+	if content.title != "" && !strings.HasPrefix(body, "# "+content.title) {
+		body = fmt.Sprintf("# %s\n\n%s", content.title, body)
+	}
+	c.send(sourceID, page.url, final, signature, "text/markdown", proto.FileType_MD, []byte(body))
+	return nil
+}
+
+// enqueue adds the page to the crawl queue if it is on the same site, was not queued yet,
+// matches include and exclude patterns and is allowed by robots.txt.
+func (c *Web) enqueue(link *url.URL, depth int) {
+	link = normalizeWebURL(link)
+	key := link.String()
+	if c.visited[key] || link.Host != c.host || (link.Scheme != "http" && link.Scheme != "https") {
+		return
+	}
+	c.visited[key] = true
+	if webSkippedExtensions[strings.ToLower(path.Ext(link.Path))] {
+		return
+	}
+	if len(c.include) > 0 && !matchAny(c.include, key) {
+		return
+	}
+	if matchAny(c.exclude, key) || !c.robots.Allowed(link.RequestURI()) {
+		return
+	}
+	c.queue = append(c.queue, &webPage{url: link, depth: depth})
+}
+
+// loadRobots loads robots.txt of the site. A missing robots.txt allows everything,
+// an unavailable robots.txt stops the crawl.
+func (c *Web) loadRobots(ctx context.Context) error {
+	robotsURL := &url.URL{Scheme: c.root.Scheme, Host: c.root.Host, Path: "/robots.txt"}
+	response, err := c.get(ctx, c.client.R().SetContext(ctx), robotsURL.String())
+	if err != nil {
+		return err
+	}
+	switch {
+	case response.StatusCode() >= http.StatusInternalServerError:
+		return webResponseError(response, nil)
+	case response.StatusCode() >= http.StatusBadRequest:
+		c.robots = &webRobots{}
+	default:
+		c.robots = parseRobots(string(response.Body()), webRobotsAgent)
+	}
+	c.delay = webDefaultDelay
+	if c.param.Delay > 0 {
+		c.delay = time.Duration(c.param.Delay) * time.Millisecond
+	}
+	c.delay = max(c.delay, c.robots.delay)
+	return nil
+}
+
+// loadSitemaps returns urls of the configured site map, of the site maps listed in robots.txt
+// and of /sitemap.xml if searching for site maps is enabled. Site map indexes are followed.
+func (c *Web) loadSitemaps(ctx context.Context) []*url.URL {
+	var queue []string
+	if c.param.SiteMap != "" {
+		queue = append(queue, c.param.SiteMap)
+	}
+	if c.param.SearchForSitemap {
+		queue = append(queue, c.robots.sitemaps...)
+		if len(c.robots.sitemaps) == 0 {
+			queue = append(queue, (&url.URL{Scheme: c.root.Scheme, Host: c.root.Host, Path: "/sitemap.xml"}).String())
+		}
+	}
+	var result []*url.URL
+	loaded := make(map[string]bool)
+	for len(queue) > 0 && len(loaded) < webMaxSitemaps {
+		sitemapURL := queue[0]
+		queue = queue[1:]
+		if loaded[sitemapURL] {
+			continue
+		}
+		loaded[sitemapURL] = true
+		sitemap, err := c.loadSitemap(ctx, sitemapURL)
+		if err != nil {
+			zap.S().Errorf("load sitemap %s : %s", sitemapURL, err.Error())
+			continue
+		}
+		for _, item := range sitemap.Sitemaps {
+			queue = append(queue, strings.TrimSpace(item.Loc))
+		}
+		for _, item := range sitemap.URLs {
+			if link, err := url.Parse(strings.TrimSpace(item.Loc)); err == nil {
+				result = append(result, link)
+			}
+		}
+	}
+	return result
+}
+
+// loadSitemap loads and parses a site map, gzip compressed site maps are decompressed.
+func (c *Web) loadSitemap(ctx context.Context, sitemapURL string) (*webSitemap, error) {
+	response, err := c.get(ctx, c.client.R().SetContext(ctx), sitemapURL)
+	if err = webResponseError(response, err); err != nil {
+		return nil, err
+	}
+	var reader io.Reader = bytes.NewReader(response.Body())
+	if body := response.Body(); len(body) > 2 && body[0] == 0x1f && body[1] == 0x8b {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return nil, err
+		}
+	}
+	var sitemap webSitemap
+	if err = xml.NewDecoder(reader).Decode(&sitemap); err != nil {
+		return nil, err
+	}
+	return &sitemap, nil
+}
+
+// get sends the request after the delay between requests has passed.
+// The body is read up to the file size limit, larger responses fail. Bodies of error responses are not read.
+func (c *Web) get(ctx context.Context, request *resty.Request, url string) (*resty.Response, error) {
+	if !c.lastRequest.IsZero() {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Until(c.lastRequest.Add(c.delay))):
+		}
+	}
+	defer func() {
+		c.lastRequest = time.Now()
 	}()
-	return c.resultCh
+	response, err := request.SetDoNotParseResponse(true).Get(url)
+	if err != nil || response.RawResponse == nil {
+		return response, err
+	}
+	body := response.RawBody()
+	defer body.Close()
+	if response.IsError() || response.StatusCode() == http.StatusNotModified {
+		return response, nil
+	}
+	content, err := io.ReadAll(io.LimitReader(body, int64(c.fileSizeLimit)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > c.fileSizeLimit {
+		return nil, fmt.Errorf("%s is larger than %d bytes", url, c.fileSizeLimit)
+	}
+	response.SetBody(content)
+	return response, nil
+}
+
+// send sends the content of the page to the executor. The start page is the parent of all other pages.
+func (c *Web) send(sourceID string, pageURL, finalURL *url.URL, signature, mimeType string, fileType proto.FileType, content []byte) {
+	doc, ok := c.model.DocsMap[sourceID]
+	if ok && doc.Signature == signature {
+		doc.IsExists = true
+		return
+	}
+	fileName := ""
+	if !ok {
+		doc = &model.Document{
+			SourceID:     sourceID,
+			ConnectorID:  c.model.ID,
+			CreationDate: time.Now().UTC(),
+		}
+		c.model.DocsMap[sourceID] = doc
+	} else {
+		// rewrite the file of the existing document
+		minioFile := strings.Split(doc.URL, ":")
+		if len(minioFile) == 3 && minioFile[0] == "minio" {
+			fileName = minioFile[2]
+		}
+	}
+	if fileName == "" {
+		name := path.Base(finalURL.Path)
+		if name == "/" || name == "." {
+			name = "index"
+		}
+		if fileType == proto.FileType_MD {
+			name = strings.TrimSuffix(name, path.Ext(name)) + ".md"
+		}
+		fileName = utils.StripFileName(c.model.BuildFileName(fmt.Sprintf("%s-%s", uuid.New().String(), name)))
+	}
+	doc.Signature = signature
+	doc.ChunkingSession = c.sessionID
+	doc.LastUpdate = pg.NullTime{time.Now().UTC()}
+	doc.OriginalURL = finalURL.String()
+	doc.IsExists = true
+
+	parentSourceID := ""
+	if pageURL != c.root {
+		parentSourceID = c.param.URL
+	}
+	c.resultCh <- &Response{
+		URL:            doc.URL,
+		Name:           fileName,
+		SourceID:       sourceID,
+		ParentSourceID: parentSourceID,
+		DocumentID:     doc.ID.IntPart(),
+		MimeType:       mimeType,
+		FileType:       fileType,
+		Signature:      signature,
+		Content: &Content{
+			Bucket: model.BucketName(c.model.User.EmbeddingModel.TenantID),
+			Body:   content,
+		},
+	}
+}
+
+// sourceID returns the source id of the page. The start page keeps the configured URL
+// so that its document stays the root document when the URL is written differently.
+func (c *Web) sourceID(pageURL *url.URL) string {
+	if pageURL == c.root {
+		return c.param.URL
+	}
+	return pageURL.String()
+}
+
+// parseWebPage extracts the title, the links and the robots directives of the html page
+// and renders the main content of the page as markdown.
+func parseWebPage(body []byte, pageURL *url.URL) (*webPageContent, error) {
+	document, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	content := &webPageContent{}
+	base := pageURL
+	var noise []*html.Node
+	var main, article, bodyNode *html.Node
+	var walk func(n *html.Node, inContent bool)
+	walk = func(n *html.Node, inContent bool) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "title":
+				if content.title == "" && n.FirstChild != nil {
+					content.title = strings.TrimSpace(markdownSpaces.ReplaceAllString(n.FirstChild.Data, " "))
+				}
+			case "base":
+				if href, err := pageURL.Parse(attribute(n, "href")); err == nil && attribute(n, "href") != "" {
+					base = href
+				}
+			case "meta":
+				if strings.EqualFold(attribute(n, "name"), "robots") {
+					directives := strings.ToLower(attribute(n, "content"))
+					content.noIndex = content.noIndex || strings.Contains(directives, "noindex") || strings.Contains(directives, "none")
+					content.noFollow = content.noFollow || strings.Contains(directives, "nofollow") || strings.Contains(directives, "none")
+				}
+			case "a":
+				href := attribute(n, "href")
+				if href != "" && !strings.Contains(strings.ToLower(attribute(n, "rel")), "nofollow") {
+					if link, err := base.Parse(href); err == nil {
+						content.links = append(content.links, link)
+					}
+				}
+			case "main":
+				if main == nil {
+					main = n
+				}
+			case "article":
+				if article == nil {
+					article = n
+				}
+			case "body":
+				bodyNode = n
+			}
+			// headers of articles are part of the content
+			if webNoiseElements[n.Data] && !(inContent && (n.Data == "header" || n.Data == "footer")) {
+				noise = append(noise, n)
+			}
+			inContent = inContent || n.Data == "main" || n.Data == "article"
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child, inContent)
+		}
+	}
+	walk(document, false)
+
+	for _, n := range noise {
+		if n.Parent != nil {
+			n.Parent.RemoveChild(n)
+		}
+	}
+	root := bodyNode
+	if main != nil {
+		root = main
+	} else if article != nil {
+		root = article
+	}
+	if root != nil {
+		w := &markdownWriter{}
+		w.renderChildren(root)
+		content.markdown = w.String()
+	}
+	return content, nil
+}
+
+// normalizeWebURL removes the fragment and the default port and lower cases the scheme and the host.
+func normalizeWebURL(link *url.URL) *url.URL {
+	normalized := *link
+	normalized.Fragment = ""
+	normalized.RawFragment = ""
+	normalized.Scheme = strings.ToLower(normalized.Scheme)
+	normalized.Host = strings.ToLower(normalized.Host)
+	if port := normalized.Port(); (port == "80" && normalized.Scheme == "http") || (port == "443" && normalized.Scheme == "https") {
+		normalized.Host = normalized.Hostname()
+	}
+	if normalized.Path == "" {
+		normalized.Path = "/"
+	}
+	return &normalized
+}
+
+// webSignature returns the ETag or the Last-Modified header of the response, or the hash of the content
+// if the server sends neither.
+func webSignature(header http.Header, body []byte) string {
+	if etag := header.Get("ETag"); etag != "" {
+		return webSignatureETag + etag
+	}
+	if lastModified := header.Get("Last-Modified"); lastModified != "" {
+		return webSignatureLastMod + lastModified
+	}
+	checksum := sha256.Sum256(body)
+	return webSignatureSHA + hex.EncodeToString(checksum[:])
+}
+
+// webResponseError returns an error if the request failed or the server responded with an error status.
+// Unlike utils.WrapRestyError it also fails for error responses without a body.
+func webResponseError(response *resty.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	if response.IsError() {
+		return fmt.Errorf("%s responded with %s", response.Request.URL, response.Status())
+	}
+	return nil
+}
+
+// validateRegexp verifies that the value is a valid regular expression.
+func validateRegexp(value interface{}) error {
+	pattern, _ := value.(string)
+	_, err := regexp.Compile(pattern)
+	return err
+}
+
+// matchAny reports whether any of the expressions matches the value.
+func matchAny(expressions []*regexp.Regexp, value string) bool {
+	for _, expression := range expressions {
+		if expression.MatchString(value) {
+			return true
+		}
+	}
+	return false
 }
 
 // NewWeb is a function that initializes and configures a new instance of Web connector.
@@ -163,29 +686,10 @@ func (c *Web) Execute(ctx context.Context, param map[string]string) chan *Respon
 // 5. If there is an error during the conversion, return nil and the error.
 // 6. Call the Validate function of web to validate the parameters.
 // 7. If there is an error during the validation, return nil and the error.
-// 8. Return a pointer to web as a Connector interface and nil as the error.
+// 8. Compile include and exclude patterns and create the http client of the crawler.
+// 9. Return a pointer to web as a Connector interface and nil as the error.
 //
 // The returned Connector interface implements the Execute, PrepareTask, and Validate methods.
-//
-// The function uses the following types:
-// - model.Connector: a struct that represents a table connector.
-// - Connector: an interface that represents a connector.
-// - Web: a struct that represents a Web connector and contains the base properties and methods needed.
-// - Base: a struct that represents the base properties and methods needed for various connectors.
-// - WebParameters: a struct that represents the parameters for a Web connector.
-// - connector.ConnectorSpecificConfig: a map that stores the connector-specific configuration.
-//
-// The function uses the following methods:
-// - web.Base.Config: a method that sets the connector model and initializes the result channel.
-// - connector.ConnectorSpecificConfig.ToStruct: a method that converts the connector-specific configuration to a struct.
-// - web.Validate: a method that validates the WebParameters.
-//
-// The function uses the following package:
-// - "github.com/clinkerhq/config-server/repository": a repository package that provides the interface for interacting with the connector repository.
-//
-// The function returns the following values
-// - Connector: an interface that represents a connector.
-// - error: an error, if any, encountered during the execution of the function.
 func NewWeb(connector *model.Connector) (Connector, error) {
 	web := Web{}
 	web.Base.Config(connector)
@@ -196,5 +700,30 @@ func NewWeb(connector *model.Connector) (Connector, error) {
 	if err := web.Validate(); err != nil {
 		return nil, err
 	}
+	for _, pattern := range web.param.IncludePatterns {
+		web.include = append(web.include, regexp.MustCompile(pattern))
+	}
+	for _, pattern := range web.param.ExcludePatterns {
+		web.exclude = append(web.exclude, regexp.MustCompile(pattern))
+	}
+	web.client = resty.New().
+		SetTimeout(time.Minute).
+		SetHeader("User-Agent", webUserAgent).
+		SetRetryCount(3).
+		AddRetryCondition(func(response *resty.Response, err error) bool {
+			retry := response != nil && (response.StatusCode() == http.StatusTooManyRequests ||
+				response.StatusCode() == http.StatusServiceUnavailable)
+			if retry && response.RawResponse != nil {
+				// the body of the response is not parsed, it is closed before the request is sent again
+				response.RawBody().Close()
+			}
+			return retry
+		}).
+		SetRetryAfter(func(client *resty.Client, response *resty.Response) (time.Duration, error) {
+			if seconds, err := strconv.Atoi(response.Header().Get("Retry-After")); err == nil {
+				return time.Duration(seconds) * time.Second, nil
+			}
+			return webDefaultDelay, nil
+		})
 	return &web, nil
 }
@@ -3,31 +3,171 @@ package connector
 import (
 	"cognix.ch/api/v2/core/model"
 	"context"
+	"fmt"
+	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestWeb_Execute(t *testing.T) {
-	web, err := NewWeb(
-		&model.Connector{
-			ID:   decimal.NewFromInt(1),
-			Name: "web test",
-			Type: "web",
-			ConnectorSpecificConfig: model.JSONMap{
-				"url1": "https://help.collaboard.app/",
-				"url2": "https://apidog.com/blog/openapi-specification/",
-				"url":  "https://developer.apple.com/documentation/visionos/improving-accessibility-support-in-your-app",
-				"url3": "https://developer.mozilla.org/en-US/docs/Learn/HTML/Introduction_to_HTML",
-			},
-			DocsMap: make(map[string]*model.Document),
-		})
-	if err != nil {
-		t.Log(err.Error())
-		t.Fatal(err)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private\n\nSitemap: "+server.URL+"/sitemap.xml\n")
+		case "/sitemap.xml":
+			fmt.Fprint(w, `<?xml version="1.0"?><urlset><url><loc>`+server.URL+`/from-sitemap</loc></url></urlset>`)
+		case "/":
+			w.Header().Set("ETag", `"root-v2"`)
+			fmt.Fprint(w, `<html><head><title>Home</title></head><body>
+				<nav><a href="/docs">Docs</a> <a href="/private/x">Private</a> <a href="/archive/1">Archive</a></nav>
+				<main><h2>Welcome</h2><p>Start <b>here</b>.</p><script>track()</script></main>
+				<a href="https://other.site/page">Other</a> <a href="/logo.png">Logo</a></body></html>`)
+		case "/docs":
+			w.Header().Set("Last-Modified", "Mon, 01 Jul 2024 00:00:00 GMT")
+			fmt.Fprint(w, `<html><head><title>Docs</title></head><body><p>Manual</p><a href="/docs/deep#top">Deep</a><a href="/secret">Secret</a></body></html>`)
+		case "/docs/deep":
+			fmt.Fprint(w, `<html><body><p>Too deep to follow</p><a href="/deeper">Deeper</a></body></html>`)
+		case "/from-sitemap":
+			w.Header().Set("ETag", `"same"`)
+			fmt.Fprint(w, `<html><body><p>Unchanged</p></body></html>`)
+		case "/secret":
+			fmt.Fprint(w, `<html><head><meta name="robots" content="noindex"></head><body>Hidden</body></html>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	conn, err := NewWeb(&model.Connector{
+		ID:   decimal.NewFromInt(1),
+		Type: model.SourceTypeWEB,
+		ConnectorSpecificConfig: model.JSONMap{
+			"url":                server.URL,
+			"url_recursive":      true,
+			"search_for_sitemap": true,
+			"max_depth":          2,
+			"exclude_patterns":   []string{"/archive/"},
+			"delay":              1,
+		},
+		DocsMap: map[string]*model.Document{
+			server.URL:                   {ID: decimal.NewFromInt(1), SourceID: server.URL, Signature: `etag:"root-v1"`},
+			server.URL + "/from-sitemap": {ID: decimal.NewFromInt(2), Signature: `etag:"same"`},
+			server.URL + "/removed":      {ID: decimal.NewFromInt(3)},
+		},
+		User: &model.User{EmbeddingModel: &model.EmbeddingModel{TenantID: uuid.New()}},
+	})
+	assert.NoError(t, err)
+	c := conn.(*Web)
+
+	var responses []*Response
+	for response := range c.Execute(context.Background(), map[string]string{}) {
+		responses = append(responses, response)
 	}
-	conn := web.Execute(context.Background(), nil)
 
-	for res := range conn {
-		t.Log(res.URL)
+	if assert.Len(t, responses, 3) {
+		assert.Equal(t, server.URL, responses[0].SourceID)
+		assert.Equal(t, "", responses[0].ParentSourceID)
+		assert.Equal(t, `etag:"root-v2"`, responses[0].Signature)
+		assert.Equal(t, "# Home\n\n## Welcome\n\nStart **here**.", string(responses[0].Content.Body))
+
+		assert.Equal(t, server.URL+"/docs", responses[1].SourceID)
+		assert.Equal(t, server.URL, responses[1].ParentSourceID)
+		assert.Equal(t, "last-modified:Mon, 01 Jul 2024 00:00:00 GMT", responses[1].Signature)
+
+		assert.Equal(t, server.URL+"/docs/deep", responses[2].SourceID)
+		assert.Equal(t, server.URL, responses[2].ParentSourceID)
+		assert.Equal(t, "Too deep to follow\n\n[Deeper](/deeper)", string(responses[2].Content.Body))
 	}
+	assert.True(t, c.model.DocsMap[server.URL+"/from-sitemap"].IsExists)
+	assert.False(t, c.model.DocsMap[server.URL+"/removed"].IsExists)
+	assert.NotContains(t, c.model.DocsMap, server.URL+"/deeper")
+	assert.NotContains(t, c.model.DocsMap, server.URL+"/secret")
+	assert.NotContains(t, c.model.DocsMap, server.URL+"/private/x")
+	assert.NotContains(t, c.model.DocsMap, server.URL+"/archive/1")
+}
+
+func TestWeb_ExecuteKeepsDocumentsWhenRobotsFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	conn, err := NewWeb(&model.Connector{
+		ID:                      decimal.NewFromInt(1),
+		Type:                    model.SourceTypeWEB,
+		ConnectorSpecificConfig: model.JSONMap{"url": server.URL, "delay": 1},
+		DocsMap: map[string]*model.Document{
+			server.URL + "/page": {ID: decimal.NewFromInt(1)},
+		},
+		User: &model.User{EmbeddingModel: &model.EmbeddingModel{TenantID: uuid.New()}},
+	})
+	assert.NoError(t, err)
+	c := conn.(*Web)
+	c.client.SetRetryCount(0)
+
+	for range c.Execute(context.Background(), map[string]string{}) {
+		t.Fatal("no documents expected")
+	}
+	assert.True(t, c.model.DocsMap[server.URL+"/page"].IsExists)
+}
+
+func TestWeb_GetSizeLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html>a large page</html>"))
+	}))
+	defer server.Close()
+
+	conn, err := NewWeb(&model.Connector{
+		ID:                      decimal.NewFromInt(1),
+		Type:                    model.SourceTypeWEB,
+		ConnectorSpecificConfig: model.JSONMap{"url": server.URL},
+	})
+	assert.NoError(t, err)
+	c := conn.(*Web)
+
+	c.fileSizeLimit = 100
+	response, err := c.get(context.Background(), c.client.R(), server.URL)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "<html>a large page</html>", string(response.Body()))
+	}
+	// pages larger than the limit are not read
+	c.fileSizeLimit = 10
+	_, err = c.get(context.Background(), c.client.R(), server.URL)
+	assert.Error(t, err)
+}
+
+func TestParseRobots(t *testing.T) {
+	robots := parseRobots(`# comment
+User-agent: *
+Disallow: /
+
+User-agent: other
+User-agent: CognixCrawler
+Disallow: /private
+Allow: /private/public
+Disallow: /*.pdf$
+Crawl-delay: 2
+
+Sitemap: https://site/sitemap.xml`, webRobotsAgent)
+
+	assert.True(t, robots.Allowed("/docs"))
+	assert.False(t, robots.Allowed("/private/page"))
+	assert.True(t, robots.Allowed("/private/public/page"))
+	assert.False(t, robots.Allowed("/files/a.pdf"))
+	assert.True(t, robots.Allowed("/files/a.pdf?download=1"))
+	assert.Equal(t, 2*time.Second, robots.delay)
+	assert.Equal(t, []string{"https://site/sitemap.xml"}, robots.sitemaps)
+
+	robots = parseRobots("User-agent: *\nDisallow: /\nAllow: /$\n", webRobotsAgent)
+	assert.True(t, robots.Allowed("/"))
+	assert.False(t, robots.Allowed("/page"))
+
+	// the group of the crawler allows everything, the * group is not used
+	robots = parseRobots("User-agent: *\nDisallow: /\n\nUser-agent: CognixCrawler\nDisallow:\n", webRobotsAgent)
+	assert.True(t, robots.Allowed("/page"))
 }
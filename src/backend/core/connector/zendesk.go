@@ -0,0 +1,505 @@
+package connector
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"cognix.ch/api/v2/core/proto"
+	"cognix.ch/api/v2/core/repository"
+	"cognix.ch/api/v2/core/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/go-pg/pg/v10"
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	zendeskArticles        = "/api/v2/help_center/%sarticles.json"
+	zendeskSectionArticles = "/api/v2/help_center/%ssections/%s/articles.json"
+	zendeskTicketExport    = "/api/v2/incremental/tickets/cursor.json"
+	zendeskTicketComments  = "/api/v2/tickets/%d/comments.json"
+	zendeskTicketURL       = "%s/agent/tickets/%d"
+	zendeskPageLen         = 100
+
+	zendeskArticlePrefix = "zendesk:article:"
+	zendeskTicketPrefix  = "zendesk:ticket:"
+)
+
+// zendeskResolvedStatuses are statuses of tickets that are loaded as historical resolutions.
+var zendeskResolvedStatuses = map[string]bool{
+	"solved": true,
+	"closed": true,
+}
+
+// Zendesk is a struct that represents the Zendesk connector.
+//
+// The struct contains the following fields:
+// - Base: a struct that represents the base properties and methods needed for various connectors.
+// - param: a pointer to the ZendeskParameters struct that contains the connector parameters.
+// - state: a pointer to the ZendeskState struct that stores the cursor of the incremental ticket export.
+// - client: a pointer to the resty.Client struct for making requests to the Zendesk API.
+// - sessionID: a uuid.NullUUID representing the session ID.
+type (
+	Zendesk struct {
+		Base
+		param     *ZendeskParameters
+		state     *ZendeskState
+		client    *resty.Client
+		sessionID uuid.NullUUID
+	}
+	// ZendeskParameters contains the URL of the Zendesk account (https://<subdomain>.zendesk.com),
+	// locales and ids of sections of help-center articles (the default locale and all sections if empty)
+	// and credentials. The email is used with the API token, the OAuth access token is used without the email.
+	// LoadTickets enables loading of solved tickets with their public comments.
+	ZendeskParameters struct {
+		BaseURL     string            `json:"base_url"`
+		Locales     model.StringSlice `json:"locales"`
+		Sections    model.StringSlice `json:"sections"`
+		LoadTickets bool              `json:"load_tickets"`
+		Email       string            `json:"email"`
+		Token       string            `json:"token"`
+	}
+	// ZendeskState stores the cursor of the incremental ticket export. Tickets changed after the cursor
+	// are requested by the next execution.
+	ZendeskState struct {
+		TicketCursor string `json:"ticket_cursor"`
+	}
+
+	zendeskArticle struct {
+		ID        int64  `json:"id"`
+		Title     string `json:"title"`
+		Body      string `json:"body"`
+		Locale    string `json:"locale"`
+		HTMLURL   string `json:"html_url"`
+		Draft     bool   `json:"draft"`
+		UpdatedAt string `json:"updated_at"`
+	}
+	zendeskArticlesResponse struct {
+		Articles []*zendeskArticle `json:"articles"`
+		Meta     struct {
+			HasMore bool `json:"has_more"`
+		} `json:"meta"`
+		Links struct {
+			Next string `json:"next"`
+		} `json:"links"`
+	}
+	zendeskTicket struct {
+		ID          int64    `json:"id"`
+		Subject     string   `json:"subject"`
+		Description string   `json:"description"`
+		Status      string   `json:"status"`
+		Priority    string   `json:"priority"`
+		Type        string   `json:"type"`
+		Tags        []string `json:"tags"`
+		CreatedAt   string   `json:"created_at"`
+		UpdatedAt   string   `json:"updated_at"`
+	}
+	zendeskTicketsResponse struct {
+		Tickets     []*zendeskTicket `json:"tickets"`
+		AfterCursor string           `json:"after_cursor"`
+		EndOfStream bool             `json:"end_of_stream"`
+	}
+	zendeskUser struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	zendeskComment struct {
+		AuthorID  int64  `json:"author_id"`
+		PlainBody string `json:"plain_body"`
+		Public    bool   `json:"public"`
+		CreatedAt string `json:"created_at"`
+	}
+	zendeskCommentsResponse struct {
+		Comments []*zendeskComment `json:"comments"`
+		Users    []*zendeskUser    `json:"users"`
+		Meta     struct {
+			HasMore bool `json:"has_more"`
+		} `json:"meta"`
+		Links struct {
+			Next string `json:"next"`
+		} `json:"links"`
+	}
+)
+
+// Validate checks if the ZendeskParameters struct is valid.
+// It returns an error if the URL of the account or the token is missing.
+func (p ZendeskParameters) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.BaseURL, validation.Required, is.URL),
+		validation.Field(&p.Token, validation.Required),
+	)
+}
+
+// Validate checks if the Zendesk parameter is valid.
+func (c *Zendesk) Validate() error {
+	if c.param == nil {
+		return fmt.Errorf("zendesk parameter is required")
+	}
+	return c.param.Validate()
+}
+
+// PrepareTask sends the connector request with the session ID to the connector service.
+func (c *Zendesk) PrepareTask(ctx context.Context, sessionID uuid.UUID, task Task) error {
+	params := make(map[string]string)
+	params[model.ParamSessionID] = sessionID.String()
+	return task.RunConnector(ctx, &proto.ConnectorRequest{
+		Id:     c.model.ID.IntPart(),
+		Params: params,
+	})
+}
+
+// Execute executes the Zendesk connector with the given context and parameters. It returns a channel of Response
+// objects. Articles are listed completely, so documents of deleted articles are removed by the executor.
+// Tickets are exported incrementally, so documents of tickets loaded before are kept unless the ticket
+// was deleted or reopened or loading of tickets is disabled.
+func (c *Zendesk) Execute(ctx context.Context, param map[string]string) chan *Response {
+	paramSessionID, _ := param[model.ParamSessionID]
+	if uuidSessionID, err := uuid.Parse(paramSessionID); err != nil {
+		c.sessionID = uuid.NullUUID{uuid.New(), true}
+	} else {
+		c.sessionID = uuid.NullUUID{uuidSessionID, true}
+	}
+	if len(c.model.DocsMap) == 0 {
+		c.model.DocsMap = make(map[string]*model.Document)
+	}
+	if c.param.LoadTickets {
+		c.keepDocuments(zendeskTicketPrefix)
+	}
+	go func() {
+		defer close(c.resultCh)
+		if err := c.execute(ctx); err != nil {
+			zap.S().Errorf("execute %s ", err.Error())
+		}
+	}()
+	return c.resultCh
+}
+
+// execute loads articles and tickets and saves the cursor of the ticket export in the state of the connector.
+func (c *Zendesk) execute(ctx context.Context) error {
+	if err := c.loadArticles(ctx); err != nil {
+		zap.S().Errorf("error loading articles : %s", err.Error())
+		// the list of articles is incomplete, keep all articles
+		c.keepDocuments(zendeskArticlePrefix)
+	}
+	if !c.param.LoadTickets {
+		return nil
+	}
+	ticketsErr := c.loadTickets(ctx)
+	zap.S().Infof("save connector state.")
+	if err := c.model.State.FromStruct(c.state); err == nil {
+		if err = c.connectorRepo.Update(ctx, c.model); err != nil {
+			return err
+		}
+	}
+	return ticketsErr
+}
+
+// loadArticles loads published articles of configured locales and sections.
+func (c *Zendesk) loadArticles(ctx context.Context) error {
+	locales := c.param.Locales
+	if len(locales) == 0 {
+		// the default locale of the help center
+		locales = model.StringSlice{""}
+	}
+	for _, locale := range locales {
+		localePath := ""
+		if locale != "" {
+			localePath = strings.ToLower(locale) + "/"
+		}
+		urls := []string{fmt.Sprintf(zendeskArticles, localePath)}
+		if len(c.param.Sections) > 0 {
+			urls = urls[:0]
+			for _, section := range c.param.Sections {
+				urls = append(urls, fmt.Sprintf(zendeskSectionArticles, localePath, section))
+			}
+		}
+		for _, url := range urls {
+			if err := c.loadArticlePages(ctx, url); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loadArticlePages requests articles page by page and loads each of them.
+func (c *Zendesk) loadArticlePages(ctx context.Context, url string) error {
+	params := map[string]string{"page[size]": strconv.Itoa(zendeskPageLen)}
+	for url != "" {
+		var response zendeskArticlesResponse
+		if err := c.requestAndParse(ctx, url, params, &response); err != nil {
+			return err
+		}
+		for _, article := range response.Articles {
+			if article.Draft {
+				continue
+			}
+			if err := c.loadArticle(article); err != nil {
+				zap.S().Errorf("error loading article %d : %s", article.ID, err.Error())
+				// keep the previous version of the article
+				if doc, ok := c.model.DocsMap[zendeskArticleSourceID(article)]; ok {
+					doc.IsExists = true
+				}
+			}
+		}
+		if !response.Meta.HasMore {
+			break
+		}
+		// the link to the next page contains all parameters
+		url, params = response.Links.Next, nil
+	}
+	return nil
+}
+
+// loadArticle converts the body of the article to markdown and sends it if the article was updated.
+func (c *Zendesk) loadArticle(article *zendeskArticle) error {
+	sourceID := zendeskArticleSourceID(article)
+	if doc, ok := c.model.DocsMap[sourceID]; ok {
+		doc.IsExists = true
+		if doc.Signature == article.UpdatedAt {
+			return nil
+		}
+	}
+	content, err := storageToMarkdown(article.Body)
+	if err != nil {
+		return err
+	}
+	c.send(sourceID, article.Title, article.HTMLURL, article.UpdatedAt,
+		joinNotEmpty("# "+article.Title, content))
+	return nil
+}
+
+// loadTickets exports tickets changed after the cursor of the state. The cursor is moved after each page
+// of tickets, a failed page is requested again by the next execution. A ticket whose comments can not be
+// loaded is logged and keeps its document, it is loaded again when it is updated.
+func (c *Zendesk) loadTickets(ctx context.Context) error {
+	for {
+		params := map[string]string{"per_page": strconv.Itoa(zendeskPageLen)}
+		if c.state.TicketCursor != "" {
+			params["cursor"] = c.state.TicketCursor
+		} else {
+			params["start_time"] = "1"
+		}
+		var response zendeskTicketsResponse
+		if err := c.requestAndParse(ctx, zendeskTicketExport, params, &response); err != nil {
+			return err
+		}
+		for _, ticket := range response.Tickets {
+			if err := c.loadTicket(ctx, ticket); err != nil {
+				zap.S().Errorf("error loading ticket %d : %s", ticket.ID, err.Error())
+			}
+		}
+		if response.AfterCursor != "" {
+			c.state.TicketCursor = response.AfterCursor
+		}
+		if response.EndOfStream || response.AfterCursor == "" {
+			return nil
+		}
+	}
+}
+
+// loadTicket loads public comments of the solved ticket and sends it if the ticket was updated.
+// Documents of deleted and reopened tickets are not marked as existing, so the executor deletes them.
+func (c *Zendesk) loadTicket(ctx context.Context, ticket *zendeskTicket) error {
+	sourceID := fmt.Sprintf("%s%d", zendeskTicketPrefix, ticket.ID)
+	doc, ok := c.model.DocsMap[sourceID]
+	if !zendeskResolvedStatuses[ticket.Status] {
+		if ok {
+			doc.IsExists = false
+		}
+		return nil
+	}
+	if ok && doc.Signature == ticket.UpdatedAt {
+		return nil
+	}
+	comments, users, err := c.getComments(ctx, ticket.ID)
+	if err != nil {
+		if ok {
+			doc.IsExists = true
+		}
+		return err
+	}
+	c.send(sourceID, fmt.Sprintf("ticket-%d", ticket.ID),
+		fmt.Sprintf(zendeskTicketURL, strings.TrimSuffix(c.param.BaseURL, "/"), ticket.ID),
+		ticket.UpdatedAt, buildTicketMD(ticket, comments, users))
+	return nil
+}
+
+// getComments returns public comments of the ticket and names of their authors by ids.
+func (c *Zendesk) getComments(ctx context.Context, ticketID int64) ([]*zendeskComment, map[int64]string, error) {
+	var comments []*zendeskComment
+	users := make(map[int64]string)
+	url := fmt.Sprintf(zendeskTicketComments, ticketID)
+	params := map[string]string{"include": "users", "page[size]": strconv.Itoa(zendeskPageLen)}
+	for url != "" {
+		var response zendeskCommentsResponse
+		if err := c.requestAndParse(ctx, url, params, &response); err != nil {
+			return nil, nil, err
+		}
+		for _, comment := range response.Comments {
+			if comment.Public {
+				comments = append(comments, comment)
+			}
+		}
+		for _, user := range response.Users {
+			users[user.ID] = user.Name
+		}
+		if !response.Meta.HasMore {
+			break
+		}
+		url, params = response.Links.Next, nil
+	}
+	return comments, users, nil
+}
+
+// send creates or updates the markdown document and sends it to the result channel.
+func (c *Zendesk) send(sourceID, name, originalURL, signature, content string) {
+	doc, ok := c.model.DocsMap[sourceID]
+	fileName := ""
+	if !ok {
+		doc = &model.Document{
+			SourceID:     sourceID,
+			ConnectorID:  c.model.ID,
+			CreationDate: time.Now().UTC(),
+		}
+		c.model.DocsMap[sourceID] = doc
+	} else {
+		// rewrite the file of the existing document
+		minioFile := strings.Split(doc.URL, ":")
+		if len(minioFile) == 3 && minioFile[0] == "minio" {
+			fileName = minioFile[2]
+		}
+	}
+	if fileName == "" {
+		fileName = utils.StripFileName(c.model.BuildFileName(fmt.Sprintf("%s-%s.md", uuid.New().String(), name)))
+	}
+	doc.Signature = signature
+	doc.ChunkingSession = c.sessionID
+	doc.LastUpdate = pg.NullTime{time.Now().UTC()}
+	doc.OriginalURL = originalURL
+	doc.IsExists = true
+
+	c.resultCh <- &Response{
+		URL:        doc.URL,
+		Name:       fileName,
+		SourceID:   sourceID,
+		DocumentID: doc.ID.IntPart(),
+		MimeType:   "text/markdown",
+		FileType:   proto.FileType_MD,
+		Signature:  signature,
+		Content: &Content{
+			Bucket: model.BucketName(c.model.User.EmbeddingModel.TenantID),
+			Body:   []byte(content),
+		},
+	}
+}
+
+// keepDocuments marks documents with the source id prefix as existing.
+func (c *Zendesk) keepDocuments(prefix string) {
+	for sourceID, doc := range c.model.DocsMap {
+		if strings.HasPrefix(sourceID, prefix) {
+			doc.IsExists = true
+		}
+	}
+}
+
+// requestAndParse sends a GET request to the Zendesk API and parses the response into the result.
+func (c *Zendesk) requestAndParse(ctx context.Context, url string, params map[string]string, result interface{}) error {
+	response, err := c.client.R().
+		SetContext(ctx).
+		SetQueryParams(params).
+		Get(url)
+	if err = utils.WrapRestyError(response, err); err != nil {
+		return err
+	}
+	return json.Unmarshal(response.Body(), result)
+}
+
+// zendeskArticleSourceID returns the source id of the translation of the article.
+func zendeskArticleSourceID(article *zendeskArticle) string {
+	return fmt.Sprintf("%s%s:%d", zendeskArticlePrefix, strings.ToLower(article.Locale), article.ID)
+}
+
+// buildTicketMD renders the ticket with its public comments as markdown.
+func buildTicketMD(ticket *zendeskTicket, comments []*zendeskComment, users map[int64]string) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("# Ticket #%d: %s\n\n", ticket.ID, ticket.Subject))
+	builder.WriteString(fmt.Sprintf("- Status: %s\n", ticket.Status))
+	if ticket.Type != "" {
+		builder.WriteString(fmt.Sprintf("- Type: %s\n", ticket.Type))
+	}
+	if ticket.Priority != "" {
+		builder.WriteString(fmt.Sprintf("- Priority: %s\n", ticket.Priority))
+	}
+	if len(ticket.Tags) > 0 {
+		builder.WriteString(fmt.Sprintf("- Tags: %s\n", strings.Join(ticket.Tags, ", ")))
+	}
+	builder.WriteString(fmt.Sprintf("- Created: %s\n", ticket.CreatedAt))
+	builder.WriteString(fmt.Sprintf("- Updated: %s", ticket.UpdatedAt))
+	if len(comments) == 0 {
+		if ticket.Description != "" {
+			builder.WriteString("\n\n## Description\n\n" + ticket.Description)
+		}
+		return builder.String()
+	}
+	// the first comment is the description of the ticket
+	builder.WriteString("\n\n## Comments")
+	for _, comment := range comments {
+		author, ok := users[comment.AuthorID]
+		if !ok {
+			author = strconv.FormatInt(comment.AuthorID, 10)
+		}
+		builder.WriteString(fmt.Sprintf("\n\n### %s, %s\n\n%s", author, comment.CreatedAt, strings.TrimSpace(comment.PlainBody)))
+	}
+	return builder.String()
+}
+
+// NewZendesk creates new instance of Zendesk connector
+func NewZendesk(connector *model.Connector,
+	connectorRepo repository.ConnectorRepository) (Connector, error) {
+	conn := Zendesk{
+		Base: Base{
+			connectorRepo: connectorRepo,
+		},
+		param: &ZendeskParameters{},
+		state: &ZendeskState{},
+	}
+	conn.Base.Config(connector)
+
+	if err := connector.ConnectorSpecificConfig.ToStruct(conn.param); err != nil {
+		return nil, err
+	}
+	if err := conn.Validate(); err != nil {
+		return nil, err
+	}
+	if err := connector.State.ToStruct(conn.state); err != nil {
+		zap.S().Infof("can not parse state %v", err)
+	}
+	conn.client = resty.New().
+		SetTimeout(time.Minute).
+		SetBaseURL(strings.TrimSuffix(conn.param.BaseURL, "/")).
+		SetRetryCount(3).
+		AddRetryCondition(func(response *resty.Response, err error) bool {
+			return response != nil && response.StatusCode() == http.StatusTooManyRequests
+		}).
+		SetRetryAfter(func(client *resty.Client, response *resty.Response) (time.Duration, error) {
+			if seconds, err := strconv.Atoi(response.Header().Get("Retry-After")); err == nil {
+				return time.Duration(seconds) * time.Second, nil
+			}
+			return time.Second, nil
+		})
+	if conn.param.Email != "" {
+		// API tokens are used with the email followed by /token
+		conn.client.SetBasicAuth(conn.param.Email+"/token", conn.param.Token)
+	} else {
+		conn.client.SetAuthToken(conn.param.Token)
+	}
+	return &conn, nil
+}
@@ -0,0 +1,152 @@
+package connector
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newZendeskTestConnector(t *testing.T, baseURL string, docs map[string]*model.Document) *Zendesk {
+	conn, err := NewZendesk(&model.Connector{
+		ID:   decimal.NewFromInt(1),
+		Type: model.SourceTypeZendesk,
+		ConnectorSpecificConfig: model.JSONMap{
+			"base_url":     baseURL,
+			"locales":      []string{"en-US"},
+			"sections":     []string{"10"},
+			"load_tickets": true,
+			"email":        "agent@test.com",
+			"token":        "token",
+		},
+		State:   model.JSONMap{"ticket_cursor": "start"},
+		DocsMap: docs,
+		User:    &model.User{EmbeddingModel: &model.EmbeddingModel{TenantID: uuid.New()}},
+	}, nil)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return conn.(*Zendesk)
+}
+
+func TestZendesk_LoadArticles(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ := r.BasicAuth()
+		assert.Equal(t, "agent@test.com/token", user)
+		assert.Equal(t, "token", password)
+		if r.URL.Path != "/api/v2/help_center/en-us/sections/10/articles.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body interface{}
+		if r.URL.Query().Get("page[after]") == "" {
+			body = map[string]interface{}{
+				"articles": []map[string]interface{}{
+					{"id": 1, "title": "Reset password", "body": "<p>Open <b>Settings</b>.</p>", "locale": "en-us",
+						"html_url": "https://help/1", "updated_at": "2024-07-02T00:00:00Z"},
+					{"id": 2, "title": "Draft", "draft": true, "locale": "en-us"},
+				},
+				"meta":  map[string]bool{"has_more": true},
+				"links": map[string]string{"next": server.URL + r.URL.Path + "?page[after]=x"},
+			}
+		} else {
+			body = map[string]interface{}{"articles": []map[string]interface{}{
+				{"id": 3, "title": "Unchanged", "locale": "en-us", "updated_at": "2024-07-01T00:00:00Z"},
+			}}
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	c := newZendeskTestConnector(t, server.URL, map[string]*model.Document{
+		"zendesk:article:en-us:3": {ID: decimal.NewFromInt(3), Signature: "2024-07-01T00:00:00Z"},
+		"zendesk:article:en-us:4": {ID: decimal.NewFromInt(4)},
+	})
+	go func() {
+		defer close(c.resultCh)
+		assert.NoError(t, c.loadArticles(context.Background()))
+	}()
+	var responses []*Response
+	for response := range c.resultCh {
+		responses = append(responses, response)
+	}
+
+	if assert.Len(t, responses, 1) {
+		assert.Equal(t, "zendesk:article:en-us:1", responses[0].SourceID)
+		assert.Equal(t, "# Reset password\n\nOpen **Settings**.", string(responses[0].Content.Body))
+		assert.Equal(t, "https://help/1", c.model.DocsMap["zendesk:article:en-us:1"].OriginalURL)
+	}
+	assert.True(t, c.model.DocsMap["zendesk:article:en-us:3"].IsExists)
+	assert.False(t, c.model.DocsMap["zendesk:article:en-us:4"].IsExists)
+}
+
+func TestZendesk_LoadTickets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+		switch r.URL.Path {
+		case zendeskTicketExport:
+			switch r.URL.Query().Get("cursor") {
+			case "start":
+				body = map[string]interface{}{"after_cursor": "page2", "tickets": []map[string]interface{}{
+					{"id": 7, "subject": "Cannot log in", "status": "solved", "tags": []string{"login"},
+						"created_at": "2024-07-01T00:00:00Z", "updated_at": "2024-07-02T00:00:00Z"},
+					{"id": 8, "subject": "Reopened", "status": "open"},
+					{"id": 9, "subject": "Broken comments", "status": "solved", "updated_at": "2024-07-03T00:00:00Z"},
+				}}
+			case "page2":
+				body = map[string]interface{}{"after_cursor": "page3", "end_of_stream": true, "tickets": []map[string]interface{}{}}
+			}
+		case "/api/v2/tickets/7/comments.json":
+			body = map[string]interface{}{
+				"comments": []map[string]interface{}{
+					{"author_id": 1, "plain_body": "I cannot log in.", "public": true, "created_at": "2024-07-01T00:00:00Z"},
+					{"author_id": 2, "plain_body": "Internal note", "public": false, "created_at": "2024-07-01T01:00:00Z"},
+					{"author_id": 2, "plain_body": "Reset the password in Settings.", "public": true, "created_at": "2024-07-01T02:00:00Z"},
+				},
+				"users": []map[string]interface{}{{"id": 1, "name": "Customer"}, {"id": 2, "name": "Agent"}},
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	c := newZendeskTestConnector(t, server.URL, map[string]*model.Document{
+		"zendesk:ticket:8": {ID: decimal.NewFromInt(8), IsExists: true},
+		"zendesk:ticket:9": {ID: decimal.NewFromInt(9), Signature: "2024-07-02T00:00:00Z"},
+	})
+	go func() {
+		defer close(c.resultCh)
+		assert.NoError(t, c.loadTickets(context.Background()))
+	}()
+	var responses []*Response
+	for response := range c.resultCh {
+		responses = append(responses, response)
+	}
+
+	if assert.Len(t, responses, 1) {
+		assert.Equal(t, "zendesk:ticket:7", responses[0].SourceID)
+		assert.Equal(t, "# Ticket #7: Cannot log in\n\n"+
+			"- Status: solved\n"+
+			"- Tags: login\n"+
+			"- Created: 2024-07-01T00:00:00Z\n"+
+			"- Updated: 2024-07-02T00:00:00Z\n\n"+
+			"## Comments\n\n"+
+			"### Customer, 2024-07-01T00:00:00Z\n\nI cannot log in.\n\n"+
+			"### Agent, 2024-07-01T02:00:00Z\n\nReset the password in Settings.", string(responses[0].Content.Body))
+		assert.Equal(t, server.URL+"/agent/tickets/7", c.model.DocsMap["zendesk:ticket:7"].OriginalURL)
+	}
+	// the reopened ticket is no longer a resolution
+	assert.False(t, c.model.DocsMap["zendesk:ticket:8"].IsExists)
+	// comments of the ticket can not be loaded, the previous document is kept
+	assert.True(t, c.model.DocsMap["zendesk:ticket:9"].IsExists)
+	assert.Equal(t, "page3", c.state.TicketCursor)
+}
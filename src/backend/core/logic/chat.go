@@ -104,13 +104,17 @@ func (b *chatBL) SendMessage(ctx *gin.Context, user *model.User, param *paramete
 		MessageType:   model.MessageTypeUser,
 		TimeSent:      time.Now().UTC(),
 	}
+	if lastMessage := linkMessages(chatSession.Messages); lastMessage != nil {
+		message.ParentMessageID = lastMessage.ID
+		message.ParentMessage = lastMessage
+	}
 	noLLM := chatSession.Persona == nil
 	if err = b.chatRepo.SendMessage(ctx.Request.Context(), &message); err != nil {
 		return nil, err
 	}
 	aiClient := b.aiBuilder.New(chatSession.Persona.LLM)
 	resp := responder.NewManager(
-		responder.NewAIResponder(aiClient, b.chatRepo,
+		responder.NewAIResponder(b.cfg.Responder, aiClient, b.chatRepo,
 			b.searcher, b.milvusClinet, b.docRepo, em.ModelID),
 	)
 
@@ -118,6 +122,24 @@ func (b *chatBL) SendMessage(ctx *gin.Context, user *model.User, param *paramete
 	return resp, nil
 }
 
+// linkMessages links messages of the chat session with their parent messages
+// and returns the latest message of the session.
+// Messages ordered by time. If the parent message is not set, the previous message is used as the parent.
+func linkMessages(messages []*model.ChatMessage) *model.ChatMessage {
+	messagesMap := make(map[int64]*model.ChatMessage)
+	var previous *model.ChatMessage
+	for _, message := range messages {
+		if parent, ok := messagesMap[message.ParentMessageID.IntPart()]; ok {
+			message.ParentMessage = parent
+		} else {
+			message.ParentMessage = previous
+		}
+		messagesMap[message.ID.IntPart()] = message
+		previous = message
+	}
+	return previous
+}
+
 // GetSessions retrieves the chat sessions for a given user.
 //
 // Parameters:
@@ -0,0 +1,30 @@
+package logic
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLinkMessages(t *testing.T) {
+	assert.Nil(t, linkMessages(nil))
+
+	messages := []*model.ChatMessage{
+		{ID: decimal.NewFromInt(1), Message: "first question"},
+		{ID: decimal.NewFromInt(2), Message: "first answer", ParentMessageID: decimal.NewFromInt(1)},
+		{ID: decimal.NewFromInt(3), Message: "second question", ParentMessageID: decimal.NewFromInt(2)},
+		// the parent is not loaded, the previous message is used instead
+		{ID: decimal.NewFromInt(4), Message: "second answer", ParentMessageID: decimal.NewFromInt(10)},
+		// the answer was regenerated, the previous message is not its parent
+		{ID: decimal.NewFromInt(5), Message: "regenerated answer", ParentMessageID: decimal.NewFromInt(3)},
+	}
+	last := linkMessages(messages)
+	var chain []string
+	for message := last; message != nil; message = message.ParentMessage {
+		chain = append(chain, message.Message)
+	}
+	// the chain starts with the current message and ends with the oldest one
+	assert.Equal(t, []string{"regenerated answer", "second question", "first answer", "first question"}, chain)
+	assert.Equal(t, "second question", messages[3].ParentMessage.Message)
+}
@@ -1,17 +1,23 @@
 package logic
 
 import (
+	"cognix.ch/api/v2/core/messaging"
 	"cognix.ch/api/v2/core/model"
 	"cognix.ch/api/v2/core/parameters"
+	"cognix.ch/api/v2/core/proto"
 	"cognix.ch/api/v2/core/repository"
 	"cognix.ch/api/v2/core/utils"
 	"context"
+	"fmt"
 	"github.com/go-pg/pg/v10"
 	"github.com/google/uuid"
 	"time"
 )
 
-const minRefreshFreq = 3600
+const (
+	minRefreshFreq           = 3600
+	connectorRunDefaultLimit = 50
+)
 
 type (
 
@@ -22,13 +28,18 @@ type (
 		Create(ctx context.Context, user *model.User, param *parameters.CreateConnectorParam) (*model.Connector, error)
 		Update(ctx context.Context, id int64, user *model.User, param *parameters.UpdateConnectorParam) (*model.Connector, error)
 		Archive(ctx context.Context, user *model.User, id int64, restore bool) (*model.Connector, error)
+		GetRuns(ctx context.Context, user *model.User, id int64, param *parameters.ConnectorRunListParam) ([]*model.ConnectorRun, error)
+		GetRun(ctx context.Context, user *model.User, id, runID int64) (*model.ConnectorRun, error)
+		Sync(ctx context.Context, user *model.User, id int64, mode string) (*model.ConnectorRun, error)
 	}
 
 	// connectorBL represents the business logic implementation for managing connectors.
 	// It contains a reference to the connector repository for data access.
 	connectorBL struct {
-		connectorRepo repository.ConnectorRepository
-		//messenger      messaging.Client
+		connectorRepo    repository.ConnectorRepository
+		connectorRunRepo repository.ConnectorRunRepository
+		messenger        messaging.Client
+		cfg              *Config
 	}
 )
 
@@ -74,8 +85,15 @@ func (c *connectorBL) Archive(ctx context.Context, user *model.User, id int64, r
 //
 // Returns:
 // - ConnectorBL: an instance of ConnectorBL interface.
-func NewConnectorBL(connectorRepo repository.ConnectorRepository) ConnectorBL {
-	return &connectorBL{connectorRepo: connectorRepo}
+func NewConnectorBL(connectorRepo repository.ConnectorRepository,
+	connectorRunRepo repository.ConnectorRunRepository,
+	messenger messaging.Client,
+	cfg *Config) ConnectorBL {
+	return &connectorBL{connectorRepo: connectorRepo,
+		connectorRunRepo: connectorRunRepo,
+		messenger:        messenger,
+		cfg:              cfg,
+	}
 }
 
 // Create creates a new connector with the provided parameters.
@@ -104,6 +122,7 @@ func (c *connectorBL) Create(ctx context.Context, user *model.User, param *param
 		Type:                    model.SourceType(param.Source),
 		ConnectorSpecificConfig: param.ConnectorSpecificConfig,
 		RefreshFreq:             param.RefreshFreq,
+		Schedule:                param.Schedule,
 		UserID:                  user.ID,
 		TenantID:                tenantID,
 		Status:                  model.ConnectorStatusReadyToProcessed,
@@ -120,7 +139,7 @@ func (c *connectorBL) Create(ctx context.Context, user *model.User, param *param
 }
 
 // Update updates the details of a connector.
-// It updates the connector's ConnectorSpecificConfig, Name, RefreshFreq, TenantID, and LastUpdate fields.
+// It updates the connector's ConnectorSpecificConfig, Name, RefreshFreq, Schedule, TenantID, and LastUpdate fields.
 // If the connector is shared, the TenantID field will be set to the user's TenantID.
 // The LastUpdate field will be updated to the current date and time.
 // If the Status field is provided, it will be updated.
@@ -143,6 +162,7 @@ func (c *connectorBL) Update(ctx context.Context, id int64, user *model.User, pa
 	conn.ConnectorSpecificConfig = param.ConnectorSpecificConfig
 	conn.Name = param.Name
 	conn.RefreshFreq = param.RefreshFreq
+	conn.Schedule = param.Schedule
 	tenantID := uuid.NullUUID{Valid: false}
 	if param.Shared {
 		tenantID.Valid = true
@@ -189,3 +209,87 @@ func (c *connectorBL) GetAll(ctx context.Context, user *model.User) ([]*model.Co
 func (c *connectorBL) GetByID(ctx context.Context, user *model.User, id int64) (*model.Connector, error) {
 	return c.connectorRepo.GetByIDAndUser(ctx, user.TenantID, user.ID, id)
 }
+
+// GetRuns returns the run history of a connector the user has access to, the latest run first.
+// If param.Limit is not set, the latest 50 runs are returned.
+func (c *connectorBL) GetRuns(ctx context.Context, user *model.User, id int64, param *parameters.ConnectorRunListParam) ([]*model.ConnectorRun, error) {
+	if _, err := c.connectorRepo.GetByIDAndUser(ctx, user.TenantID, user.ID, id); err != nil {
+		return nil, err
+	}
+	if param.Limit == 0 {
+		param.Limit = connectorRunDefaultLimit
+	}
+	return c.connectorRunRepo.GetByConnectorID(ctx, id, param)
+}
+
+// GetRun returns a run of a connector the user has access to, including the errors of its documents.
+func (c *connectorBL) GetRun(ctx context.Context, user *model.User, id, runID int64) (*model.ConnectorRun, error) {
+	if _, err := c.connectorRepo.GetByIDAndUser(ctx, user.TenantID, user.ID, id); err != nil {
+		return nil, err
+	}
+	return c.connectorRunRepo.GetByID(ctx, id, runID)
+}
+
+// Sync sends the connector to the connector service at once, without waiting for its refresh frequency.
+// In the sync mode only new and changed documents are analyzed, in the reindex mode all documents
+// of the connector are dropped and analyzed again.
+// The user must be the owner of the connector, an admin, or a super admin to perform this operation.
+// Only one run of a connector can be pending or processing at a time.
+//
+// Returns the pending run that can be followed with GetRun.
+func (c *connectorBL) Sync(ctx context.Context, user *model.User, id int64, mode string) (*model.ConnectorRun, error) {
+	connector, err := c.connectorRepo.GetByIDAndUser(ctx, user.TenantID, user.ID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !(connector.UserID == user.ID || user.HasRoles(model.RoleAdmin, model.RoleSuperAdmin)) {
+		return nil, utils.ErrorPermission.New("permission denied")
+	}
+	if !connector.DeletedDate.IsZero() {
+		return nil, utils.ErrorBadRequest.New("connector is deleted")
+	}
+	switch connector.Type {
+	case model.SourceTypeFile, model.SourceTypeYoutube, model.SourceTypeIngestionApi:
+		// these documents are not loaded by the connector service
+		return nil, utils.ErrorBadRequest.Newf("connector of type %s can not be synced", connector.Type)
+	}
+	ok, err := c.connectorRepo.SetPending(ctx, connector)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, utils.ErrorConflict.New("connector is already running")
+	}
+	run := &model.ConnectorRun{
+		ConnectorID:    connector.ID,
+		SessionID:      uuid.New(),
+		Status:         model.ConnectorStatusPending,
+		DocumentErrors: make([]*model.ConnectorRunError, 0),
+		CreationDate:   time.Now().UTC(),
+	}
+	if err = c.connectorRunRepo.Create(ctx, run); err != nil {
+		return nil, err
+	}
+	if err = c.messenger.Publish(ctx, c.messenger.StreamConfig().ConnectorStreamName,
+		c.messenger.StreamConfig().ConnectorStreamSubject,
+		&proto.ConnectorRequest{
+			Id: connector.ID.IntPart(),
+			Params: map[string]string{
+				model.ParamSessionID: run.SessionID.String(),
+				model.ParamMode:      mode,
+				model.ParamFileLimit: fmt.Sprintf("%d", c.cfg.FileSizeLimit),
+			},
+		}); err != nil {
+		// the connector is not running, it can be synced again
+		run.Finish(err)
+		connector.Status = model.ConnectorStatusUnableProcess
+		if errr := c.connectorRunRepo.Update(ctx, run); errr != nil {
+			return nil, errr
+		}
+		if errr := c.connectorRepo.Update(ctx, connector); errr != nil {
+			return nil, errr
+		}
+		return nil, utils.Internal.Wrap(err, "can not send connector")
+	}
+	return run, nil
+}
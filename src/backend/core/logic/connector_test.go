@@ -0,0 +1,59 @@
+package logic
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"cognix.ch/api/v2/core/utils"
+	"cognix.ch/api/v2/orchestrator/mocks"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConnectorBL_Sync(t *testing.T) {
+	user := &model.User{ID: uuid.New(), TenantID: uuid.New()}
+	tests := []struct {
+		name     string
+		status   string
+		conflict bool
+	}{
+		{name: "pending", status: model.ConnectorStatusPending, conflict: true},
+		{name: "running", status: model.ConnectorStatusWorking, conflict: true},
+		{name: "completed", status: model.ConnectorStatusSuccess},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connectorRepo := mocks.NewMockConnectorRepoWithConnectors(map[int64]*model.Connector{
+				1: {
+					ID:     decimal.NewFromInt(1),
+					Type:   model.SourceTypeWEB,
+					Status: tt.status,
+					UserID: user.ID,
+				},
+			})
+			runRepo := mocks.NewMockConnectorRunRepo().(*mocks.MockConnectorRunRepository)
+			messenger := mocks.NewMockMessenger(nil).(*mocks.MockMessenger)
+			bl := NewConnectorBL(connectorRepo, runRepo, messenger, &Config{FileSizeLimit: 1})
+
+			run, err := bl.Sync(context.Background(), user, 1, model.ModeReindex)
+			if tt.conflict {
+				var wrapped utils.Errors
+				if assert.True(t, errors.As(err, &wrapped)) {
+					assert.Equal(t, utils.ErrorConflict, wrapped.Code)
+				}
+				assert.Nil(t, run)
+				assert.Empty(t, runRepo.Runs())
+				assert.Empty(t, messenger.Published("connector"))
+				return
+			}
+			assert.NoError(t, err)
+			if assert.NotNil(t, run) {
+				assert.Equal(t, model.ConnectorStatusPending, run.Status)
+			}
+			assert.Len(t, runRepo.Runs(), 1)
+			assert.Equal(t, []int64{1}, messenger.Published("connector"))
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package logic
+
+import (
+	"cognix.ch/api/v2/core/messaging"
+	"cognix.ch/api/v2/core/model"
+	"cognix.ch/api/v2/core/parameters"
+	"cognix.ch/api/v2/core/proto"
+	"cognix.ch/api/v2/core/utils"
+	"context"
+	proto2 "github.com/golang/protobuf/proto"
+)
+
+const deadLetterDefaultLimit = 50
+
+type (
+	// DeadLetterBL is an interface for managing messages that failed in the pipeline streams.
+	// Dead letters contain messages of all tenants, only super administrators can manage them.
+	//
+	// GetAll returns dead letters filtered by stream.
+	// GetByID returns the dead letter with its parsed message.
+	// Replay publishes the message again on its stream and removes the dead letter.
+	// Delete removes the dead letter.
+	DeadLetterBL interface {
+		GetAll(ctx context.Context, user *model.User, param *parameters.DeadLetterListParam) ([]*messaging.DeadLetter, error)
+		GetByID(ctx context.Context, user *model.User, sequence uint64) (*messaging.DeadLetter, error)
+		Replay(ctx context.Context, user *model.User, sequence uint64) error
+		Delete(ctx context.Context, user *model.User, sequence uint64) error
+	}
+
+	// deadLetterBL implements DeadLetterBL on top of the messaging client.
+	deadLetterBL struct {
+		messenger messaging.Client
+	}
+)
+
+// GetAll returns up to param.Limit dead letters starting with param.From, the limit is 50 if it is not set.
+func (b *deadLetterBL) GetAll(ctx context.Context, user *model.User, param *parameters.DeadLetterListParam) ([]*messaging.DeadLetter, error) {
+	if !user.HasRoles(model.RoleSuperAdmin) {
+		return nil, utils.ErrorPermission.New("permission denied")
+	}
+	limit := param.Limit
+	if limit == 0 {
+		limit = deadLetterDefaultLimit
+	}
+	deadLetters, err := b.messenger.GetDeadLetters(ctx, param.Stream, param.From, limit)
+	if err != nil {
+		return nil, utils.Internal.Wrapf(err, "can not load dead letters [%s]", err.Error())
+	}
+	return deadLetters, nil
+}
+
+// GetByID returns the dead letter with the message parsed by the message type of its stream.
+func (b *deadLetterBL) GetByID(ctx context.Context, user *model.User, sequence uint64) (*messaging.DeadLetter, error) {
+	if !user.HasRoles(model.RoleSuperAdmin) {
+		return nil, utils.ErrorPermission.New("permission denied")
+	}
+	deadLetter, err := b.messenger.GetDeadLetter(ctx, sequence)
+	if err != nil {
+		return nil, err
+	}
+	var payload proto2.Message
+	switch deadLetter.Stream {
+	case b.messenger.StreamConfig().ConnectorStreamName:
+		payload = &proto.ConnectorRequest{}
+	case b.messenger.StreamConfig().SemanticStreamName:
+		payload = &proto.SemanticData{}
+	case b.messenger.StreamConfig().VoiceStreamName:
+		payload = &proto.VoiceData{}
+	}
+	// a message that can not be parsed is returned without payload
+	if payload != nil && proto2.Unmarshal(deadLetter.Data, payload) == nil {
+		deadLetter.Payload = payload
+	}
+	return deadLetter, nil
+}
+
+// Replay publishes the message of the dead letter again on its original subject.
+func (b *deadLetterBL) Replay(ctx context.Context, user *model.User, sequence uint64) error {
+	if !user.HasRoles(model.RoleSuperAdmin) {
+		return utils.ErrorPermission.New("permission denied")
+	}
+	return b.messenger.ReplayDeadLetter(ctx, sequence)
+}
+
+// Delete removes the dead letter.
+func (b *deadLetterBL) Delete(ctx context.Context, user *model.User, sequence uint64) error {
+	if !user.HasRoles(model.RoleSuperAdmin) {
+		return utils.ErrorPermission.New("permission denied")
+	}
+	return b.messenger.DeleteDeadLetter(ctx, sequence)
+}
+
+// NewDeadLetterBL creates a new instance of DeadLetterBL.
+func NewDeadLetterBL(messenger messaging.Client) DeadLetterBL {
+	return &deadLetterBL{messenger: messenger}
+}
@@ -1,13 +1,24 @@
 package logic
 
 import (
+	"bytes"
+	"cognix.ch/api/v2/core/messaging"
 	"cognix.ch/api/v2/core/model"
+	"cognix.ch/api/v2/core/parameters"
+	"cognix.ch/api/v2/core/proto"
 	"cognix.ch/api/v2/core/repository"
 	"cognix.ch/api/v2/core/storage"
+	"cognix.ch/api/v2/core/utils"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 	"io"
+	"mime"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -24,8 +35,15 @@ type (
 	// Returns:
 	// - *model.Document: The newly created Document object.
 	// - error: Any error that occurred during the upload process.
+	//
+	// IngestDocument creates or updates the document with the given source id in an ingestion API connector
+	// and sends it to the semantic service. The content is taken from the file if it is presented.
+	// DeleteIngestedDocument removes the document with the given source id from an ingestion API connector.
 	DocumentBL interface {
 		UploadDocument(ctx context.Context, user *model.User, fileName, contentType string, file io.Reader) (*model.Document, error)
+		IngestDocument(ctx context.Context, user *model.User, connectorID int64, param *parameters.IngestDocumentParam,
+			fileName, contentType string, file io.Reader) (*model.Document, error)
+		DeleteIngestedDocument(ctx context.Context, user *model.User, connectorID int64, sourceID string) (*model.Document, error)
 	}
 
 	// documentBL is a struct that represents a business logic layer for managing documents.
@@ -36,6 +54,9 @@ type (
 	// - documentRepo: The repository for managing documents.
 	// - minioClient: The MinIO client for uploading documents to storage.
 	// - connectorRepo: The repository for managing connectors.
+	// - embeddingModelRepo: The repository for reading the tenant's embedding model.
+	// - messenger: The client for sending documents to the semantic and voice services.
+	// - milvusClient: The client for removing embeddings of deleted documents.
 	//
 	// Example usage:
 	// db := repository.NewDocumentRepository()
@@ -50,6 +71,11 @@ type (
 		documentRepo  repository.DocumentRepository
 		minioClient   storage.FileStorageClient
 		connectorRepo repository.ConnectorRepository
+
+		cfg                *Config
+		embeddingModelRepo repository.EmbeddingModelRepository
+		messenger          messaging.Client
+		milvusClient       storage.VectorDBClient
 	}
 )
 
@@ -90,22 +116,226 @@ func (b *documentBL) UploadDocument(ctx context.Context, user *model.User, fileN
 	return document, nil
 }
 
+// IngestDocument creates or updates the document with the given source id in an ingestion API connector.
+// Text and markdown payloads are stored as a file, the title is rendered as the first line.
+// If the content did not change, only the original url and the metadata are updated,
+// otherwise the content is saved in MinIO and the document is sent to the semantic or voice service.
+func (b *documentBL) IngestDocument(ctx context.Context, user *model.User, connectorID int64, param *parameters.IngestDocumentParam,
+	fileName, contentType string, file io.Reader) (*model.Document, error) {
+	connector, err := b.getIngestionConnector(ctx, user, connectorID)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		if param.Content == "" {
+			return nil, utils.ErrorBadRequest.New("content or file should be presented")
+		}
+		content := param.Content
+		if param.Format == parameters.IngestFormatMarkdown {
+			fileName, contentType = "document.md", "text/markdown"
+			if param.Title != "" {
+				content = fmt.Sprintf("# %s\n\n%s", param.Title, content)
+			}
+		} else {
+			fileName, contentType = "document.txt", "text/plain"
+			if param.Title != "" {
+				content = fmt.Sprintf("%s\n\n%s", param.Title, content)
+			}
+		}
+		file = strings.NewReader(content)
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	}
+	fileType, ok := model.SupportedMimeTypes[contentType]
+	if !ok {
+		// http clients often send a generic content type, recognize the file by its extension then
+		contentType = model.SupportedExtensions[strings.ToUpper(strings.TrimPrefix(filepath.Ext(fileName), "."))]
+		fileType, ok = model.SupportedMimeTypes[contentType]
+	}
+	if !ok || fileType == proto.FileType_URL {
+		return nil, utils.ErrorBadRequest.Newf("unsupported file type %s", fileName)
+	}
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, utils.ErrorBadRequest.Wrap(err, "can not read content")
+	}
+	checksum := sha256.Sum256(content)
+	signature := hex.EncodeToString(checksum[:])
+
+	doc, err := b.documentRepo.FindBySourceID(ctx, connectorID, param.SourceID)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = &model.Document{
+			SourceID:    param.SourceID,
+			ConnectorID: connector.ID,
+		}
+	}
+	doc.OriginalURL = param.OriginalURL
+	doc.Metadata = param.Metadata
+	if doc.ID.IntPart() != 0 && doc.Signature == signature {
+		if err = b.documentRepo.Update(ctx, doc); err != nil {
+			return nil, err
+		}
+		return doc, nil
+	}
+
+	previousURL := doc.URL
+	bucket := model.BucketName(user.TenantID)
+	key, _, err := b.minioClient.Upload(ctx, bucket,
+		utils.StripFileName(connector.BuildFileName(fmt.Sprintf("%s-%s", uuid.New().String(), fileName))),
+		contentType, bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	doc.URL = fmt.Sprintf("minio:%s:%s", bucket, key)
+	doc.Signature = signature
+	if doc.ID.IntPart() != 0 {
+		err = b.documentRepo.Update(ctx, doc)
+	} else {
+		err = b.documentRepo.Create(ctx, doc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if previousURL != "" {
+		if err = b.deleteContent(ctx, previousURL); err != nil {
+			zap.S().Errorf("can not delete previous content of document %d: %s", doc.ID.IntPart(), err.Error())
+		}
+	}
+	if err = b.publish(ctx, user, connector, doc, fileType); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// DeleteIngestedDocument deletes the document with the given source id from an ingestion API connector
+// together with its embeddings in Milvus and its content in MinIO. The embeddings and the document are deleted first,
+// so the document is not found by the search anymore, a failure of deleting the content is only logged.
+func (b *documentBL) DeleteIngestedDocument(ctx context.Context, user *model.User, connectorID int64, sourceID string) (*model.Document, error) {
+	connector, err := b.getIngestionConnector(ctx, user, connectorID)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := b.documentRepo.FindBySourceID(ctx, connectorID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, utils.NotFound.New("document not found")
+	}
+	if err = b.milvusClient.Delete(ctx, connector.CollectionName(), doc.ID.IntPart()); err != nil {
+		return nil, err
+	}
+	if err = b.documentRepo.DeleteByIDS(ctx, doc.ID.IntPart()); err != nil {
+		return nil, err
+	}
+	if err = b.deleteContent(ctx, doc.URL); err != nil {
+		zap.S().Errorf("can not delete content of document %d: %s", doc.ID.IntPart(), err.Error())
+	}
+	return doc, nil
+}
+
+// getIngestionConnector loads the connector documents are pushed into.
+// Only the owner of the connector or an administrator can push documents
+// and the connector must be an active ingestion API connector.
+func (b *documentBL) getIngestionConnector(ctx context.Context, user *model.User, id int64) (*model.Connector, error) {
+	connector, err := b.connectorRepo.GetByIDAndUser(ctx, user.TenantID, user.ID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !(connector.UserID == user.ID || user.HasRoles(model.RoleAdmin, model.RoleSuperAdmin)) {
+		return nil, utils.ErrorPermission.New("permission denied")
+	}
+	if connector.Type != model.SourceTypeIngestionApi {
+		return nil, utils.ErrorBadRequest.New("connector is not an ingestion api connector")
+	}
+	if !connector.DeletedDate.IsZero() {
+		return nil, utils.ErrorBadRequest.New("connector is deleted")
+	}
+	return connector, nil
+}
+
+// publish sends the document to the voice service for audio and video files
+// and to the semantic service for all other files.
+func (b *documentBL) publish(ctx context.Context, user *model.User, connector *model.Connector,
+	doc *model.Document, fileType proto.FileType) error {
+	em, err := b.embeddingModelRepo.GetDefault(ctx, user.TenantID)
+	if err != nil {
+		zap.S().Errorf(err.Error())
+		em = &model.EmbeddingModel{
+			ModelID:  b.cfg.DefaultEmbeddingModel,
+			ModelDim: b.cfg.DefaultEmbeddingVectorSize,
+		}
+	}
+	if _, ok := model.VoiceFileTypes[fileType]; ok {
+		return b.messenger.Publish(ctx,
+			b.messenger.StreamConfig().VoiceStreamName,
+			b.messenger.StreamConfig().VoiceStreamSubject,
+			&proto.VoiceData{
+				Url:            doc.URL,
+				DocumentId:     doc.ID.IntPart(),
+				ConnectorId:    connector.ID.IntPart(),
+				FileType:       fileType,
+				CollectionName: connector.CollectionName(),
+				ModelName:      em.ModelID,
+				ModelDimension: int32(em.ModelDim),
+			})
+	}
+	return b.messenger.Publish(ctx,
+		b.messenger.StreamConfig().SemanticStreamName,
+		b.messenger.StreamConfig().SemanticStreamSubject,
+		&proto.SemanticData{
+			Url:            doc.URL,
+			DocumentId:     doc.ID.IntPart(),
+			ConnectorId:    connector.ID.IntPart(),
+			FileType:       fileType,
+			CollectionName: connector.CollectionName(),
+			ModelName:      em.ModelID,
+			ModelDimension: int32(em.ModelDim),
+		})
+}
+
+// deleteContent deletes the MinIO object the document url points to.
+func (b *documentBL) deleteContent(ctx context.Context, url string) error {
+	minioFile := strings.Split(url, ":")
+	if len(minioFile) == 3 && minioFile[0] == "minio" {
+		return b.minioClient.DeleteObject(ctx, minioFile[1], minioFile[2])
+	}
+	return nil
+}
+
 // NewDocumentBL is a function that creates a new instance of the DocumentBL interface.
 // The function takes the following parameters:
 // - documentRepo: The repository for managing documents.
 // - connectorRepo: The repository for managing connectors.
 // - minioClient: The MinIO client for uploading documents to storage.
+// - cfg: The configuration with the default embedding model.
+// - embeddingModelRepo: The repository for reading the tenant's embedding model.
+// - messenger: The client for sending documents to the semantic and voice services.
+// - milvusClient: The client for removing embeddings of deleted documents.
 // The function returns a new instance of the DocumentBL interface.
 // Example usage:
 // documentRepo := repository.NewDocumentRepository()
 // connectorRepo := repository.NewConnectorRepository()
 // minioClient := storage.NewMinIOClient()
-// documentBL := NewDocumentBL(documentRepo, connectorRepo, minioClient)
+// documentBL := NewDocumentBL(documentRepo, connectorRepo, minioClient, cfg, embeddingModelRepo, messenger, milvusClient)
 func NewDocumentBL(documentRepo repository.DocumentRepository,
 	connectorRepo repository.ConnectorRepository,
-	minioClient storage.FileStorageClient) DocumentBL {
+	minioClient storage.FileStorageClient,
+	cfg *Config,
+	embeddingModelRepo repository.EmbeddingModelRepository,
+	messenger messaging.Client,
+	milvusClient storage.VectorDBClient) DocumentBL {
 	return &documentBL{documentRepo: documentRepo,
-		connectorRepo: connectorRepo,
-		minioClient:   minioClient,
+		connectorRepo:      connectorRepo,
+		minioClient:        minioClient,
+		cfg:                cfg,
+		embeddingModelRepo: embeddingModelRepo,
+		messenger:          messenger,
+		milvusClient:       milvusClient,
 	}
 }
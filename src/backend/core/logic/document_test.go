@@ -0,0 +1,299 @@
+package logic
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"cognix.ch/api/v2/core/parameters"
+	"cognix.ch/api/v2/core/repository"
+	"cognix.ch/api/v2/core/storage"
+	"cognix.ch/api/v2/core/utils"
+	"cognix.ch/api/v2/orchestrator/mocks"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+type documentRepoStub struct {
+	repository.DocumentRepository
+	events *[]string
+	docs   map[string]*model.Document
+}
+
+func (r *documentRepoStub) FindBySourceID(ctx context.Context, connectorID int64, sourceID string) (*model.Document, error) {
+	return r.docs[sourceID], nil
+}
+
+func (r *documentRepoStub) Create(ctx context.Context, document *model.Document) error {
+	*r.events = append(*r.events, "create document")
+	return r.DocumentRepository.Create(ctx, document)
+}
+
+func (r *documentRepoStub) Update(ctx context.Context, document *model.Document) error {
+	*r.events = append(*r.events, "update document")
+	return nil
+}
+
+func (r *documentRepoStub) DeleteByIDS(ctx context.Context, ids ...int64) error {
+	*r.events = append(*r.events, "delete document")
+	return nil
+}
+
+type fileStorageStub struct {
+	storage.FileStorageClient
+	events      *[]string
+	contentType string
+	deleteErr   error
+}
+
+func (s *fileStorageStub) Upload(ctx context.Context, bucket, filename, contentType string, reader io.Reader) (string, string, error) {
+	*s.events = append(*s.events, "upload content")
+	s.contentType = contentType
+	return filename, "", nil
+}
+
+func (s *fileStorageStub) DeleteObject(ctx context.Context, bucket, filename string) error {
+	*s.events = append(*s.events, "delete content "+filename)
+	return s.deleteErr
+}
+
+type vectorDBStub struct {
+	storage.VectorDBClient
+	events *[]string
+}
+
+func (v *vectorDBStub) Delete(ctx context.Context, collection string, documentID ...int64) error {
+	*v.events = append(*v.events, "delete vectors")
+	return nil
+}
+
+type embeddingModelRepoStub struct {
+	repository.EmbeddingModelRepository
+}
+
+func (r *embeddingModelRepoStub) GetDefault(ctx context.Context, tenantID uuid.UUID) (*model.EmbeddingModel, error) {
+	return &model.EmbeddingModel{ModelID: "model", ModelDim: 3}, nil
+}
+
+type documentTest struct {
+	bl        DocumentBL
+	events    []string
+	docs      map[string]*model.Document
+	storage   *fileStorageStub
+	messenger *mocks.MockMessenger
+}
+
+// newDocumentTest creates the business logic of the ingestion API connector with the id 1 of the user.
+func newDocumentTest(user *model.User) *documentTest {
+	test := &documentTest{
+		events:    make([]string, 0),
+		docs:      make(map[string]*model.Document),
+		messenger: mocks.NewMockMessenger(nil).(*mocks.MockMessenger),
+	}
+	test.storage = &fileStorageStub{events: &test.events}
+	connectorRepo := mocks.NewMockConnectorRepoWithConnectors(map[int64]*model.Connector{
+		1: {ID: decimal.NewFromInt(1), Type: model.SourceTypeIngestionApi, UserID: user.ID, TenantID: uuid.NullUUID{user.TenantID, true}},
+		2: {ID: decimal.NewFromInt(2), Type: model.SourceTypeWEB, UserID: user.ID},
+		3: {ID: decimal.NewFromInt(3), Type: model.SourceTypeIngestionApi, UserID: uuid.New()},
+		4: {ID: decimal.NewFromInt(4), Type: model.SourceTypeIngestionApi, UserID: user.ID, DeletedDate: pg.NullTime{time.Now().UTC()}},
+	})
+	test.bl = NewDocumentBL(
+		&documentRepoStub{DocumentRepository: mocks.NewMockDocumentRepo(), events: &test.events, docs: test.docs},
+		connectorRepo,
+		test.storage,
+		&Config{},
+		&embeddingModelRepoStub{},
+		test.messenger,
+		&vectorDBStub{events: &test.events},
+	)
+	return test
+}
+
+func errorCode(err error) utils.ErrorWrap {
+	var wrapped utils.Errors
+	if errors.As(err, &wrapped) {
+		return wrapped.Code
+	}
+	return 0
+}
+
+func TestDocumentBL_IngestDocumentFileType(t *testing.T) {
+	user := &model.User{ID: uuid.New(), TenantID: uuid.New()}
+	tests := []struct {
+		name        string
+		param       *parameters.IngestDocumentParam
+		fileName    string
+		contentType string
+		file        io.Reader
+		want        string
+		stream      string
+	}{
+		{
+			name:   "text content",
+			param:  &parameters.IngestDocumentParam{Content: "text", Format: parameters.IngestFormatText},
+			want:   "text/plain",
+			stream: "semantic",
+		},
+		{
+			name:   "markdown content",
+			param:  &parameters.IngestDocumentParam{Content: "text", Title: "title", Format: parameters.IngestFormatMarkdown},
+			want:   "text/markdown",
+			stream: "semantic",
+		},
+		{
+			name:        "content type with parameters",
+			param:       &parameters.IngestDocumentParam{},
+			fileName:    "notes",
+			contentType: "text/plain; charset=utf-8",
+			file:        strings.NewReader("text"),
+			want:        "text/plain",
+			stream:      "semantic",
+		},
+		{
+			name:        "generic content type resolved by extension",
+			param:       &parameters.IngestDocumentParam{},
+			fileName:    "report.pdf",
+			contentType: "application/octet-stream",
+			file:        strings.NewReader("pdf"),
+			want:        "application/pdf",
+			stream:      "semantic",
+		},
+		{
+			name:        "video is sent to voice service",
+			param:       &parameters.IngestDocumentParam{},
+			fileName:    "meeting.mp4",
+			contentType: "video/mp4",
+			file:        strings.NewReader("video"),
+			want:        "video/mp4",
+			stream:      "voice",
+		},
+		{
+			name:        "unsupported file",
+			param:       &parameters.IngestDocumentParam{},
+			fileName:    "archive.zip",
+			contentType: "application/zip",
+			file:        strings.NewReader("zip"),
+		},
+		{
+			name:  "without content",
+			param: &parameters.IngestDocumentParam{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := newDocumentTest(user)
+			tt.param.SourceID = "doc-1"
+			doc, err := test.bl.IngestDocument(context.Background(), user, 1, tt.param, tt.fileName, tt.contentType, tt.file)
+			if tt.want == "" {
+				assert.Equal(t, utils.ErrorBadRequest, errorCode(err))
+				assert.Nil(t, doc)
+				assert.Empty(t, test.events)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, test.storage.contentType)
+			assert.Equal(t, []string{"upload content", "create document"}, test.events)
+			assert.Equal(t, []int64{1}, test.messenger.Published(tt.stream))
+		})
+	}
+}
+
+func TestDocumentBL_IngestDocumentSignature(t *testing.T) {
+	user := &model.User{ID: uuid.New(), TenantID: uuid.New()}
+	checksum := sha256.Sum256([]byte("text"))
+	signature := hex.EncodeToString(checksum[:])
+
+	// the content did not change, only the metadata is updated
+	test := newDocumentTest(user)
+	test.docs["doc-1"] = &model.Document{ID: decimal.NewFromInt(5), SourceID: "doc-1", Signature: signature, URL: "minio:bucket:old.txt"}
+	doc, err := test.bl.IngestDocument(context.Background(), user, 1, &parameters.IngestDocumentParam{
+		SourceID:    "doc-1",
+		Content:     "text",
+		OriginalURL: "https://doc-1",
+	}, "", "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://doc-1", doc.OriginalURL)
+	assert.Equal(t, "minio:bucket:old.txt", doc.URL)
+	assert.Equal(t, []string{"update document"}, test.events)
+	assert.Empty(t, test.messenger.Published("semantic"))
+
+	// the content changed, the previous content is replaced
+	test = newDocumentTest(user)
+	test.docs["doc-1"] = &model.Document{ID: decimal.NewFromInt(5), SourceID: "doc-1", Signature: "previous", URL: "minio:bucket:old.txt"}
+	doc, err = test.bl.IngestDocument(context.Background(), user, 1, &parameters.IngestDocumentParam{
+		SourceID: "doc-1",
+		Content:  "text",
+	}, "", "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, signature, doc.Signature)
+	assert.NotEqual(t, "minio:bucket:old.txt", doc.URL)
+	assert.Equal(t, []string{"upload content", "update document", "delete content old.txt"}, test.events)
+	assert.Equal(t, []int64{1}, test.messenger.Published("semantic"))
+}
+
+func TestDocumentBL_IngestionConnector(t *testing.T) {
+	user := &model.User{ID: uuid.New(), TenantID: uuid.New()}
+	admin := &model.User{ID: uuid.New(), TenantID: user.TenantID, Roles: model.StringSlice{model.RoleAdmin}}
+	tests := []struct {
+		name        string
+		user        *model.User
+		connectorID int64
+		code        utils.ErrorWrap
+	}{
+		{name: "owner", user: user, connectorID: 1},
+		{name: "admin", user: admin, connectorID: 3},
+		{name: "not found", user: user, connectorID: 10, code: utils.NotFound},
+		{name: "other type", user: user, connectorID: 2, code: utils.ErrorBadRequest},
+		{name: "other user", user: user, connectorID: 3, code: utils.ErrorPermission},
+		{name: "deleted", user: user, connectorID: 4, code: utils.ErrorBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := newDocumentTest(user)
+			_, err := test.bl.IngestDocument(context.Background(), tt.user, tt.connectorID, &parameters.IngestDocumentParam{
+				SourceID: "doc-1",
+				Content:  "text",
+			}, "", "", nil)
+			if tt.code == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tt.code, errorCode(err))
+			assert.Empty(t, test.events)
+
+			_, err = test.bl.DeleteIngestedDocument(context.Background(), tt.user, tt.connectorID, "doc-1")
+			assert.Equal(t, tt.code, errorCode(err))
+			assert.Empty(t, test.events)
+		})
+	}
+}
+
+func TestDocumentBL_DeleteIngestedDocument(t *testing.T) {
+	user := &model.User{ID: uuid.New(), TenantID: uuid.New()}
+	for _, deleteErr := range []error{nil, errors.New("minio is not available")} {
+		t.Run(fmt.Sprintf("content deleted with %v", deleteErr), func(t *testing.T) {
+			test := newDocumentTest(user)
+			test.storage.deleteErr = deleteErr
+			test.docs["doc-1"] = &model.Document{ID: decimal.NewFromInt(5), SourceID: "doc-1", URL: "minio:bucket:doc-1.txt"}
+
+			doc, err := test.bl.DeleteIngestedDocument(context.Background(), user, 1, "doc-1")
+			// the content is deleted last and its failure does not fail the request
+			assert.NoError(t, err)
+			assert.Equal(t, int64(5), doc.ID.IntPart())
+			assert.Equal(t, []string{"delete vectors", "delete document", "delete content doc-1.txt"}, test.events)
+		})
+	}
+
+	test := newDocumentTest(user)
+	_, err := test.bl.DeleteIngestedDocument(context.Background(), user, 1, "unknown")
+	assert.Equal(t, utils.NotFound, errorCode(err))
+	assert.Empty(t, test.events)
+}
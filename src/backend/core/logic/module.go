@@ -1,6 +1,7 @@
 package logic
 
 import (
+	"cognix.ch/api/v2/core/responder"
 	"cognix.ch/api/v2/core/utils"
 	"go.uber.org/fx"
 )
@@ -14,15 +15,22 @@ import (
 //     It is tagged with `env:"DEFAULT_EMBEDDING_MODEL"` and has a default value of "paraphrase-multilingual-mpnet-base-v2".
 //   - DefaultEmbeddingVectorSize: An integer representing the default embedding vector size.
 //     It is tagged with `env:"DEFAULT_EMBEDDING_VECTOR_SIZE"` and has a default value of 768.
+//   - FileSizeLimit:              An integer representing the size limit of files in GB for connector runs started by users.
+//     It is tagged with `env:"FILE_SIZE_LIMIT"` and has a default value of 1.
+//   - Responder:                  A pointer to the responder.Config with settings of the chat responders.
 type Config struct {
 	RedirectURL                string `env:"REDIRECT_URL"`
 	DefaultEmbeddingModel      string `env:"DEFAULT_EMBEDDING_MODEL" envDefault:"paraphrase-multilingual-mpnet-base-v2"`
 	DefaultEmbeddingVectorSize int    `env:"DEFAULT_EMBEDDING_VECTOR_SIZE" envDefault:"768"`
+	FileSizeLimit              int    `env:"FILE_SIZE_LIMIT" envDefault:"1"`
+	Responder                  *responder.Config
 }
 
 var BLLModule = fx.Options(
 	fx.Provide(func() (*Config, error) {
-		cfg := Config{}
+		cfg := Config{
+			Responder: &responder.Config{},
+		}
 		err := utils.ReadConfig(&cfg)
 		return &cfg, err
 	}),
@@ -34,5 +42,6 @@ var BLLModule = fx.Options(
 		NewDocumentBL,
 		NewEmbeddingModelBL,
 		NewTenantBL,
+		NewDeadLetterBL,
 	),
 )
@@ -83,21 +83,31 @@ func (b *personaBL) Create(ctx context.Context, user *model.User, param *paramet
 		return nil, utils.ErrorBadRequest.Wrap(err, "fail to marshal starter messages")
 	}
 	persona := model.Persona{
-		Name:            param.Name,
-		DefaultPersona:  true,
-		Description:     param.Description,
-		TenantID:        user.TenantID,
-		IsVisible:       true,
-		StarterMessages: starterMessages,
-		CreationDate:    time.Now().UTC(),
+		Name:                 param.Name,
+		DefaultPersona:       true,
+		Description:          param.Description,
+		TenantID:             user.TenantID,
+		IsVisible:            true,
+		StarterMessages:      starterMessages,
+		CreationDate:         time.Now().UTC(),
+		SearchTopK:           param.SearchTopK,
+		SearchScoreThreshold: param.SearchScoreThreshold,
+		ConnectorIDs:         param.ConnectorIDs,
+		SourceTypes:          param.SourceTypes,
 		LLM: &model.LLM{
-			Name:         fmt.Sprintf("%s %s", user.FirstName, param.ModelID),
-			ModelID:      param.ModelID,
-			TenantID:     user.TenantID,
-			CreationDate: time.Now().UTC(),
-			Url:          param.URL,
-			ApiKey:       param.APIKey,
-			Endpoint:     param.Endpoint,
+			Name:           fmt.Sprintf("%s %s", user.FirstName, param.ModelID),
+			ModelID:        param.ModelID,
+			TenantID:       user.TenantID,
+			CreationDate:   time.Now().UTC(),
+			Url:            param.URL,
+			ApiKey:         param.APIKey,
+			Endpoint:       param.Endpoint,
+			ContextSize:    param.ContextSize,
+			Temperature:    param.Temperature,
+			TopP:           param.TopP,
+			MaxTokens:      param.MaxTokens,
+			Stop:           param.Stop,
+			ResponseFormat: param.ResponseFormat,
 		},
 		Prompt: &model.Prompt{
 			UserID:       user.ID,
@@ -119,8 +129,8 @@ func (b *personaBL) Create(ctx context.Context, user *model.User, param *paramet
 // It retrieves the persona from the persona repository by ID and tenant ID.
 // If an error occurs while retrieving the persona, it returns the error.
 // It marshals the starter messages from the parameter into JSON.
-// It updates the persona's name, description, last update time, and starter messages with the values from the parameter.
-// It updates the persona's LLM endpoint and model ID with the values from the parameter.
+// It updates the persona's name, description, last update time, starter messages, search settings and retrieval scope with the values from the parameter.
+// It updates the persona's LLM endpoint, model ID, context size and generation settings with the values from the parameter.
 // If the persona's LLM.ApiKey is different from the parameter API key, it updates the persona's LLM API key with the parameter API key.
 // It also updates the persona's LLM last update time.
 // It updates the persona's prompt name, description, system prompt,
@@ -137,8 +147,18 @@ func (b *personaBL) Update(ctx context.Context, id int64, user *model.User, para
 	persona.Description = param.Description
 	persona.LastUpdate = pg.NullTime{time.Now().UTC()}
 	persona.StarterMessages = starterMessages
+	persona.SearchTopK = param.SearchTopK
+	persona.SearchScoreThreshold = param.SearchScoreThreshold
+	persona.ConnectorIDs = param.ConnectorIDs
+	persona.SourceTypes = param.SourceTypes
 	persona.LLM.Endpoint = param.Endpoint
 	persona.LLM.ModelID = param.ModelID
+	persona.LLM.ContextSize = param.ContextSize
+	persona.LLM.Temperature = param.Temperature
+	persona.LLM.TopP = param.TopP
+	persona.LLM.MaxTokens = param.MaxTokens
+	persona.LLM.Stop = param.Stop
+	persona.LLM.ResponseFormat = param.ResponseFormat
 	// update api key if user updates it.
 	if persona.LLM.MaskApiKey() != param.APIKey {
 		persona.LLM.ApiKey = param.APIKey
@@ -0,0 +1,223 @@
+package messaging
+
+import (
+	"cognix.ch/api/v2/core/utils"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"strconv"
+	"time"
+)
+
+// headers of dead letter messages
+const (
+	headerDeadLetterStream     = "Dead-Letter-Stream"
+	headerDeadLetterSubject    = "Dead-Letter-Subject"
+	headerDeadLetterError      = "Dead-Letter-Error"
+	headerDeadLetterDeliveries = "Dead-Letter-Deliveries"
+)
+
+type (
+	// PermanentError is an error of a message handler that is not fixed by delivering the message again,
+	// like a message that can not be parsed. The message is moved to the dead letter stream at once.
+	PermanentError struct {
+		Err error
+	}
+
+	// DeadLetter is a message that failed in its stream and was moved to the dead letter stream.
+	//
+	// Fields:
+	// - Sequence: the sequence of the message in the dead letter stream.
+	// - Stream: the name of the stream the message failed in.
+	// - Subject: the subject the message was published on, the message is replayed on it.
+	// - Error: the error of the last attempt.
+	// - Deliveries: the number of attempts.
+	// - FailedAt: the time the message was moved to the dead letter stream.
+	// - Data: the message.
+	// - Payload: the parsed message, set by the caller who knows the message type of the stream.
+	DeadLetter struct {
+		Sequence   uint64      `json:"sequence"`
+		Stream     string      `json:"stream"`
+		Subject    string      `json:"subject"`
+		Error      string      `json:"error"`
+		Deliveries int         `json:"deliveries"`
+		FailedAt   time.Time   `json:"failed_at"`
+		Data       []byte      `json:"-"`
+		Payload    interface{} `json:"payload,omitempty"`
+	}
+)
+
+// Permanent marks the error of a message handler as permanent.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// IsPermanent reports whether the error was marked as permanent. Not found and validation errors
+// of the repositories are permanent as well, like a connector that was deleted or has an invalid configuration.
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	if errors.As(err, &permanent) {
+		return true
+	}
+	var wrapped utils.Errors
+	if errors.As(err, &wrapped) {
+		return wrapped.Code == utils.NotFound || wrapped.Code == utils.ErrorBadRequest
+	}
+	return false
+}
+
+// retryDelay returns the delay before the next delivery after the given number of deliveries.
+// The delay starts with RetryDelay and is doubled with every delivery up to RetryMaxDelay.
+func retryDelay(cfg *StreamConfig, deliveries uint64) time.Duration {
+	delay := time.Duration(max(cfg.RetryDelay, 1)) * time.Second
+	maxDelay := time.Duration(max(cfg.RetryMaxDelay, cfg.RetryDelay, 1)) * time.Second
+	for i := uint64(1); i < deliveries && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+// handleFailure redelivers the failed message with backoff. Messages that failed with a permanent
+// error or for the MaxDeliver time are moved to the dead letter stream.
+func (c *clientStream) handleFailure(ctx context.Context, streamName string, msg jetstream.Msg, handlerErr error) error {
+	deliveries := uint64(1)
+	if metadata, err := msg.Metadata(); err == nil {
+		deliveries = metadata.NumDelivered
+	}
+	if !IsPermanent(handlerErr) && (c.streamCfg.MaxDeliver <= 0 || deliveries < uint64(c.streamCfg.MaxDeliver)) {
+		return msg.NakWithDelay(retryDelay(c.streamCfg, deliveries))
+	}
+	if err := c.deadLetter(ctx, streamName, msg, handlerErr, deliveries); err != nil {
+		// the message is not acknowledged and stays in the stream
+		return fmt.Errorf("move message to dead letter stream: %w", err)
+	}
+	return msg.Term()
+}
+
+// deadLetter publishes the failed message with the reason of the failure to the dead letter stream.
+func (c *clientStream) deadLetter(ctx context.Context, streamName string, msg jetstream.Msg, handlerErr error, deliveries uint64) error {
+	if _, err := c.deadLetterStream(ctx); err != nil {
+		return err
+	}
+	deadLetter := nats.NewMsg(c.deadLetterSubject(streamName))
+	deadLetter.Data = msg.Data()
+	deadLetter.Header.Set(headerDeadLetterStream, streamName)
+	deadLetter.Header.Set(headerDeadLetterSubject, msg.Subject())
+	deadLetter.Header.Set(headerDeadLetterError, handlerErr.Error())
+	deadLetter.Header.Set(headerDeadLetterDeliveries, strconv.FormatUint(deliveries, 10))
+	_, err := c.js.PublishMsg(ctx, deadLetter)
+	return err
+}
+
+// GetDeadLetters returns up to limit dead letters of the stream starting with the sequence.
+// If the stream name is empty, dead letters of all streams are returned.
+func (c *clientStream) GetDeadLetters(ctx context.Context, streamName string, fromSequence uint64, limit int) ([]*DeadLetter, error) {
+	stream, err := c.deadLetterStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subject := c.deadLetterSubject(">")
+	if streamName != "" {
+		subject = c.deadLetterSubject(streamName)
+	}
+	result := make([]*DeadLetter, 0)
+	for sequence := max(fromSequence, 1); len(result) < limit; {
+		msg, err := stream.GetMsg(ctx, sequence, jetstream.WithGetMsgSubject(subject))
+		if errors.Is(err, jetstream.ErrMsgNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, newDeadLetter(msg))
+		sequence = msg.Sequence + 1
+	}
+	return result, nil
+}
+
+// GetDeadLetter returns the dead letter with the sequence.
+func (c *clientStream) GetDeadLetter(ctx context.Context, sequence uint64) (*DeadLetter, error) {
+	stream, err := c.deadLetterStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := stream.GetMsg(ctx, sequence)
+	if errors.Is(err, jetstream.ErrMsgNotFound) {
+		return nil, utils.NotFound.Wrap(err, "dead letter not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newDeadLetter(msg), nil
+}
+
+// ReplayDeadLetter publishes the message of the dead letter again on its original subject
+// and removes it from the dead letter stream.
+func (c *clientStream) ReplayDeadLetter(ctx context.Context, sequence uint64) error {
+	deadLetter, err := c.GetDeadLetter(ctx, sequence)
+	if err != nil {
+		return err
+	}
+	if _, err = c.js.Publish(ctx, deadLetter.Subject, deadLetter.Data); err != nil {
+		return err
+	}
+	return c.DeleteDeadLetter(ctx, sequence)
+}
+
+// DeleteDeadLetter removes the dead letter from the dead letter stream.
+func (c *clientStream) DeleteDeadLetter(ctx context.Context, sequence uint64) error {
+	stream, err := c.deadLetterStream(ctx)
+	if err != nil {
+		return err
+	}
+	if err = stream.DeleteMsg(ctx, sequence); err != nil {
+		if errors.Is(err, jetstream.ErrMsgNotFound) {
+			return utils.NotFound.Wrap(err, "dead letter not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// deadLetterStream creates or updates the dead letter stream. Unlike the work queues
+// it keeps messages until they are replayed, deleted or expire.
+func (c *clientStream) deadLetterStream(ctx context.Context) (jetstream.Stream, error) {
+	return c.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:      c.streamCfg.DeadLetterStreamName,
+		Retention: jetstream.LimitsPolicy,
+		Subjects:  []string{c.deadLetterSubject(">")},
+		MaxAge:    time.Duration(c.streamCfg.DeadLetterMaxAge) * 24 * time.Hour,
+	})
+}
+
+// deadLetterSubject returns the subject of dead letters of the stream.
+func (c *clientStream) deadLetterSubject(streamName string) string {
+	return fmt.Sprintf("%s.%s", c.streamCfg.DeadLetterStreamName, streamName)
+}
+
+// newDeadLetter reads the dead letter from the message of the dead letter stream.
+func newDeadLetter(msg *jetstream.RawStreamMsg) *DeadLetter {
+	deliveries, _ := strconv.Atoi(msg.Header.Get(headerDeadLetterDeliveries))
+	return &DeadLetter{
+		Sequence:   msg.Sequence,
+		Stream:     msg.Header.Get(headerDeadLetterStream),
+		Subject:    msg.Header.Get(headerDeadLetterSubject),
+		Error:      msg.Header.Get(headerDeadLetterError),
+		Deliveries: deliveries,
+		FailedAt:   msg.Time,
+		Data:       msg.Data,
+	}
+}
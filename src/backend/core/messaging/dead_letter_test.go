@@ -0,0 +1,148 @@
+package messaging
+
+import (
+	"cognix.ch/api/v2/core/utils"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type msgStub struct {
+	jetstream.Msg
+	deliveries uint64
+	nakDelay   time.Duration
+	terminated bool
+}
+
+func (m *msgStub) Metadata() (*jetstream.MsgMetadata, error) {
+	return &jetstream.MsgMetadata{NumDelivered: m.deliveries}, nil
+}
+
+func (m *msgStub) NakWithDelay(delay time.Duration) error {
+	m.nakDelay = delay
+	return nil
+}
+
+func (m *msgStub) Term() error {
+	m.terminated = true
+	return nil
+}
+
+func (m *msgStub) Data() []byte {
+	return []byte("data")
+}
+
+func (m *msgStub) Subject() string {
+	return "connector.executor"
+}
+
+type jetStreamStub struct {
+	jetstream.JetStream
+	published []*nats.Msg
+}
+
+func (j *jetStreamStub) CreateOrUpdateStream(ctx context.Context, cfg jetstream.StreamConfig) (jetstream.Stream, error) {
+	return nil, nil
+}
+
+func (j *jetStreamStub) PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	j.published = append(j.published, msg)
+	return &jetstream.PubAck{}, nil
+}
+
+func TestRetryDelay(t *testing.T) {
+	cfg := &StreamConfig{RetryDelay: 5, RetryMaxDelay: 60}
+	tests := []struct {
+		deliveries uint64
+		want       time.Duration
+	}{
+		{deliveries: 0, want: 5 * time.Second},
+		{deliveries: 1, want: 5 * time.Second},
+		{deliveries: 2, want: 10 * time.Second},
+		{deliveries: 3, want: 20 * time.Second},
+		{deliveries: 4, want: 40 * time.Second},
+		// the delay is capped by RetryMaxDelay
+		{deliveries: 5, want: 60 * time.Second},
+		{deliveries: 100, want: 60 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("deliveries %d", tt.deliveries), func(t *testing.T) {
+			assert.Equal(t, tt.want, retryDelay(cfg, tt.deliveries))
+		})
+	}
+
+	// a maximum below the first delay does not shorten the first delay
+	assert.Equal(t, 5*time.Second, retryDelay(&StreamConfig{RetryDelay: 5, RetryMaxDelay: 1}, 3))
+	// delays are at least one second
+	assert.Equal(t, time.Second, retryDelay(&StreamConfig{}, 3))
+}
+
+func TestIsPermanent(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "marked", err: Permanent(errors.New("broken message")), want: true},
+		{name: "marked and wrapped", err: fmt.Errorf("run: %w", Permanent(errors.New("broken message"))), want: true},
+		{name: "not found", err: utils.NotFound.New("can not load connector"), want: true},
+		{name: "not found wrapped", err: fmt.Errorf("run: %w", utils.NotFound.Wrap(errors.New("no rows"), "can not load connector")), want: true},
+		{name: "validation", err: utils.ErrorBadRequest.New("invalid config"), want: true},
+		{name: "internal", err: utils.Internal.Wrap(errors.New("connection refused"), "can not load connector"), want: false},
+		{name: "conflict", err: utils.ErrorConflict.New("connector is running"), want: false},
+		{name: "plain", err: errors.New("timeout"), want: false},
+		{name: "nil", err: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsPermanent(tt.err))
+		})
+	}
+}
+
+func TestHandleFailure(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		deliveries uint64
+		deadLetter bool
+		nakDelay   time.Duration
+	}{
+		{name: "transient is redelivered", err: errors.New("timeout"), deliveries: 2, nakDelay: 2 * time.Second},
+		{name: "transient at max deliver", err: errors.New("timeout"), deliveries: 3, deadLetter: true},
+		{name: "permanent at first delivery", err: Permanent(errors.New("broken message")), deliveries: 1, deadLetter: true},
+		{name: "not found at first delivery", err: utils.NotFound.New("can not load connector"), deliveries: 1, deadLetter: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			js := &jetStreamStub{}
+			client := &clientStream{
+				js: js,
+				streamCfg: &StreamConfig{
+					MaxDeliver:           3,
+					RetryDelay:           1,
+					RetryMaxDelay:        10,
+					DeadLetterStreamName: "dead_letter",
+				},
+			}
+			msg := &msgStub{deliveries: tt.deliveries}
+			assert.NoError(t, client.handleFailure(context.Background(), "connector", msg, tt.err))
+			assert.Equal(t, tt.deadLetter, msg.terminated)
+			assert.Equal(t, tt.nakDelay, msg.nakDelay)
+			if !tt.deadLetter {
+				assert.Empty(t, js.published)
+				return
+			}
+			if assert.Len(t, js.published, 1) {
+				assert.Equal(t, "dead_letter.connector", js.published[0].Subject)
+				assert.Equal(t, tt.err.Error(), js.published[0].Header.Get(headerDeadLetterError))
+				assert.Equal(t, fmt.Sprint(tt.deliveries), js.published[0].Header.Get(headerDeadLetterDeliveries))
+			}
+		})
+	}
+}
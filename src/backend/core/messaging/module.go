@@ -36,18 +36,32 @@ type (
 		VoiceStreamSubject     string `env:"NATS_CLIENT_VOICE_STREAM_SUBJECT,required"`
 		AckWait                int    `env:"NATS_CLIENT_CONNECTOR_ACK_WAIT,required"`
 		MaxDeliver             int    `env:"NATS_CLIENT_CONNECTOR_MAX_DELIVER,required"`
+		// RetryDelay is the delay in seconds before the first redelivery of a failed message,
+		// it is doubled for every further attempt up to RetryMaxDelay.
+		RetryDelay    int `env:"NATS_CLIENT_RETRY_DELAY" envDefault:"5"`
+		RetryMaxDelay int `env:"NATS_CLIENT_RETRY_MAX_DELAY" envDefault:"300"`
+		// DeadLetterStreamName is the stream that keeps messages which failed MaxDeliver times
+		// for DeadLetterMaxAge days on the subject <DeadLetterStreamName>.<stream name>.
+		DeadLetterStreamName string `env:"NATS_CLIENT_DEAD_LETTER_STREAM_NAME" envDefault:"dead_letter"`
+		DeadLetterMaxAge     int    `env:"NATS_CLIENT_DEAD_LETTER_MAX_AGE" envDefault:"30"`
 	}
 
 	// MessageHandler represents a function type
 	MessageHandler func(ctx context.Context, msg jetstream.Msg) error
 
 	// Client is an interface that defines the methods for interacting with a messaging client.
+	// Messages whose handler failed MaxDeliver times or with a permanent error are moved
+	// to the dead letter stream, where they can be listed, replayed or deleted.
 	Client interface {
 		Publish(ctx context.Context, streamName, topic string, body proto2.Message) error
 		Listen(ctx context.Context, streamName, topic string, handler MessageHandler) error
 		StreamConfig() *StreamConfig
 		IsOnline() bool
 		Close()
+		GetDeadLetters(ctx context.Context, streamName string, fromSequence uint64, limit int) ([]*DeadLetter, error)
+		GetDeadLetter(ctx context.Context, sequence uint64) (*DeadLetter, error)
+		ReplayDeadLetter(ctx context.Context, sequence uint64) error
+		DeleteDeadLetter(ctx context.Context, sequence uint64) error
 	}
 )
 
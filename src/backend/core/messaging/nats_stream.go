@@ -99,6 +99,11 @@ func (c *clientStream) Listen(ctx context.Context, streamName, topic string, han
 		msg.InProgress()
 		if err := handler(ctx, msg); err != nil {
 			zap.S().Errorf("Error handling message: %s", err.Error())
+			// failed messages are delivered again with backoff or moved to the dead letter stream
+			if err = c.handleFailure(ctx, streamName, msg, err); err != nil {
+				zap.S().Errorf("Error handling failed message: %s", err.Error())
+			}
+			return
 		}
 		err := msg.Ack()
 		if err != nil {
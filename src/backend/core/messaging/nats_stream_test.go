@@ -1,29 +1,35 @@
 package messaging
 
 import (
-	"cognix.ch/api/v2/core/model"
-	"cognix.ch/api/v2/core/proto"
 	"context"
+	"github.com/nats-io/nats.go/jetstream"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
 )
 
 func TestClientStream_Listen(t *testing.T) {
-	cfg := &natsConfig{
-		URL:                 "localhost:4222",
-		ConnectorStreamName: "test-1",
+	cfg := &Config{
+		Nats: &natsConfig{
+			URL: "localhost:4222",
+		},
+		Stream: &StreamConfig{
+			ConnectorStreamName:    "test-1",
+			ConnectorStreamSubject: "test-1.executor",
+		},
 	}
 	stClient, err := NewClientStream(cfg)
-	assert.Nil(t, err)
+	if !assert.Nil(t, err) {
+		return
+	}
 
 	go func() {
 		time.Sleep(time.Second * time.Duration(30))
 		t.Error("close client stream")
 		stClient.Close()
 	}()
-	stClient.Listen(context.Background(), model.TopicExecutor, model.SubscriptionExecutor, func(ctx context.Context, msg *proto.Message) error {
-		t.Log(msg.Body.String())
+	stClient.Listen(context.Background(), cfg.Stream.ConnectorStreamName, cfg.Stream.ConnectorStreamSubject, func(ctx context.Context, msg jetstream.Msg) error {
+		t.Log(string(msg.Data()))
 		return nil
 	})
 }
@@ -44,6 +44,7 @@ type (
 		RephrasedQuery     string                     `json:"rephrased_query,omitempty" pg:",use_zero"`
 		Citations          []*DocumentResponse        `json:"citations,omitempty" pg:"-"`
 		Error              string                     `json:"error,omitempty" pg:",use_zero"`
+		InvalidCitations   bool                       `json:"invalid_citations,omitempty" pg:",use_zero"`
 		Feedback           *ChatMessageFeedback       `json:"feedback,omitempty" pg:"rel:has-one,fk:id,join_fk:chat_message_id"`
 		ParentMessage      *ChatMessage               `json:"-" pg:"-"`
 		DocumentPairs      []*ChatMessageDocumentPair `json:"-" pg:"rel:has-many,fk:chat_message_id,join_fk:chat_message_id"`
@@ -60,12 +61,15 @@ type (
 	}
 	// ChatMessageDocumentPair struct represent data from table chat_message_document_pairs
 	// what documents were found for each message.
+	// CitationNumber is the number of the document in the prompt, Cited shows whether the answer refers to it.
 	ChatMessageDocumentPair struct {
-		tableName     struct{}        `pg:"chat_message_document_pairs"`
-		ID            decimal.Decimal `json:"id"`
-		ChatMessageID decimal.Decimal `json:"chat_message_id" pg:",use_zero"`
-		DocumentID    decimal.Decimal `json:"document_id" pg:",use_zero"`
-		Document      *Document       `json:"document" pg:"rel:has-one"`
+		tableName      struct{}        `pg:"chat_message_document_pairs"`
+		ID             decimal.Decimal `json:"id"`
+		ChatMessageID  decimal.Decimal `json:"chat_message_id" pg:",use_zero"`
+		DocumentID     decimal.Decimal `json:"document_id" pg:",use_zero"`
+		CitationNumber int             `json:"citation_number" pg:",use_zero"`
+		Cited          bool            `json:"cited" pg:",use_zero"`
+		Document       *Document       `json:"document" pg:"rel:has-one"`
 	}
 )
 
@@ -79,10 +83,12 @@ func (c *ChatMessage) AfterSelect(ctx context.Context) error {
 			continue
 		}
 		doc := &DocumentResponse{
-			ID:         dp.DocumentID,
-			MessageID:  dp.ChatMessageID,
-			Link:       dp.Document.OriginalURL,
-			DocumentID: dp.Document.SourceID,
+			ID:             dp.DocumentID,
+			MessageID:      dp.ChatMessageID,
+			Link:           dp.Document.OriginalURL,
+			DocumentID:     dp.Document.SourceID,
+			CitationNumber: dp.CitationNumber,
+			Cited:          dp.Cited,
 		}
 		if !dp.Document.LastUpdate.IsZero() {
 			doc.UpdatedDate = dp.Document.LastUpdate.Time
@@ -22,6 +22,10 @@ const (
 	ConnectorStatusUnableProcess    = "UNABLE_TO_PROCESS"
 )
 
+// ConnectorRunTimeout is the time after which a pending or processing connector
+// can be started again, in case its run was lost.
+const ConnectorRunTimeout = 24 * time.Hour
+
 // Connector is a struct that represents a table connector.
 type Connector struct {
 	tableName               struct{}             `pg:"connectors"`
@@ -30,6 +34,7 @@ type Connector struct {
 	Type                    SourceType           `json:"source,omitempty" pg:"type"`
 	ConnectorSpecificConfig JSONMap              `json:"connector_specific_config,omitempty"`
 	RefreshFreq             int                  `json:"refresh_freq,omitempty"`
+	Schedule                *ConnectorSchedule   `json:"schedule,omitempty" pg:"type:jsonb"`
 	UserID                  uuid.UUID            `json:"user_id,omitempty"`
 	TenantID                uuid.NullUUID        `json:"tenant_id,omitempty"`
 	LastSuccessfulAnalyzed  pg.NullTime          `json:"last_successful_analysis,omitempty" pg:",use_zero"`
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// MaxConnectorRunErrors is the maximum number of document errors stored for a run.
+const MaxConnectorRunErrors = 100
+
+// ConnectorRun is a struct that represents one run of a connector in a database table named "connector_runs".
+// The run is created by the orchestrator when it sends the task and is updated by the connector service
+// that executes it. Status uses the connector status values.
+type ConnectorRun struct {
+	tableName       struct{}             `pg:"connector_runs"`
+	ID              decimal.Decimal      `json:"id,omitempty"`
+	ConnectorID     decimal.Decimal      `json:"connector_id,omitempty"`
+	SessionID       uuid.UUID            `json:"session_id,omitempty"`
+	Status          string               `json:"status,omitempty"`
+	Attempts        int                  `json:"attempts" pg:",use_zero"`
+	DocsAdded       int                  `json:"docs_added" pg:",use_zero"`
+	DocsUpdated     int                  `json:"docs_updated" pg:",use_zero"`
+	DocsDeleted     int                  `json:"docs_deleted" pg:",use_zero"`
+	DocsSkipped     int                  `json:"docs_skipped" pg:",use_zero"`
+	DocsFailed      int                  `json:"docs_failed" pg:",use_zero"`
+	BytesDownloaded int64                `json:"bytes_downloaded" pg:",use_zero"`
+	Error           string               `json:"error,omitempty" pg:",use_zero"`
+	DocumentErrors  []*ConnectorRunError `json:"document_errors,omitempty" pg:"type:jsonb,use_zero"`
+	CreationDate    time.Time            `json:"creation_date,omitempty"`
+	StartDate       pg.NullTime          `json:"start_date,omitempty" pg:",use_zero"`
+	EndDate         pg.NullTime          `json:"end_date,omitempty" pg:",use_zero"`
+}
+
+// ConnectorRunError is the error of a document that could not be processed in a run.
+type ConnectorRunError struct {
+	SourceID string `json:"source_id"`
+	Error    string `json:"error"`
+}
+
+// AddDocumentError counts the failed document and keeps its error,
+// only the first MaxConnectorRunErrors errors are kept.
+func (r *ConnectorRun) AddDocumentError(sourceID string, err error) {
+	r.DocsFailed++
+	if len(r.DocumentErrors) < MaxConnectorRunErrors {
+		r.DocumentErrors = append(r.DocumentErrors, &ConnectorRunError{SourceID: sourceID, Error: err.Error()})
+	}
+}
+
+// Finish sets the end date and the status of the run. The run fails if err is not nil,
+// it is completed with errors if some documents failed.
+func (r *ConnectorRun) Finish(err error) {
+	r.EndDate = pg.NullTime{time.Now().UTC()}
+	switch {
+	case err != nil:
+		r.Status = ConnectorStatusUnableProcess
+		r.Error = err.Error()
+	case r.DocsFailed > 0:
+		r.Status = ConnectorStatusError
+	default:
+		r.Status = ConnectorStatusSuccess
+	}
+}
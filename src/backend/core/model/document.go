@@ -21,15 +21,19 @@ type Document struct {
 	CreationDate    time.Time           `json:"creation_date,omitempty"`
 	LastUpdate      pg.NullTime         `json:"last_update,omitempty" pg:",use_zero"`
 	OriginalURL     string              `json:"original_url,omitempty" pg:",use_zero"`
+	Metadata        JSONMap             `json:"metadata,omitempty" pg:"type:jsonb"`
 	IsExists        bool                `json:"-" pg:"-"`
 }
 
 // DocumentResponse is a struct that represents a response containing document information.
 type DocumentResponse struct {
-	ID          decimal.Decimal `json:"id,omitempty"`
-	MessageID   decimal.Decimal `json:"message_id,omitempty"`
-	Link        string          `json:"link,omitempty"`
-	DocumentID  string          `json:"document_id,omitempty"`
-	Content     string          `json:"content,omitempty"`
-	UpdatedDate time.Time       `json:"updated_date,omitempty"`
+	ID             decimal.Decimal `json:"id,omitempty"`
+	MessageID      decimal.Decimal `json:"message_id,omitempty"`
+	Link           string          `json:"link,omitempty"`
+	DocumentID     string          `json:"document_id,omitempty"`
+	Content        string          `json:"content,omitempty"`
+	Score          float32         `json:"score,omitempty"`
+	CitationNumber int             `json:"citation_number,omitempty"`
+	Cited          bool            `json:"cited,omitempty"`
+	UpdatedDate    time.Time       `json:"updated_date,omitempty"`
 }
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// DocumentChunk is a struct that represents a chunk of the document in a database table named "document_chunks".
+// Chunks are stored together with vectors in the vector database and are used for the full-text search.
+type DocumentChunk struct {
+	tableName      struct{}        `pg:"document_chunks"`
+	ID             decimal.Decimal `json:"id,omitempty"`
+	DocumentID     decimal.Decimal `json:"document_id,omitempty"`
+	CollectionName string          `json:"collection_name,omitempty"`
+	Content        string          `json:"content,omitempty" pg:",use_zero"`
+	CreationDate   time.Time       `json:"creation_date,omitempty"`
+}
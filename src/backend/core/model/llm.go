@@ -7,18 +7,31 @@ import (
 	"time"
 )
 
+const (
+	ResponseFormatText = "text"
+	ResponseFormatJSON = "json_object"
+)
+
 // LLM represents a model of the llms table.
+// Temperature, TopP, MaxTokens, Stop and ResponseFormat are generation settings of the persona,
+// empty values use defaults of the provider.
 type LLM struct {
-	tableName    struct{}        `pg:"llms"`
-	ID           decimal.Decimal `json:"id,omitempty"`
-	Name         string          `json:"name,omitempty"`
-	ModelID      string          `json:"model_id,omitempty"`
-	TenantID     uuid.UUID       `json:"tenant_id,omitempty"`
-	Url          string          `json:"url,omitempty"  pg:",use_zero"`
-	ApiKey       string          `json:"api_key"`
-	Endpoint     string          `json:"endpoint,omitempty"`
-	CreationDate time.Time       `json:"creation_date,omitempty"`
-	LastUpdate   pg.NullTime     `json:"last_update,omitempty" pg:",use_zero"`
+	tableName      struct{}        `pg:"llms"`
+	ID             decimal.Decimal `json:"id,omitempty"`
+	Name           string          `json:"name,omitempty"`
+	ModelID        string          `json:"model_id,omitempty"`
+	TenantID       uuid.UUID       `json:"tenant_id,omitempty"`
+	Url            string          `json:"url,omitempty"  pg:",use_zero"`
+	ApiKey         string          `json:"api_key"`
+	Endpoint       string          `json:"endpoint,omitempty"`
+	ContextSize    int             `json:"context_size,omitempty" pg:",use_zero"`
+	Temperature    *float32        `json:"temperature,omitempty"`
+	TopP           *float32        `json:"top_p,omitempty"`
+	MaxTokens      int             `json:"max_tokens,omitempty" pg:",use_zero"`
+	Stop           StringSlice     `json:"stop,omitempty" pg:",array"`
+	ResponseFormat string          `json:"response_format,omitempty" pg:",use_zero"`
+	CreationDate   time.Time       `json:"creation_date,omitempty"`
+	LastUpdate     pg.NullTime     `json:"last_update,omitempty" pg:",use_zero"`
 
 	DeletedDate pg.NullTime `json:"deleted_date,omitempty" pg:",use_zero"`
 }
@@ -4,12 +4,15 @@ const (
 	ProviderCustom    OAuthProvider = "custom"
 	ProviderMicrosoft OAuthProvider = "microsoft"
 	ProviderGoogle    OAuthProvider = "google"
+	ProviderGmail     OAuthProvider = "gmail"
 )
 
 var ConnectorAuthProvider = map[SourceType]OAuthProvider{
 	SourceTypeOneDrive:    ProviderMicrosoft,
 	SourceTypeMsTeams:     ProviderMicrosoft,
+	SourceTypeSharepoint:  ProviderMicrosoft,
 	SourceTypeGoogleDrive: ProviderGoogle,
+	SourceTypeGMAIL:       ProviderGmail,
 }
 
 // OAuthProvider represents enum for oauth providers
@@ -0,0 +1,185 @@
+package model
+
+import (
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/robfig/cron/v3"
+	"hash/fnv"
+	"time"
+)
+
+// maxScheduleJitter is the maximum jitter of a schedule in seconds.
+const maxScheduleJitter = 24 * 60 * 60
+
+type (
+	// ConnectorSchedule defines when a connector runs instead of its refresh frequency.
+	//
+	// Fields:
+	// - Cron: a cron expression with five fields or a descriptor, like "0 2 * * 1-5" or "@daily".
+	//   If it is empty, the connector runs RefreshFreq seconds after its last update.
+	// - Windows: the times of the day the connector may start in. If there are none, it starts at any time.
+	// - Jitter: the maximum delay in seconds added to the scheduled time, so that connectors
+	//   scheduled at the same time do not start together. The delay of a connector is always the same.
+	// - TimeZone: the IANA time zone of the cron expression and the windows, UTC if it is empty.
+	ConnectorSchedule struct {
+		Cron     string        `json:"cron,omitempty"`
+		Windows  []*TimeWindow `json:"windows,omitempty"`
+		Jitter   int           `json:"jitter,omitempty"`
+		TimeZone string        `json:"time_zone,omitempty"`
+	}
+
+	// TimeWindow is a time range of the day in the format HH:MM. A window that ends before
+	// it starts spans midnight, like 22:00 - 06:00.
+	TimeWindow struct {
+		Start string `json:"start"`
+		End   string `json:"end"`
+	}
+)
+
+// Validate checks the cron expression, the windows, the jitter and the time zone of the schedule.
+// The jitter must be shorter than every window, a connector delayed to the end of a window would never start.
+func (s ConnectorSchedule) Validate() error {
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.Cron, validation.By(func(value interface{}) error {
+			if s.Cron == "" {
+				return nil
+			}
+			_, err := cron.ParseStandard(s.Cron)
+			return err
+		})),
+		validation.Field(&s.Windows),
+		validation.Field(&s.Jitter, validation.Min(0), validation.Max(maxScheduleJitter), validation.By(func(value interface{}) error {
+			for _, window := range s.Windows {
+				if length := window.Length(); length > 0 && time.Duration(s.Jitter)*time.Second >= length {
+					return fmt.Errorf("jitter should be shorter than the window %s - %s", window.Start, window.End)
+				}
+			}
+			return nil
+		})),
+		validation.Field(&s.TimeZone, validation.By(func(value interface{}) error {
+			_, err := time.LoadLocation(s.TimeZone)
+			return err
+		})),
+	)
+}
+
+// Validate checks the format of the start and the end of the window.
+func (w TimeWindow) Validate() error {
+	return validation.ValidateStruct(&w,
+		validation.Field(&w.Start, validation.Required, validation.By(validateClock)),
+		validation.Field(&w.End, validation.Required, validation.By(validateClock)),
+	)
+}
+
+// Contains reports whether the time of the day of t is in the window.
+func (w TimeWindow) Contains(t time.Time) bool {
+	start, err := parseClock(w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(w.End)
+	if err != nil {
+		return false
+	}
+	clock := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	switch {
+	case start == end:
+		return true
+	case start < end:
+		return clock >= start && clock < end
+	default:
+		return clock >= start || clock < end
+	}
+}
+
+// Length returns the duration of the window, zero if the window is not valid.
+func (w TimeWindow) Length() time.Duration {
+	start, err := parseClock(w.Start)
+	if err != nil {
+		return 0
+	}
+	end, err := parseClock(w.End)
+	if err != nil {
+		return 0
+	}
+	if end <= start {
+		end += 24 * time.Hour
+	}
+	return end - start
+}
+
+// NextRun returns the time of the next run of the connector after its last update.
+// The jitter of the schedule is added to the time.
+func (c *Connector) NextRun() (time.Time, error) {
+	last := c.LastUpdate.UTC()
+	if c.Schedule == nil {
+		return last.Add(time.Duration(c.RefreshFreq) * time.Second), nil
+	}
+	location, err := time.LoadLocation(c.Schedule.TimeZone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	next := last.Add(time.Duration(c.RefreshFreq) * time.Second)
+	if c.Schedule.Cron != "" {
+		schedule, err := cron.ParseStandard(c.Schedule.Cron)
+		if err != nil {
+			return time.Time{}, err
+		}
+		next = schedule.Next(last.In(location)).UTC()
+	}
+	return next.Add(c.jitter()), nil
+}
+
+// IsDue reports whether the connector should run at the time. A new connector runs at once,
+// other connectors run at their next run. If the schedule has windows, the connector
+// starts only in them, delayed by the jitter after the window opens.
+func (c *Connector) IsDue(now time.Time) (bool, error) {
+	if !c.LastUpdate.IsZero() {
+		next, err := c.NextRun()
+		if err != nil {
+			return false, err
+		}
+		if now.Before(next) {
+			return false, nil
+		}
+	}
+	if c.Schedule == nil || len(c.Schedule.Windows) == 0 {
+		return true, nil
+	}
+	location, err := time.LoadLocation(c.Schedule.TimeZone)
+	if err != nil {
+		return false, err
+	}
+	local := now.In(location)
+	for _, window := range c.Schedule.Windows {
+		if window.Contains(local) && window.Contains(local.Add(-c.jitter())) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// jitter returns the delay of the connector, it is derived from the ID to stay the same between runs.
+func (c *Connector) jitter() time.Duration {
+	if c.Schedule == nil || c.Schedule.Jitter <= 0 {
+		return 0
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(c.ID.String()))
+	return time.Duration(hash.Sum32()%uint32(c.Schedule.Jitter)) * time.Second
+}
+
+// validateClock checks that the value is a time of the day in the format HH:MM.
+func validateClock(value interface{}) error {
+	_, err := parseClock(value.(string))
+	return err
+}
+
+// parseClock parses a time of the day in the format HH:MM to the duration since midnight.
+func parseClock(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("time should be in the format HH:MM")
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
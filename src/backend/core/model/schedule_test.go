@@ -0,0 +1,87 @@
+package model
+
+import (
+	"github.com/go-pg/pg/v10"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestTimeWindow_Contains(t *testing.T) {
+	overnight := TimeWindow{Start: "22:00", End: "06:00"}
+	assert.True(t, overnight.Contains(time.Date(2024, 7, 1, 23, 30, 0, 0, time.UTC)))
+	assert.True(t, overnight.Contains(time.Date(2024, 7, 1, 5, 59, 0, 0, time.UTC)))
+	assert.False(t, overnight.Contains(time.Date(2024, 7, 1, 6, 0, 0, 0, time.UTC)))
+	assert.False(t, overnight.Contains(time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)))
+
+	office := TimeWindow{Start: "09:00", End: "17:00"}
+	assert.True(t, office.Contains(time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)))
+	assert.False(t, office.Contains(time.Date(2024, 7, 1, 17, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, 8*time.Hour, overnight.Length())
+	assert.Equal(t, 8*time.Hour, office.Length())
+	assert.Equal(t, 24*time.Hour, TimeWindow{Start: "00:00", End: "00:00"}.Length())
+}
+
+func TestConnector_IsDue(t *testing.T) {
+	last := time.Date(2024, 7, 1, 2, 10, 0, 0, time.UTC)
+	conn := &Connector{
+		ID:          decimal.NewFromInt(7),
+		RefreshFreq: 3600,
+		LastUpdate:  pg.NullTime{Time: last},
+	}
+	due, err := conn.IsDue(last.Add(59 * time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, due)
+	due, err = conn.IsDue(last.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, due)
+
+	// cron in the time zone of the schedule, the refresh frequency is not used
+	conn.Schedule = &ConnectorSchedule{Cron: "0 2 * * *", TimeZone: "Europe/Zurich"}
+	next, err := conn.NextRun()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 7, 2, 0, 0, 0, 0, time.UTC), next)
+
+	// the jitter delays the run of a connector by the same time
+	conn.Schedule.Jitter = 600
+	jittered, err := conn.NextRun()
+	assert.NoError(t, err)
+	assert.True(t, !jittered.Before(next) && jittered.Before(next.Add(10*time.Minute)))
+	again, _ := conn.NextRun()
+	assert.Equal(t, jittered, again)
+
+	// a connector that is due starts only in its windows
+	conn.Schedule = &ConnectorSchedule{Windows: []*TimeWindow{{Start: "22:00", End: "06:00"}}}
+	due, err = conn.IsDue(time.Date(2024, 7, 1, 15, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.False(t, due)
+	due, err = conn.IsDue(time.Date(2024, 7, 1, 22, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.True(t, due)
+
+	// a new connector runs at once
+	conn = &Connector{ID: decimal.NewFromInt(8), Schedule: &ConnectorSchedule{Cron: "@daily"}}
+	due, err = conn.IsDue(time.Now().UTC())
+	assert.NoError(t, err)
+	assert.True(t, due)
+}
+
+func TestConnectorSchedule_Validate(t *testing.T) {
+	assert.NoError(t, ConnectorSchedule{}.Validate())
+	assert.NoError(t, ConnectorSchedule{
+		Cron:     "30 1 * * 1-5",
+		Windows:  []*TimeWindow{{Start: "22:00", End: "06:00"}},
+		Jitter:   300,
+		TimeZone: "Europe/Zurich",
+	}.Validate())
+	assert.Error(t, ConnectorSchedule{Cron: "every night"}.Validate())
+	assert.Error(t, ConnectorSchedule{Windows: []*TimeWindow{{Start: "25:00", End: "06:00"}}}.Validate())
+	assert.Error(t, ConnectorSchedule{Windows: []*TimeWindow{{Start: "22:00"}}}.Validate())
+	assert.Error(t, ConnectorSchedule{Jitter: -1}.Validate())
+	// the connector would never start in the window
+	assert.Error(t, ConnectorSchedule{Windows: []*TimeWindow{{Start: "22:00", End: "22:30"}}, Jitter: 1800}.Validate())
+	assert.NoError(t, ConnectorSchedule{Windows: []*TimeWindow{{Start: "22:00", End: "22:30"}}, Jitter: 1799}.Validate())
+	assert.Error(t, ConnectorSchedule{TimeZone: "Mars/Olympus"}.Validate())
+}
@@ -41,36 +41,57 @@ type (
 )
 
 var (
-	sourceTypeFileDescription        = SourceTypeDescription{SourceTypeFile, "File", true}
-	sourceTypeWEBDescription         = SourceTypeDescription{SourceTypeWEB, "Web", true}
-	sourceTypeSlackDescription       = SourceTypeDescription{SourceTypeSlack, "Slack", false}
-	sourceTypeGoogleDriveDescription = SourceTypeDescription{SourceTypeGoogleDrive, "Google Drive", true}
-	sourceTypeGmailDescription       = SourceTypeDescription{SourceTypeGMAIL, "Gmail", false}
-	sourceTypeSharepointDescription  = SourceTypeDescription{SourceTypeSharepoint, "Sharepoint", false}
-	sourceTypeOneDriveDescription    = SourceTypeDescription{SourceTypeOneDrive, "OneDrive", true}
-	sourceTypeMsTeamsDescription     = SourceTypeDescription{SourceTypeMsTeams, "Teams", true}
-	sourceTypeYouTubeDescription     = SourceTypeDescription{SourceTypeYoutube, "Youtube", true}
+	sourceTypeFileDescription         = SourceTypeDescription{SourceTypeFile, "File", true}
+	sourceTypeWEBDescription          = SourceTypeDescription{SourceTypeWEB, "Web", true}
+	sourceTypeSlackDescription        = SourceTypeDescription{SourceTypeSlack, "Slack", true}
+	sourceTypeConfluenceDescription   = SourceTypeDescription{SourceTypeConfluence, "Confluence", true}
+	sourceTypeGithubDescription       = SourceTypeDescription{SourceTypeGithub, "GitHub", true}
+	sourceTypeGitlabDescription       = SourceTypeDescription{SourceTypeGitlab, "GitLab", true}
+	sourceTypeJiraDescription         = SourceTypeDescription{SourceTypeJira, "Jira", true}
+	sourceTypeNotionDescription       = SourceTypeDescription{SourceTypeNotion, "Notion", true}
+	sourceTypeZendeskDescription      = SourceTypeDescription{SourceTypeZendesk, "Zendesk", true}
+	sourceTypeGoogleDriveDescription  = SourceTypeDescription{SourceTypeGoogleDrive, "Google Drive", true}
+	sourceTypeGmailDescription        = SourceTypeDescription{SourceTypeGMAIL, "Gmail / IMAP", true}
+	sourceTypeSharepointDescription   = SourceTypeDescription{SourceTypeSharepoint, "Sharepoint", true}
+	sourceTypeOneDriveDescription     = SourceTypeDescription{SourceTypeOneDrive, "OneDrive", true}
+	sourceTypeMsTeamsDescription      = SourceTypeDescription{SourceTypeMsTeams, "Teams", true}
+	sourceTypeYouTubeDescription      = SourceTypeDescription{SourceTypeYoutube, "Youtube", true}
+	sourceTypeIngestionAPIDescription = SourceTypeDescription{SourceTypeIngestionApi, "Ingestion API", true}
 )
 var AllSourceTypes = map[SourceType]*SourceTypeDescription{
-	SourceTypeFile:        &sourceTypeFileDescription,
-	SourceTypeWEB:         &sourceTypeWEBDescription,
-	SourceTypeSlack:       &sourceTypeSlackDescription,
-	SourceTypeGoogleDrive: &sourceTypeGoogleDriveDescription,
-	SourceTypeGMAIL:       &sourceTypeGmailDescription,
-	SourceTypeSharepoint:  &sourceTypeSharepointDescription,
-	SourceTypeOneDrive:    &sourceTypeOneDriveDescription,
-	SourceTypeMsTeams:     &sourceTypeMsTeamsDescription,
-	SourceTypeYoutube:     &sourceTypeYouTubeDescription,
+	SourceTypeFile:         &sourceTypeFileDescription,
+	SourceTypeWEB:          &sourceTypeWEBDescription,
+	SourceTypeSlack:        &sourceTypeSlackDescription,
+	SourceTypeConfluence:   &sourceTypeConfluenceDescription,
+	SourceTypeGithub:       &sourceTypeGithubDescription,
+	SourceTypeGitlab:       &sourceTypeGitlabDescription,
+	SourceTypeJira:         &sourceTypeJiraDescription,
+	SourceTypeNotion:       &sourceTypeNotionDescription,
+	SourceTypeZendesk:      &sourceTypeZendeskDescription,
+	SourceTypeGoogleDrive:  &sourceTypeGoogleDriveDescription,
+	SourceTypeGMAIL:        &sourceTypeGmailDescription,
+	SourceTypeSharepoint:   &sourceTypeSharepointDescription,
+	SourceTypeOneDrive:     &sourceTypeOneDriveDescription,
+	SourceTypeMsTeams:      &sourceTypeMsTeamsDescription,
+	SourceTypeYoutube:      &sourceTypeYouTubeDescription,
+	SourceTypeIngestionApi: &sourceTypeIngestionAPIDescription,
 }
 
 var SourceTypesList = []*SourceTypeDescription{
 	&sourceTypeFileDescription,
 	&sourceTypeWEBDescription,
 	&sourceTypeSlackDescription,
+	&sourceTypeConfluenceDescription,
+	&sourceTypeGithubDescription,
+	&sourceTypeGitlabDescription,
+	&sourceTypeJiraDescription,
+	&sourceTypeNotionDescription,
+	&sourceTypeZendeskDescription,
 	&sourceTypeGoogleDriveDescription,
 	&sourceTypeGmailDescription,
 	&sourceTypeSharepointDescription,
 	&sourceTypeOneDriveDescription,
 	&sourceTypeMsTeamsDescription,
 	&sourceTypeYouTubeDescription,
+	&sourceTypeIngestionAPIDescription,
 }
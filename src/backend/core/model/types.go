@@ -12,6 +12,15 @@ const (
 	GB             = 1024 * 1024 * 1024
 	ParamFileLimit = "file_limit"
 	ParamSessionID = "session_id"
+	ParamMode      = "mode"
+	// ParamLocalRoot is the directory of the connector service that local repositories must be in.
+	// It is set by the connector service from its configuration and never taken from the request.
+	ParamLocalRoot = "local_root"
+
+	// ModeSync runs the connector incrementally, only new and changed documents are analyzed.
+	ModeSync = "sync"
+	// ModeReindex drops the documents of the connector and analyzes all of them again.
+	ModeReindex = "reindex"
 )
 
 type JSONMap map[string]interface{}
@@ -12,6 +12,7 @@ const (
 	InviteState = "invite"
 
 	ProviderGoogle    = "google"
+	ProviderGmail     = "gmail"
 	ProviderMicrosoft = "microsoft"
 )
 
@@ -60,6 +61,11 @@ func NewProvider(name string, cfg *Config, redirectURL string) (Proxy, error) {
 			redirectURL = cfg.Google.RedirectURL
 		}
 		return NewGoogleProvider(cfg.Google, redirectURL), nil
+	case ProviderGmail:
+		if redirectURL == "" {
+			redirectURL = cfg.Google.RedirectURL
+		}
+		return NewGmailProvider(cfg.Google, redirectURL), nil
 	}
 	return nil, fmt.Errorf("unknown provider: %s", name)
 }
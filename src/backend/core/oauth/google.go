@@ -26,6 +26,7 @@ var googleDriveScopes []string = []string{"https://www.googleapis.com/auth/drive
 	"https://www.googleapis.com/auth/drive.metadata.readonly",
 	"https://www.googleapis.com/auth/drive.activity.readonly",
 }
+var googleGmailScopes = []string{"https://www.googleapis.com/auth/gmail.readonly"}
 
 // GoogleConfig represents the configuration for Google OAuth service.
 type GoogleConfig struct {
@@ -49,14 +50,27 @@ type googleProvider struct {
 // The googleProvider struct also implements the methods defined in the Proxy interface.
 // This function is used to create a new Google OAuth provider in the application.
 func NewGoogleProvider(cfg *GoogleConfig, redirectURL string) Proxy {
+	return newGoogleProvider(cfg, fmt.Sprintf("%s/google/callback", redirectURL),
+		append(append([]string{}, googleAuthScopes...), googleDriveScopes...))
+}
+
+// NewGmailProvider creates a google oAuth client for the Gmail connector.
+// It uses the same client as NewGoogleProvider and requests read access to the mailbox,
+// so the Gmail scope is asked only when a user connects a mailbox and not on login.
+func NewGmailProvider(cfg *GoogleConfig, redirectURL string) Proxy {
+	return newGoogleProvider(cfg, fmt.Sprintf("%s/gmail/callback", redirectURL),
+		append(append([]string{}, googleAuthScopes...), googleGmailScopes...))
+}
+
+func newGoogleProvider(cfg *GoogleConfig, redirectURL string, scopes []string) Proxy {
 	return &googleProvider{
 		httpClient: resty.New().SetTimeout(time.Minute),
 		config: &oauth2.Config{
 			ClientID:     cfg.GoogleClientID,
 			ClientSecret: cfg.GoogleSecret,
 			Endpoint:     google.Endpoint,
-			RedirectURL:  fmt.Sprintf("%s/google/callback", redirectURL),
-			Scopes:       append(googleAuthScopes, googleDriveScopes...),
+			RedirectURL:  redirectURL,
+			Scopes:       scopes,
 		},
 	}
 }
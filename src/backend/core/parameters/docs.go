@@ -1,6 +1,11 @@
 package parameters
 
-import "github.com/shopspring/decimal"
+import (
+	"cognix.ch/api/v2/core/model"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/shopspring/decimal"
+)
 
 type DocumentSetParam struct {
 	Name        string `json:"name"`
@@ -17,3 +22,38 @@ type DocumentUploadResponse struct {
 	Error    string      `json:"error"`
 	Document interface{} `json:"document"`
 }
+
+const (
+	IngestFormatText     = "text"
+	IngestFormatMarkdown = "markdown"
+)
+
+// IngestDocumentParam is a document pushed into an ingestion API connector.
+// The content is either given as text or markdown, or uploaded as a file.
+type IngestDocumentParam struct {
+	SourceID    string        `json:"source_id" form:"source_id"`
+	Title       string        `json:"title" form:"title"`
+	Content     string        `json:"content" form:"content"`
+	Format      string        `json:"format" form:"format"`
+	OriginalURL string        `json:"original_url" form:"original_url"`
+	Metadata    model.JSONMap `json:"metadata" form:"-"`
+}
+
+func (v IngestDocumentParam) Validate() error {
+	return validation.ValidateStruct(&v,
+		validation.Field(&v.SourceID, validation.Required, validation.Length(1, 1024)),
+		validation.Field(&v.Format, validation.In(IngestFormatText, IngestFormatMarkdown)),
+		validation.Field(&v.OriginalURL, is.URL),
+	)
+}
+
+// DeleteIngestedDocumentParam identifies a pushed document by its source id.
+type DeleteIngestedDocumentParam struct {
+	SourceID string `form:"source_id"`
+}
+
+func (v DeleteIngestedDocumentParam) Validate() error {
+	return validation.ValidateStruct(&v,
+		validation.Field(&v.SourceID, validation.Required),
+	)
+}
@@ -35,12 +35,13 @@ type ArchivedParam struct {
 }
 
 type CreateConnectorParam struct {
-	Name                    string        `json:"name,omitempty"`
-	Source                  string        `json:"source,omitempty"`
-	ConnectorSpecificConfig model.JSONMap `json:"connector_specific_config,omitempty"`
-	RefreshFreq             int           `json:"refresh_freq,omitempty"`
-	Shared                  bool          `json:"shared,omitempty"`
-	Disabled                bool          `json:"disabled,omitempty"`
+	Name                    string                   `json:"name,omitempty"`
+	Source                  string                   `json:"source,omitempty"`
+	ConnectorSpecificConfig model.JSONMap            `json:"connector_specific_config,omitempty"`
+	RefreshFreq             int                      `json:"refresh_freq,omitempty"`
+	Schedule                *model.ConnectorSchedule `json:"schedule,omitempty"`
+	Shared                  bool                     `json:"shared,omitempty"`
+	Disabled                bool                     `json:"disabled,omitempty"`
 }
 
 func (v CreateConnectorParam) Validate() error {
@@ -51,22 +52,27 @@ func (v CreateConnectorParam) Validate() error {
 					return fmt.Errorf("invalid source type")
 				}
 				return nil
-			})))
+			})),
+		validation.Field(&v.Schedule),
+	)
 }
 
 type UpdateConnectorParam struct {
-	Name                    string        `json:"name,omitempty"`
-	ConnectorSpecificConfig model.JSONMap `json:"connector_specific_config,omitempty"`
-	RefreshFreq             int           `json:"refresh_freq,omitempty"`
-	Shared                  bool          `json:"shared,omitempty"`
-	Status                  string        `json:"status"`
+	Name                    string                   `json:"name,omitempty"`
+	ConnectorSpecificConfig model.JSONMap            `json:"connector_specific_config,omitempty"`
+	RefreshFreq             int                      `json:"refresh_freq,omitempty"`
+	Schedule                *model.ConnectorSchedule `json:"schedule,omitempty"`
+	Shared                  bool                     `json:"shared,omitempty"`
+	Status                  string                   `json:"status"`
 }
 
 func (v UpdateConnectorParam) Validate() error {
 	return validation.ValidateStruct(&v,
 		validation.Field(&v.Name, validation.Required),
 		validation.Field(&v.ConnectorSpecificConfig, validation.Required),
-		validation.Field(&v.RefreshFreq, validation.Required),
+		// the refresh frequency is not used by cron schedules
+		validation.Field(&v.RefreshFreq, validation.When(v.Schedule == nil || v.Schedule.Cron == "", validation.Required)),
+		validation.Field(&v.Schedule),
 		validation.Field(&v.Status, validation.In("", model.ConnectorStatusReadyToProcessed)),
 	)
 }
@@ -92,3 +98,27 @@ func (v EditUserParam) Validate() error {
 		validation.Field(&v.Role, validation.Required, validation.In(model.RoleSuperAdmin, model.RoleUser, model.RoleAdmin)),
 	)
 }
+
+type DeadLetterListParam struct {
+	Stream string `form:"stream"`
+	From   uint64 `form:"from"`
+	Limit  int    `form:"limit"`
+}
+
+func (v DeadLetterListParam) Validate() error {
+	return validation.ValidateStruct(&v,
+		validation.Field(&v.Limit, validation.Min(0), validation.Max(500)),
+	)
+}
+
+type ConnectorRunListParam struct {
+	Limit  int `form:"limit"`
+	Offset int `form:"offset"`
+}
+
+func (v ConnectorRunListParam) Validate() error {
+	return validation.ValidateStruct(&v,
+		validation.Field(&v.Limit, validation.Min(0), validation.Max(500)),
+		validation.Field(&v.Offset, validation.Min(0)),
+	)
+}
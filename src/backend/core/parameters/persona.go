@@ -1,19 +1,31 @@
 package parameters
 
 import (
+	"cognix.ch/api/v2/core/model"
+	"fmt"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
 type PersonaParam struct {
-	Name            string            `json:"name"`
-	Description     string            `json:"description"`
-	ModelID         string            `json:"model_id"`
-	URL             string            `json:"url"`
-	APIKey          string            `json:"api_key"`
-	Endpoint        string            `json:"endpoint"`
-	SystemPrompt    string            `json:"system_prompt"`
-	TaskPrompt      string            `json:"task_prompt"`
-	StarterMessages []*StarterMessage `json:"starter_messages,omitempty"`
+	Name                 string            `json:"name"`
+	Description          string            `json:"description"`
+	ModelID              string            `json:"model_id"`
+	URL                  string            `json:"url"`
+	APIKey               string            `json:"api_key"`
+	Endpoint             string            `json:"endpoint"`
+	SystemPrompt         string            `json:"system_prompt"`
+	TaskPrompt           string            `json:"task_prompt"`
+	StarterMessages      []*StarterMessage `json:"starter_messages,omitempty"`
+	ContextSize          int               `json:"context_size"`
+	Temperature          *float32          `json:"temperature,omitempty"`
+	TopP                 *float32          `json:"top_p,omitempty"`
+	MaxTokens            int               `json:"max_tokens"`
+	Stop                 []string          `json:"stop,omitempty"`
+	ResponseFormat       string            `json:"response_format"`
+	SearchTopK           int               `json:"search_top_k"`
+	SearchScoreThreshold float32           `json:"search_score_threshold"`
+	ConnectorIDs         []int64           `json:"connector_ids"`
+	SourceTypes          []string          `json:"source_types"`
 }
 
 type StarterMessage struct {
@@ -26,5 +38,19 @@ func (v PersonaParam) Validate() error {
 	return validation.ValidateStruct(&v,
 		validation.Field(&v.Name, validation.Required),
 		validation.Field(&v.ModelID, validation.Required),
-		validation.Field(&v.APIKey, validation.Required))
+		validation.Field(&v.APIKey, validation.Required),
+		validation.Field(&v.ContextSize, validation.Min(0)),
+		validation.Field(&v.Temperature, validation.Min(float32(0)), validation.Max(float32(2))),
+		validation.Field(&v.TopP, validation.Min(float32(0)), validation.Max(float32(1))),
+		validation.Field(&v.MaxTokens, validation.Min(0)),
+		validation.Field(&v.Stop, validation.Length(0, 4)),
+		validation.Field(&v.ResponseFormat, validation.In(model.ResponseFormatText, model.ResponseFormatJSON)),
+		validation.Field(&v.SearchTopK, validation.Min(0), validation.Max(100)),
+		validation.Field(&v.SearchScoreThreshold, validation.Min(float32(0))),
+		validation.Field(&v.SourceTypes, validation.Each(validation.By(func(value interface{}) error {
+			if _, ok := model.AllSourceTypes[model.SourceType(value.(string))]; !ok {
+				return fmt.Errorf("invalid source type")
+			}
+			return nil
+		}))))
 }
@@ -23,6 +23,7 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type EmbedServiceClient interface {
 	GetEmbedding(ctx context.Context, in *EmbedRequest, opts ...grpc.CallOption) (*EmbedResponse, error)
+	Rerank(ctx context.Context, in *RerankRequest, opts ...grpc.CallOption) (*RerankResponse, error)
 }
 
 type embedServiceClient struct {
@@ -42,11 +43,21 @@ func (c *embedServiceClient) GetEmbedding(ctx context.Context, in *EmbedRequest,
 	return out, nil
 }
 
+func (c *embedServiceClient) Rerank(ctx context.Context, in *RerankRequest, opts ...grpc.CallOption) (*RerankResponse, error) {
+	out := new(RerankResponse)
+	err := c.cc.Invoke(ctx, "/com.cognix.EmbedService/Rerank", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // EmbedServiceServer is the server API for EmbedService service.
 // All implementations must embed UnimplementedEmbedServiceServer
 // for forward compatibility
 type EmbedServiceServer interface {
 	GetEmbedding(context.Context, *EmbedRequest) (*EmbedResponse, error)
+	Rerank(context.Context, *RerankRequest) (*RerankResponse, error)
 	mustEmbedUnimplementedEmbedServiceServer()
 }
 
@@ -57,6 +68,9 @@ type UnimplementedEmbedServiceServer struct {
 func (UnimplementedEmbedServiceServer) GetEmbedding(context.Context, *EmbedRequest) (*EmbedResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetEmbedding not implemented")
 }
+func (UnimplementedEmbedServiceServer) Rerank(context.Context, *RerankRequest) (*RerankResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rerank not implemented")
+}
 func (UnimplementedEmbedServiceServer) mustEmbedUnimplementedEmbedServiceServer() {}
 
 // UnsafeEmbedServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -88,6 +102,24 @@ func _EmbedService_GetEmbedding_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EmbedService_Rerank_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RerankRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmbedServiceServer).Rerank(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/com.cognix.EmbedService/Rerank",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmbedServiceServer).Rerank(ctx, req.(*RerankRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // EmbedService_ServiceDesc is the grpc.ServiceDesc for EmbedService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -99,6 +131,10 @@ var EmbedService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetEmbedding",
 			Handler:    _EmbedService_GetEmbedding_Handler,
 		},
+		{
+			MethodName: "Rerank",
+			Handler:    _EmbedService_Rerank_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "embed_service.proto",
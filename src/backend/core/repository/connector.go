@@ -4,21 +4,25 @@ import (
 	"cognix.ch/api/v2/core/model"
 	"cognix.ch/api/v2/core/utils"
 	"context"
+	"errors"
 	"github.com/go-pg/pg/v10"
 	"github.com/go-pg/pg/v10/orm"
 	"github.com/google/uuid"
+	"time"
 )
 
 // ConnectorRepository is an interface for accessing and manipulating connector data in the database.
 type (
 	ConnectorRepository interface {
-		GetActive(ctx context.Context) ([]*model.Connector, error)
+		ClaimActive(ctx context.Context, owner string, lease time.Duration) ([]*model.Connector, error)
+		ReleaseLease(ctx context.Context, owner string, ids ...int64) error
 		GetAllByUser(ctx context.Context, tenantID, userID uuid.UUID) ([]*model.Connector, error)
 		GetByIDAndUser(ctx context.Context, tenantID, userID uuid.UUID, id int64) (*model.Connector, error)
 		GetByID(ctx context.Context, id int64) (*model.Connector, error)
 		GetBySource(ctx context.Context, tenantID, userID uuid.UUID, source model.SourceType) (*model.Connector, error)
 		Create(ctx context.Context, connector *model.Connector) error
 		Update(ctx context.Context, connector *model.Connector) error
+		SetPending(ctx context.Context, connector *model.Connector) (bool, error)
 	}
 	connectorRepository struct {
 		db *pg.DB
@@ -99,7 +103,10 @@ func (r *connectorRepository) GetByID(ctx context.Context, id int64) (*model.Con
 		Relation("User.EmbeddingModel").
 		Where("connector.id = ?", id).
 		First(); err != nil {
-		return nil, utils.NotFound.Wrap(err, "can not load connector")
+		if errors.Is(err, pg.ErrNoRows) {
+			return nil, utils.NotFound.Wrap(err, "can not load connector")
+		}
+		return nil, utils.Internal.Wrap(err, "can not load connector")
 	}
 	connector.DocsMap = make(map[string]*model.Document)
 	for _, doc := range connector.Docs {
@@ -128,30 +135,87 @@ func (r *connectorRepository) Update(ctx context.Context, connector *model.Conne
 	return nil
 }
 
-// GetActive retrieves active connectors with specific statuses and without deleted date.
+// SetPending sets the status of the connector to pending unless a run of it is pending or processing.
+// Runs that were not updated within model.ConnectorRunTimeout are considered lost.
+// The check and the update are done in one statement, so only one of concurrent callers starts a run.
+// It returns false if the connector is running.
+func (r *connectorRepository) SetPending(ctx context.Context, connector *model.Connector) (bool, error) {
+	now := time.Now().UTC()
+	result, err := r.db.WithContext(ctx).Model(connector).
+		Set("status = ?", model.ConnectorStatusPending).
+		Set("last_update = ?", now).
+		Where("id = ?", connector.ID).
+		WhereGroup(func(query *orm.Query) (*orm.Query, error) {
+			return query.WhereOr("status <> all(?)", pg.Array([]string{model.ConnectorStatusPending, model.ConnectorStatusWorking})).
+				WhereOr("last_update is null").
+				WhereOr("last_update < ?", now.Add(-model.ConnectorRunTimeout)), nil
+		}).
+		Update()
+	if err != nil {
+		return false, utils.Internal.Wrap(err, "can not update connector")
+	}
+	if result.RowsAffected() == 0 {
+		return false, nil
+	}
+	connector.Status = model.ConnectorStatusPending
+	connector.LastUpdate = pg.NullTime{now}
+	return true, nil
+}
+
+// ClaimActive claims active connectors with specific statuses and without deleted date for the owner
+// and loads them with their documents and the embedding model of the user.
 //
-// The method loads connectors with the following statuses:
+// The method claims connectors with the following statuses:
 // - READY_TO_PROCESS
 // - COMPLETED_SUCCESSFULLY
 // - COMPLETED_WITH_ERRORS
 //
+// A claimed connector is leased to the owner until the lease expires or is released, other owners
+// skip it. Rows locked by another owner that is claiming at the same time are skipped as well,
+// so several orchestrators share the connectors without triggering one twice.
+// Leases of crashed owners expire and the connectors are claimed again.
+//
 // It returns an array of model.Connector and an error, if any.
-func (r *connectorRepository) GetActive(ctx context.Context) ([]*model.Connector, error) {
+func (r *connectorRepository) ClaimActive(ctx context.Context, owner string, lease time.Duration) ([]*model.Connector, error) {
 	connectors := make([]*model.Connector, 0)
-	//todo ask Gian how to do this
 
 	// load connectors with status that might be resending
 	enabledStatuses := []string{model.ConnectorStatusReadyToProcessed, model.ConnectorStatusSuccess, model.ConnectorStatusError}
 
+	now := time.Now().UTC()
+	var ids []int64
+	if _, err := r.db.QueryContext(ctx, &ids, `UPDATE connectors SET lease_owner = ?, lease_expiry = ?
+		WHERE id IN (SELECT id FROM connectors
+			WHERE status = any(?)
+			AND deleted_date is null
+			AND (lease_expiry is null OR lease_expiry < ?)
+			FOR UPDATE SKIP LOCKED)
+		RETURNING id`,
+		owner, now.Add(lease), pg.Array(enabledStatuses), now); err != nil {
+		return nil, utils.Internal.Wrapf(err, "can not claim connectors: %s ", err.Error())
+	}
+	if len(ids) == 0 {
+		return connectors, nil
+	}
 	if err := r.db.WithContext(ctx).
 		Model(&connectors).
 		Relation("Docs").
 		Relation("User.EmbeddingModel").
-		Where("status = any(?)", pg.Array(enabledStatuses)).
-		Where("connector.deleted_date is null").
+		Where("connector.id in (?)", pg.In(ids)).
 		Select(); err != nil {
 		return nil, utils.Internal.Wrapf(err, "can not load connectors: %s ", err.Error())
 	}
 	return connectors, nil
+}
 
+// ReleaseLease releases the leases of the owner on the connectors, so that they can be claimed again.
+func (r *connectorRepository) ReleaseLease(ctx context.Context, owner string, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := r.db.ExecContext(ctx, `UPDATE connectors SET lease_owner = null, lease_expiry = null
+		WHERE id in (?) AND lease_owner = ?`, pg.In(ids), owner); err != nil {
+		return utils.Internal.Wrap(err, "can not release connectors")
+	}
+	return nil
 }
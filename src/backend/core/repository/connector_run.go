@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"cognix.ch/api/v2/core/parameters"
+	"cognix.ch/api/v2/core/utils"
+	"context"
+	"errors"
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+)
+
+type (
+	// ConnectorRunRepository is an interface that specifies the methods for accessing the run history of connectors.
+	ConnectorRunRepository interface {
+		GetByConnectorID(ctx context.Context, connectorID int64, param *parameters.ConnectorRunListParam) ([]*model.ConnectorRun, error)
+		GetByID(ctx context.Context, connectorID, id int64) (*model.ConnectorRun, error)
+		GetBySessionID(ctx context.Context, sessionID uuid.UUID) (*model.ConnectorRun, error)
+		Create(ctx context.Context, run *model.ConnectorRun) error
+		Update(ctx context.Context, run *model.ConnectorRun) error
+	}
+	connectorRunRepository struct {
+		db *pg.DB
+	}
+)
+
+// GetByConnectorID returns the runs of the connector, the latest run first.
+// Document errors are not loaded, they are returned by GetByID.
+func (r *connectorRunRepository) GetByConnectorID(ctx context.Context, connectorID int64, param *parameters.ConnectorRunListParam) ([]*model.ConnectorRun, error) {
+	runs := make([]*model.ConnectorRun, 0)
+	if err := r.db.WithContext(ctx).Model(&runs).
+		ExcludeColumn("document_errors").
+		Where("connector_id = ?", connectorID).
+		Order("creation_date DESC", "id DESC").
+		Limit(param.Limit).
+		Offset(param.Offset).
+		Select(); err != nil {
+		return nil, utils.NotFound.Wrap(err, "can not load connector runs")
+	}
+	return runs, nil
+}
+
+// GetByID returns the run of the connector with its document errors.
+func (r *connectorRunRepository) GetByID(ctx context.Context, connectorID, id int64) (*model.ConnectorRun, error) {
+	var run model.ConnectorRun
+	if err := r.db.WithContext(ctx).Model(&run).
+		Where("id = ?", id).
+		Where("connector_id = ?", connectorID).
+		First(); err != nil {
+		return nil, utils.NotFound.Wrap(err, "can not load connector run")
+	}
+	return &run, nil
+}
+
+// GetBySessionID returns the run of the session, or nil if the session has no run.
+func (r *connectorRunRepository) GetBySessionID(ctx context.Context, sessionID uuid.UUID) (*model.ConnectorRun, error) {
+	var run model.ConnectorRun
+	if err := r.db.WithContext(ctx).Model(&run).
+		Where("session_id = ?", sessionID).
+		First(); err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, utils.Internal.Wrap(err, "can not load connector run")
+	}
+	return &run, nil
+}
+
+func (r *connectorRunRepository) Create(ctx context.Context, run *model.ConnectorRun) error {
+	if _, err := r.db.WithContext(ctx).Model(run).Insert(); err != nil {
+		return utils.Internal.Wrap(err, "can not create connector run")
+	}
+	return nil
+}
+
+func (r *connectorRunRepository) Update(ctx context.Context, run *model.ConnectorRun) error {
+	if _, err := r.db.WithContext(ctx).Model(run).
+		Where("id = ?", run.ID).
+		Update(); err != nil {
+		return utils.Internal.Wrap(err, "can not update connector run")
+	}
+	return nil
+}
+
+// NewConnectorRunRepository creates a new instance of the ConnectorRunRepository interface, using the provided *pg.DB.
+func NewConnectorRunRepository(db *pg.DB) ConnectorRunRepository {
+	return &connectorRunRepository{db: db}
+}
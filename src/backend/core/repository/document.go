@@ -4,8 +4,10 @@ import (
 	"cognix.ch/api/v2/core/model"
 	"cognix.ch/api/v2/core/utils"
 	"context"
+	"errors"
 	"github.com/go-pg/pg/v10"
 	"github.com/go-pg/pg/v10/orm"
+	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"time"
 )
@@ -16,6 +18,8 @@ type (
 		FindByConnectorIDAndUser(ctx context.Context, user *model.User, connectorID int64) ([]*model.Document, error)
 		FindByConnectorID(ctx context.Context, connectorID int64) ([]*model.Document, error)
 		FindByID(ctx context.Context, id int64) (*model.Document, error)
+		FindBySourceID(ctx context.Context, connectorID int64, sourceID string) (*model.Document, error)
+		FindConnectorIDsByScope(ctx context.Context, tenantID uuid.UUID, connectorIDs []int64, sourceTypes []string) ([]int64, error)
 		Create(ctx context.Context, document *model.Document) error
 		Update(ctx context.Context, document *model.Document) error
 		DeleteByIDS(ctx context.Context, ids ...int64) error
@@ -37,6 +41,47 @@ func (r *documentRepository) FindByID(ctx context.Context, id int64) (*model.Doc
 	return &doc, nil
 }
 
+// FindBySourceID retrieves the document of the connector with the given source id.
+// It returns nil without an error if the connector has no such document.
+func (r *documentRepository) FindBySourceID(ctx context.Context, connectorID int64, sourceID string) (*model.Document, error) {
+	var doc model.Document
+	if err := r.db.WithContext(ctx).Model(&doc).
+		Where("connector_id = ?", connectorID).
+		Where("source_id = ?", sourceID).
+		First(); err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, utils.Internal.Wrapf(err, "can not find document [%s]", err.Error())
+	}
+	return &doc, nil
+}
+
+// FindConnectorIDsByScope returns ids of the tenant's connectors
+// that have one of the given ids or one of the given source types.
+func (r *documentRepository) FindConnectorIDsByScope(ctx context.Context, tenantID uuid.UUID, connectorIDs []int64, sourceTypes []string) ([]int64, error) {
+	ids := make([]int64, 0)
+	if len(connectorIDs) == 0 && len(sourceTypes) == 0 {
+		return ids, nil
+	}
+	if err := r.db.WithContext(ctx).Model((*model.Connector)(nil)).
+		Column("connector.id").
+		Where("connector.tenant_id = ?", tenantID).
+		Where("connector.deleted_date IS NULL").
+		WhereGroup(func(query *orm.Query) (*orm.Query, error) {
+			if len(connectorIDs) > 0 {
+				query = query.WhereOr("connector.id IN (?)", pg.In(connectorIDs))
+			}
+			if len(sourceTypes) > 0 {
+				query = query.WhereOr("connector.type IN (?)", pg.In(sourceTypes))
+			}
+			return query, nil
+		}).Select(&ids); err != nil {
+		return nil, utils.Internal.Wrap(err, "can not find connectors of the scope")
+	}
+	return ids, nil
+}
+
 // FindByConnectorID retrieves a list of documents associated with a given connector ID.
 func (r *documentRepository) FindByConnectorID(ctx context.Context, connectorID int64) ([]*model.Document, error) {
 	documents := make([]*model.Document, 0)
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"cognix.ch/api/v2/core/utils"
+	"context"
+	"github.com/go-pg/pg/v10"
+	"strings"
+	"unicode"
+)
+
+// fullTextConfig is a text search configuration used for the content of chunks.
+// simple configuration does not use stemming, so it works for any language and keeps identifiers as is.
+const fullTextConfig = "simple"
+
+type (
+	// DocumentChunkRepository represents an interface for the full-text search over chunks of documents.
+	DocumentChunkRepository interface {
+		Search(ctx context.Context, query string, limit int, connectorIDs []int64, collectionNames ...string) ([]*model.DocumentChunk, error)
+	}
+	documentChunkRepository struct {
+		db *pg.DB
+	}
+)
+
+// NewDocumentChunkRepository creates a new instance of DocumentChunkRepository with the provided database connection.
+func NewDocumentChunkRepository(db *pg.DB) DocumentChunkRepository {
+	return &documentChunkRepository{db: db}
+}
+
+// Search finds chunks that contain any of the terms of the query in the given collections.
+// If connectorIDs is not nil, only chunks of documents of these connectors are searched.
+// Chunks are ordered by the full-text rank, the most relevant first.
+func (r *documentChunkRepository) Search(ctx context.Context, query string, limit int, connectorIDs []int64, collectionNames ...string) ([]*model.DocumentChunk, error) {
+	chunks := make([]*model.DocumentChunk, 0)
+	tsQuery := buildTSQuery(query)
+	if tsQuery == "" || len(collectionNames) == 0 || (connectorIDs != nil && len(connectorIDs) == 0) {
+		return chunks, nil
+	}
+	stm := r.db.WithContext(ctx).Model(&chunks).
+		Where("collection_name IN (?)", pg.In(collectionNames)).
+		Where("content_tsv @@ to_tsquery(?, ?)", fullTextConfig, tsQuery)
+	if connectorIDs != nil {
+		stm = stm.Where("document_id IN (SELECT id FROM documents WHERE connector_id IN (?))", pg.In(connectorIDs))
+	}
+	if err := stm.
+		OrderExpr("ts_rank(content_tsv, to_tsquery(?, ?)) DESC", fullTextConfig, tsQuery).
+		Limit(limit).
+		Select(); err != nil {
+		return nil, utils.Internal.Wrap(err, "can not search document chunks")
+	}
+	return chunks, nil
+}
+
+// buildTSQuery converts the text into the tsquery that matches any of the words of the text.
+// Characters that have special meaning in tsquery are removed.
+func buildTSQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '.'
+	})
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.Trim(word, "-_.")
+		if word == "" {
+			continue
+		}
+		terms = append(terms, "'"+word+"'")
+	}
+	return strings.Join(terms, " | ")
+}
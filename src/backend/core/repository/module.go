@@ -23,7 +23,9 @@ var RepositoriesModule = fx.Options(
 		NewPersonaRepository,
 		NewChatRepository,
 		NewDocumentRepository,
+		NewDocumentChunkRepository,
 		NewEmbeddingModelRepository,
 		NewTenantRepository,
+		NewConnectorRunRepository,
 	),
 )
@@ -10,7 +10,6 @@ import (
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
-	"strings"
 	"sync"
 	"time"
 )
@@ -20,6 +19,7 @@ import (
 // interacting with the chat repository, performing document searches, and managing vectors in a VectorDB.
 // The embedding model is used for document search and retrieval.
 type aiResponder struct {
+	cfg            *Config
 	aiClient       ai.Client
 	charRepo       repository.ChatRepository
 	searcher       ai.Searcher
@@ -63,7 +63,32 @@ func (r *aiResponder) Send(ctx context.Context,
 		return
 	}
 
-	docs, err := r.FindDocuments(ctx, ch, user, &message, model.CollectionName(user.ID, uuid.NullUUID{Valid: true, UUID: user.TenantID}),
+	packer := newContextPacker(r.cfg, persona.LLM)
+	parentMessage.TokenCount = packer.tokenizer.CountTokens(parentMessage.Message)
+	if r.cfg.RephraseQuery && !noLLM {
+		query, err := r.rephraseQuery(ctx, parentMessage, packer.tokenizer)
+		if err != nil {
+			zap.S().Errorf("rephrase query %s ", err.Error())
+		} else if query != parentMessage.Message {
+			parentMessage.RephrasedQuery = query
+		}
+	}
+	if err := r.charRepo.UpdateMessage(ctx, parentMessage); err != nil {
+		zap.S().Errorf("update user message %s ", err.Error())
+	}
+
+	searchParams, err := r.searchParams(ctx, user, persona)
+	if err != nil {
+		zap.S().Errorf("retrieval scope of persona %s ", err.Error())
+		// an answer without the documents of the scope would not be grounded
+		ch <- &Response{
+			IsValid: false,
+			Type:    ResponseError,
+			Err:     err,
+		}
+		return
+	}
+	docs, err := r.FindDocuments(ctx, ch, user, &message, searchParams, model.CollectionName(user.ID, uuid.NullUUID{Valid: true, UUID: user.TenantID}),
 		model.CollectionName(user.ID, uuid.NullUUID{Valid: false}))
 	if err != nil {
 
@@ -71,40 +96,61 @@ func (r *aiResponder) Send(ctx context.Context,
 	if noLLM {
 		return
 	}
-	messageParts := []string{
-		persona.Prompt.SystemPrompt,
-		parentMessage.Message,
-		persona.Prompt.TaskPrompt,
-	}
-
-	for _, doc := range docs {
-		if len(messageParts) > 5 {
-			break
-		}
-		messageParts = append(messageParts, doc.Content)
+	// llm messages : system prompt, previous turns of the conversation,
+	// user message : user chat \n task_prompt \n citation prompt \n [1] document content1 \n ...\n [n] document content n
+	// documents are added in the order of their rank while they fit into the context window of the model.
+	messages, packedDocs := packer.Pack(persona.Prompt.SystemPrompt, parentMessage.ParentMessage,
+		parentMessage.Message, persona.Prompt.TaskPrompt, docs)
+	for _, doc := range packedDocs {
 		if doc.ID.IntPart() != 0 {
 			message.DocumentPairs = append(message.DocumentPairs, &model.ChatMessageDocumentPair{
-				ChatMessageID: message.ID,
-				DocumentID:    doc.ID,
+				ChatMessageID:  message.ID,
+				DocumentID:     doc.ID,
+				CitationNumber: doc.CitationNumber,
 			})
 		}
 	}
 	message.Citations = docs
 	message.Message = ""
-	//_ = docs
-	// docs.Content
-	// user chat
-	// system_prompt
-	// task_prompt
-	// default_prompt
-	// llm message format : system prompt \n user chat \n task_prompt \n document content1 \n ...\n document content n ( top 5)
-	//
-	response, err := r.aiClient.Request(ctx, strings.Join(messageParts, "\n"))
+
+	// answer is streamed to the client chunk by chunk. delta responses contain only the
+	// identifiers of the message to keep events small, the whole message is sent at the end.
+	deltaMessage := &model.ChatMessage{
+		ID:              message.ID,
+		ChatSessionID:   message.ChatSessionID,
+		ParentMessageID: message.ParentMessageID,
+		MessageType:     message.MessageType,
+		TimeSent:        message.TimeSent,
+	}
+	response, err := r.aiClient.RequestStream(ctx, messages, ai.NewGenerationSettings(persona.LLM), func(delta string) error {
+		// the client may be gone, the stream is stopped instead of blocking on the channel
+		select {
+		case ch <- &Response{
+			IsValid: true,
+			Type:    ResponseDelta,
+			Message: deltaMessage,
+			Delta:   delta,
+		}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
 
 	if err != nil {
 		message.Error = err.Error()
 	} else {
 		message.Message = response.Message
+		message.TokenCount = packer.tokenizer.CountTokens(response.Message)
+		// answers without valid [n] citations are flagged
+		cited, valid := parseCitations(response.Message, len(packedDocs))
+		message.InvalidCitations = !valid
+		for _, doc := range packedDocs {
+			doc.Cited = cited[doc.CitationNumber]
+		}
+		for _, pair := range message.DocumentPairs {
+			pair.Cited = cited[pair.CitationNumber]
+		}
 	}
 
 	if errr := r.charRepo.UpdateMessage(ctx, &message); errr != nil {
@@ -131,7 +177,8 @@ func (r *aiResponder) Send(ctx context.Context,
 // - ctx: the context.Context for the method execution.
 // - ch: the channel to send the response to.
 // - user: the user performing the search.
-// - message: the chat message containing the search query.
+// - message: the chat message, the search query is the rephrased query or the text of its parent message.
+// - params: the number of results, the score threshold and metadata filters of the search.
 // - collectionNames: the names of the collections to search in.
 //
 // Returns:
@@ -141,9 +188,18 @@ func (r *aiResponder) FindDocuments(ctx context.Context,
 	ch chan *Response,
 	user *model.User,
 	message *model.ChatMessage,
+	params *storage.SearchParams,
 	collectionNames ...string) ([]*model.DocumentResponse, error) {
 
-	searchResult, err := r.searcher.FindDocuments(ctx, user.ID, user.TenantID, r.embeddingModel, message.ParentMessage.Message, collectionNames...)
+	// persona is restricted to connectors without documents
+	if connectorIDs, ok := params.FilterValues(storage.ColumnNameConnectorID); ok && len(connectorIDs) == 0 {
+		return nil, nil
+	}
+	query := message.ParentMessage.Message
+	if message.ParentMessage.RephrasedQuery != "" {
+		query = message.ParentMessage.RephrasedQuery
+	}
+	searchResult, err := r.searcher.FindDocuments(ctx, user.ID, user.TenantID, r.embeddingModel, query, params, collectionNames...)
 	if err != nil {
 		zap.S().Errorf("embeding service %s ", err.Error())
 		ch <- &Response{
@@ -160,6 +216,7 @@ func (r *aiResponder) FindDocuments(ctx context.Context,
 			ID:        decimal.NewFromInt(sr.DocumentID),
 			MessageID: message.ID,
 			Content:   sr.Content,
+			Score:     sr.Score,
 		}
 		dbDoc, err := r.docRepo.FindByID(ctx, sr.DocumentID)
 		if err != nil {
@@ -192,11 +249,32 @@ func (r *aiResponder) FindDocuments(ctx context.Context,
 	return result, nil
 }
 
+// searchParams returns search parameters of the persona. If the persona has a retrieval scope,
+// the search is restricted to documents of the chosen connectors and connectors of the chosen source types.
+func (r *aiResponder) searchParams(ctx context.Context, user *model.User, persona *model.Persona) (*storage.SearchParams, error) {
+	params := &storage.SearchParams{
+		TopK:           persona.SearchTopK,
+		ScoreThreshold: persona.SearchScoreThreshold,
+	}
+	if !persona.HasRetrievalScope() {
+		return params, nil
+	}
+	connectorIDs, err := r.docRepo.FindConnectorIDsByScope(ctx, user.TenantID, persona.ConnectorIDs, persona.SourceTypes)
+	if err != nil {
+		return nil, err
+	}
+	params.Filters = append(params.Filters, &storage.SearchFilter{
+		Field:  storage.ColumnNameConnectorID,
+		Values: connectorIDs,
+	})
+	return params, nil
+}
+
 // NewAIResponder creates a new AIResponder object with the given dependencies.
-// It takes an Client, ChatRepository, Searcher, VectorDBClient, DocumentRepository,
+// It takes a Config, an Client, ChatRepository, Searcher, VectorDBClient, DocumentRepository,
 // and an embeddingModel as parameters and returns a ChatResponder object.
 // The ChatResponder object is implemented by the aiResponder struct.
-// The aiResponder struct has the following fields: aiClient, charRepo, searcher,
+// The aiResponder struct has the following fields: cfg, aiClient, charRepo, searcher,
 // vectorDBClinet, docRepo, and embeddingModel.
 // The implementation of the Send method in aiResponder is responsible for sending chat responses.
 // The Send method takes a context, a response channel, a wait group, a user, a boolean flag,
@@ -204,6 +282,7 @@ func (r *aiResponder) FindDocuments(ctx context.Context,
 // The NewAIResponder function initializes an aiResponder object with the provided dependencies
 // and returns it as a ChatResponder object.
 func NewAIResponder(
+	cfg *Config,
 	aiClient ai.Client,
 	charRepo repository.ChatRepository,
 	searcher ai.Searcher,
@@ -211,7 +290,8 @@ func NewAIResponder(
 	docRepo repository.DocumentRepository,
 	embeddingModel string,
 ) ChatResponder {
-	return &aiResponder{aiClient: aiClient,
+	return &aiResponder{cfg: cfg,
+		aiClient:       aiClient,
 		charRepo:       charRepo,
 		searcher:       searcher,
 		vectorDBClinet: vectorDBClinet,
@@ -0,0 +1,40 @@
+package responder
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// citationPrompt instructs the LLM to refer to numbered documents of the user message.
+const citationPrompt = `Answer using the numbered documents below. ` +
+	`Cite the documents that support each statement with their numbers in square brackets, for example [1] or [1][3]. ` +
+	`Do not cite documents that are not listed.`
+
+// citationMarker matches citation markers like [1] or [1, 2] in the answer.
+var citationMarker = regexp.MustCompile(`\[(\d+(?:\s*,\s*\d+)*)\]`)
+
+// numberDocument prefixes the content of the document with its citation number.
+func numberDocument(number int, content string) string {
+	return fmt.Sprintf("[%d] %s", number, content)
+}
+
+// parseCitations returns the set of valid citation numbers found in the answer.
+// Numbers outside of 1..documentsCount are invalid. The answer is valid if all its citations
+// are valid and it cites at least one document, a citation is required only if there are documents.
+func parseCitations(answer string, documentsCount int) (map[int]bool, bool) {
+	cited := make(map[int]bool)
+	valid := true
+	for _, match := range citationMarker.FindAllStringSubmatch(answer, -1) {
+		for _, part := range strings.Split(match[1], ",") {
+			number, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || number < 1 || number > documentsCount {
+				valid = false
+				continue
+			}
+			cited[number] = true
+		}
+	}
+	return cited, valid && (len(cited) > 0 || documentsCount == 0)
+}
@@ -0,0 +1,29 @@
+package responder
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseCitations(t *testing.T) {
+	cited, valid := parseCitations("Paris is the capital [1]. It has 2M inhabitants [2, 3].", 3)
+	assert.True(t, valid)
+	assert.Equal(t, map[int]bool{1: true, 2: true, 3: true}, cited)
+
+	// citation of a document that was not in the prompt
+	cited, valid = parseCitations("Paris is the capital [1][4].", 3)
+	assert.False(t, valid)
+	assert.Equal(t, map[int]bool{1: true}, cited)
+
+	// answer without citations
+	cited, valid = parseCitations("Paris is the capital.", 3)
+	assert.False(t, valid)
+	assert.Empty(t, cited)
+
+	// no documents were found, the answer can not cite any
+	cited, valid = parseCitations("I could not find this in the documents.", 0)
+	assert.True(t, valid)
+	assert.Empty(t, cited)
+	_, valid = parseCitations("Paris is the capital [1].", 0)
+	assert.False(t, valid)
+}
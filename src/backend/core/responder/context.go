@@ -0,0 +1,94 @@
+package responder
+
+import (
+	"cognix.ch/api/v2/core/ai"
+	"cognix.ch/api/v2/core/model"
+	"strings"
+)
+
+// contextPacker assembles messages sent to the LLM within the context window of the model.
+// The window is shared by the answer, the system prompt, the user question, previous turns
+// of the conversation and documents found by the search.
+type contextPacker struct {
+	tokenizer     ai.Tokenizer
+	contextSize   int
+	responseSize  int
+	historyBudget int
+}
+
+// newContextPacker creates a context packer for the LLM of the persona.
+// The space for the answer is the max tokens setting of the LLM if it is set.
+func newContextPacker(cfg *Config, llm *model.LLM) *contextPacker {
+	modelID, contextSize, responseSize := "", 0, cfg.ResponseTokenReserve
+	if llm != nil {
+		modelID, contextSize = llm.ModelID, llm.ContextSize
+		if llm.MaxTokens > 0 {
+			responseSize = llm.MaxTokens
+		}
+	}
+	return &contextPacker{
+		tokenizer:     ai.NewTokenizer(modelID),
+		contextSize:   ai.ContextSize(modelID, contextSize),
+		responseSize:  responseSize,
+		historyBudget: cfg.HistoryTokenBudget,
+	}
+}
+
+// Pack returns messages for the LLM and documents included into the user message.
+// The system prompt and the question with the task prompt are always included.
+// Previous turns take at most the history budget and at most half of the remaining space,
+// the rest is filled with documents in the order of their rank. Documents that do not fit are skipped.
+// Included documents are numbered for citations, their CitationNumber is set.
+func (p *contextPacker) Pack(systemPrompt string, previous *model.ChatMessage,
+	question, taskPrompt string,
+	docs []*model.DocumentResponse) ([]*ai.ChatMessage, []*model.DocumentResponse) {
+	var messages []*ai.ChatMessage
+	if systemPrompt != "" {
+		messages = append(messages, &ai.ChatMessage{
+			Role:    ai.ChatMessageRoleSystem,
+			Content: systemPrompt,
+		})
+	}
+	messageParts := []string{question}
+	if taskPrompt != "" {
+		messageParts = append(messageParts, taskPrompt)
+	}
+	userMessage := &ai.ChatMessage{
+		Role:    ai.ChatMessageRoleUser,
+		Content: strings.Join(messageParts, "\n"),
+	}
+	remaining := p.contextSize - p.responseSize - p.tokenizer.CountMessages(append(messages, userMessage)...)
+
+	historyBudget := p.historyBudget
+	if historyBudget > remaining/2 {
+		historyBudget = remaining / 2
+	}
+	history := buildHistory(previous, historyBudget, p.tokenizer)
+	remaining -= p.tokenizer.CountMessages(history...)
+
+	if len(docs) > 0 {
+		messageParts = append(messageParts, citationPrompt)
+		remaining -= p.tokenizer.CountTokens(citationPrompt) + 1
+	}
+	var packed []*model.DocumentResponse
+	for _, doc := range docs {
+		content := numberDocument(len(packed)+1, doc.Content)
+		// every document is separated from the previous part by a new line
+		tokens := p.tokenizer.CountTokens(content) + 1
+		if tokens > remaining {
+			continue
+		}
+		remaining -= tokens
+		messageParts = append(messageParts, content)
+		doc.CitationNumber = len(packed) + 1
+		packed = append(packed, doc)
+	}
+	if len(packed) == 0 && len(docs) > 0 {
+		messageParts = messageParts[:len(messageParts)-1]
+	}
+	userMessage.Content = strings.Join(messageParts, "\n")
+
+	messages = append(messages, history...)
+	messages = append(messages, userMessage)
+	return messages, packed
+}
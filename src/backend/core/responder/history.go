@@ -0,0 +1,46 @@
+package responder
+
+import (
+	"cognix.ch/api/v2/core/ai"
+	"cognix.ch/api/v2/core/model"
+)
+
+// buildHistory walks the chain of parent messages starting from the given message
+// and returns previous conversation turns as role-tagged messages in chronological order.
+// Turns are added from the newest to the oldest until the token budget counted by the tokenizer is exhausted.
+// Messages with errors and system messages are skipped.
+func buildHistory(message *model.ChatMessage, tokenBudget int, tokenizer ai.Tokenizer) []*ai.ChatMessage {
+	var history []*ai.ChatMessage
+	visited := make(map[string]bool)
+	for msg := message; msg != nil; msg = msg.ParentMessage {
+		if visited[msg.ID.String()] {
+			break
+		}
+		visited[msg.ID.String()] = true
+		if msg.Message == "" || msg.Error != "" {
+			continue
+		}
+		role := ai.ChatMessageRoleUser
+		switch msg.MessageType {
+		case model.MessageTypeAssistant:
+			role = ai.ChatMessageRoleAssistant
+		case model.MessageTypeSystem:
+			continue
+		}
+		turn := &ai.ChatMessage{
+			Role:    role,
+			Content: msg.Message,
+		}
+		tokens := tokenizer.CountMessages(turn)
+		if tokens > tokenBudget {
+			break
+		}
+		tokenBudget -= tokens
+		history = append(history, turn)
+	}
+	// reverse history to chronological order
+	for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+		history[i], history[j] = history[j], history[i]
+	}
+	return history
+}
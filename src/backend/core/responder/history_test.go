@@ -0,0 +1,80 @@
+package responder
+
+import (
+	"cognix.ch/api/v2/core/ai"
+	"cognix.ch/api/v2/core/model"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// conversation returns the last message of a conversation of two questions and two answers.
+func conversation() *model.ChatMessage {
+	var previous *model.ChatMessage
+	for i, text := range []string{"first question", "first answer", "second question", "second answer"} {
+		messageType := model.MessageTypeUser
+		if i%2 == 1 {
+			messageType = model.MessageTypeAssistant
+		}
+		previous = &model.ChatMessage{
+			ID:            decimal.NewFromInt(int64(i + 1)),
+			Message:       text,
+			MessageType:   messageType,
+			ParentMessage: previous,
+		}
+	}
+	return previous
+}
+
+func TestBuildHistory(t *testing.T) {
+	tokenizer := ai.NewTokenizer("llama3")
+	// the budget is enough for the last two turns, the oldest turns are dropped
+	budget := tokenizer.CountMessages(
+		&ai.ChatMessage{Role: ai.ChatMessageRoleUser, Content: "second question"},
+		&ai.ChatMessage{Role: ai.ChatMessageRoleAssistant, Content: "second answer"})
+	history := buildHistory(conversation(), budget, tokenizer)
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, &ai.ChatMessage{Role: ai.ChatMessageRoleUser, Content: "second question"}, history[0])
+		assert.Equal(t, &ai.ChatMessage{Role: ai.ChatMessageRoleAssistant, Content: "second answer"}, history[1])
+	}
+
+	history = buildHistory(conversation(), 1000, tokenizer)
+	if assert.Len(t, history, 4) {
+		assert.Equal(t, "first question", history[0].Content)
+		assert.Equal(t, "second answer", history[3].Content)
+	}
+
+	assert.Empty(t, buildHistory(conversation(), 0, tokenizer))
+}
+
+func TestContextPackerHistory(t *testing.T) {
+	tokenizer := ai.NewTokenizer("llama3")
+	question := &ai.ChatMessage{Role: ai.ChatMessageRoleUser, Content: "current question"}
+	lastTurn := tokenizer.CountMessages(&ai.ChatMessage{Role: ai.ChatMessageRoleAssistant, Content: "second answer"})
+	tests := []struct {
+		name        string
+		contextSize int
+		history     []string
+	}{
+		// half of the remaining space is used by the history
+		{name: "newest turn", contextSize: tokenizer.CountMessages(question) + 2*lastTurn + 1, history: []string{"second answer"}},
+		// the question is sent even if it does not fit into the context window
+		{name: "no space", contextSize: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packer := &contextPacker{
+				tokenizer:     tokenizer,
+				contextSize:   tt.contextSize,
+				historyBudget: 1000,
+			}
+			messages, _ := packer.Pack("", conversation(), "current question", "", nil)
+			if assert.Len(t, messages, len(tt.history)+1) {
+				for i, content := range tt.history {
+					assert.Equal(t, content, messages[i].Content)
+				}
+				assert.Equal(t, question, messages[len(messages)-1])
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package responder
+
+import (
+	"cognix.ch/api/v2/core/ai"
+	"cognix.ch/api/v2/core/model"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// rephrasePrompt is a system prompt used to rewrite the latest user message into a standalone search query.
+const rephrasePrompt = `Given the conversation history and the latest user message, rewrite the latest user message ` +
+	`into a standalone search query that can be understood without the history. ` +
+	`Resolve pronouns and references using the history. Keep the language of the user message. ` +
+	`Respond only with the query.`
+
+// rephraseQuery asks the LLM to rewrite the user message into a standalone search query using the conversation history.
+// The user message is returned as is if there is no history.
+func (r *aiResponder) rephraseQuery(ctx context.Context, userMessage *model.ChatMessage, tokenizer ai.Tokenizer) (string, error) {
+	history := buildHistory(userMessage.ParentMessage, r.cfg.HistoryTokenBudget, tokenizer)
+	if len(history) == 0 {
+		return userMessage.Message, nil
+	}
+	var conversation []string
+	for _, msg := range history {
+		conversation = append(conversation, fmt.Sprintf("%s: %s", msg.Role, msg.Content))
+	}
+	response, err := r.aiClient.Request(ctx, []*ai.ChatMessage{
+		{
+			Role:    ai.ChatMessageRoleSystem,
+			Content: rephrasePrompt,
+		},
+		{
+			Role: ai.ChatMessageRoleUser,
+			Content: fmt.Sprintf("Conversation history:\n%s\n\nLatest user message: %s",
+				strings.Join(conversation, "\n"), userMessage.Message),
+		},
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	query := strings.TrimSpace(response.Message)
+	if query == "" {
+		return userMessage.Message, nil
+	}
+	return query, nil
+}
@@ -11,19 +11,33 @@ const (
 	ResponseMessage  = "message"
 	ResponseError    = "error"
 	ResponseDocument = "document"
+	ResponseDelta    = "delta"
 	ResponseEnd      = "end"
 )
 
 // Response represents a response object containing information about a chat message response.
 // It includes fields for validity, type, chat message, document response, and an error.
+// Delta contains the next chunk of the answer for responses with type ResponseDelta.
 type Response struct {
 	IsValid  bool
 	Type     string
 	Message  *model.ChatMessage
 	Document *model.DocumentResponse
+	Delta    string
 	Err      error
 }
 
+// Config is a struct that holds the configuration settings of the chat responders.
+//   - HistoryTokenBudget: the maximum number of tokens of previous conversation turns sent to the LLM.
+//     Older turns that do not fit into the budget are truncated.
+//   - RephraseQuery: rewrite follow-up messages into standalone search queries before the document search.
+//   - ResponseTokenReserve: the number of tokens of the context window reserved for the answer of the LLM.
+type Config struct {
+	HistoryTokenBudget   int  `env:"CHAT_HISTORY_TOKEN_BUDGET" envDefault:"2000"`
+	RephraseQuery        bool `env:"CHAT_REPHRASE_QUERY" envDefault:"true"`
+	ResponseTokenReserve int  `env:"CHAT_RESPONSE_TOKEN_RESERVE" envDefault:"1024"`
+}
+
 // ChatResponder is an interface that represents an object capable of sending chat responses.
 // It defines a method `Send` that takes in a context, a response channel, a wait group, a user,
 // a boolean flag, a parent message, and a persona, and sends the chat response.
@@ -19,11 +19,16 @@ import (
 const (
 	ColumnNameID         = "id"
 	ColumnNameDocumentID = "document_id"
-	ColumnNameContent    = "content"
-	ColumnNameVector     = "vector"
+	// ColumnNameConnectorID is the connector of the document, it restricts the search to the retrieval scope of a persona.
+	ColumnNameConnectorID = "connector_id"
+	ColumnNameContent     = "content"
+	ColumnNameVector      = "vector"
 
 	VectorDimension = 1536
 
+	// DefaultTopK is the number of entities returned by the vector search if top k is not set.
+	DefaultTopK = 10
+
 	IndexStrategyDISKANN   = "DISKANN"
 	IndexStrategyAUTOINDEX = "AUTOINDEX"
 	IndexStrategyNoIndex   = "NOINDEX"
@@ -42,16 +47,33 @@ type (
 		IndexStrategy string `env:"MILVUS_INDEX_STRATEGY" envDefault:"DISKANN"`
 	}
 	MilvusPayload struct {
-		ID         int64     `json:"id"`
-		DocumentID int64     `json:"document_id"`
-		Chunk      int64     `json:"chunk"`
-		Content    string    `json:"content"`
-		Vector     []float32 `json:"vector"`
+		ID          int64     `json:"id"`
+		DocumentID  int64     `json:"document_id"`
+		ConnectorID int64     `json:"connector_id"`
+		Chunk       int64     `json:"chunk"`
+		Content     string    `json:"content"`
+		Vector      []float32 `json:"vector"`
+		Score       float32   `json:"score"`
+	}
+	// SearchFilter restricts the vector search to entities whose field matches any of the values.
+	SearchFilter struct {
+		Field  string  `json:"field"`
+		Values []int64 `json:"values"`
+	}
+	// SearchParams are parameters of the vector search.
+	//   - TopK: the maximum number of entities returned from a collection.
+	//   - ScoreThreshold: the minimum similarity of entities for COSINE and IP metrics,
+	//     the maximum distance for L2 metric. Zero disables the threshold.
+	//   - Filters: metadata filters combined with and.
+	SearchParams struct {
+		TopK           int             `json:"top_k"`
+		ScoreThreshold float32         `json:"score_threshold"`
+		Filters        []*SearchFilter `json:"filters"`
 	}
 	VectorDBClient interface {
 		CreateSchema(ctx context.Context, name string) error
 		Save(ctx context.Context, collection string, payloads ...*MilvusPayload) error
-		Load(ctx context.Context, collection string, vector []float32) ([]*MilvusPayload, error)
+		Load(ctx context.Context, collection string, vector []float32, params *SearchParams) ([]*MilvusPayload, error)
 		Delete(ctx context.Context, collection string, documentID ...int64) error
 	}
 	milvusClient struct {
@@ -114,13 +136,19 @@ func (v MilvusConfig) Validate() error {
 	)
 }
 
-func (c *milvusClient) Load(ctx context.Context, collection string, vector []float32) ([]*MilvusPayload, error) {
+// Load searches for entities similar to the vector in the collection.
+// The number of entities, the score threshold and metadata filters are taken from params.
+// Entities are returned with the similarity score.
+func (c *milvusClient) Load(ctx context.Context, collection string, vector []float32, params *SearchParams) ([]*MilvusPayload, error) {
 	if err := c.checkConnection(); err != nil {
 		return nil, err
 	}
+	if params == nil {
+		params = &SearchParams{}
+	}
 	vs := []entity.Vector{entity.FloatVector(vector)}
 	sp, _ := entity.NewIndexFlatSearchParam()
-	result, err := c.client.Search(ctx, collection, []string{}, "", responseColumns, vs, ColumnNameVector, c.MetricType, 10, sp)
+	result, err := c.client.Search(ctx, collection, []string{}, params.Expression(), responseColumns, vs, ColumnNameVector, c.MetricType, params.Limit(), sp)
 	if err != nil {
 		return nil, err
 	}
@@ -131,12 +159,70 @@ func (c *milvusClient) Load(ctx context.Context, collection string, vector []flo
 			if err = pr.FromResult(i, row); err != nil {
 				return nil, err
 			}
+			if i < len(row.Scores) {
+				pr.Score = row.Scores[i]
+			}
+			if !params.IsRelevant(c.MetricType, pr.Score) {
+				continue
+			}
 			payload = append(payload, &pr)
 		}
 	}
 	return payload, nil
 }
 
+// Limit returns top k or DefaultTopK if it is not set.
+func (p *SearchParams) Limit() int {
+	if p == nil || p.TopK <= 0 {
+		return DefaultTopK
+	}
+	return p.TopK
+}
+
+// Expression builds the boolean expression of the metadata filters.
+func (p *SearchParams) Expression() string {
+	if p == nil {
+		return ""
+	}
+	var expressions []string
+	for _, filter := range p.Filters {
+		if filter.Field == "" {
+			continue
+		}
+		values := make([]string, 0, len(filter.Values))
+		for _, value := range filter.Values {
+			values = append(values, strconv.FormatInt(value, 10))
+		}
+		expressions = append(expressions, fmt.Sprintf("%s in [%s]", filter.Field, strings.Join(values, ",")))
+	}
+	return strings.Join(expressions, " and ")
+}
+
+// FilterValues returns values of the filter on the field, false if there is no such filter.
+func (p *SearchParams) FilterValues(field string) ([]int64, bool) {
+	if p == nil {
+		return nil, false
+	}
+	for _, filter := range p.Filters {
+		if filter.Field == field {
+			return filter.Values, true
+		}
+	}
+	return nil, false
+}
+
+// IsRelevant checks the score against the threshold.
+// For L2 metric the score is a distance, so smaller score means more similar entity.
+func (p *SearchParams) IsRelevant(metricType entity.MetricType, score float32) bool {
+	if p == nil || p.ScoreThreshold <= 0 {
+		return true
+	}
+	if metricType == entity.L2 {
+		return score <= p.ScoreThreshold
+	}
+	return score >= p.ScoreThreshold
+}
+
 // MilvusModule is a variable of type fx.Option. It provides dependencies for Milvus configuration
 // and client initialization.
 //
@@ -175,7 +261,7 @@ func NewMilvusClient(cfg *MilvusConfig) (VectorDBClient, error) {
 
 // checks if the connection to Milvus is ready
 func (c *milvusClient) Save(ctx context.Context, collection string, payloads ...*MilvusPayload) error {
-	var ids, documentIDs, chunks []int64
+	var ids, documentIDs, connectorIDs, chunks []int64
 	var contents [][]byte
 	var vectors [][]float32
 	if err := c.checkConnection(); err != nil {
@@ -184,6 +270,7 @@ func (c *milvusClient) Save(ctx context.Context, collection string, payloads ...
 	for _, payload := range payloads {
 		ids = append(ids, payload.ID)
 		documentIDs = append(documentIDs, payload.DocumentID)
+		connectorIDs = append(connectorIDs, payload.ConnectorID)
 		chunks = append(chunks, payload.Chunk)
 		contents = append(contents, []byte(fmt.Sprintf(`{"content":"%s"}`, payload.Content)))
 		vectors = append(vectors, payload.Vector)
@@ -191,6 +278,7 @@ func (c *milvusClient) Save(ctx context.Context, collection string, payloads ...
 	if _, err := c.client.Insert(ctx, collection, "",
 		entity.NewColumnInt64(ColumnNameID, ids),
 		entity.NewColumnInt64(ColumnNameDocumentID, documentIDs),
+		entity.NewColumnInt64(ColumnNameConnectorID, connectorIDs),
 		entity.NewColumnJSONBytes(ColumnNameContent, contents),
 		entity.NewColumnFloatVector(ColumnNameVector, VectorDimension, vectors),
 	); err != nil {
@@ -229,6 +317,7 @@ func (c *milvusClient) CreateSchema(ctx context.Context, name string) error {
 	schema := entity.NewSchema().WithName(name).
 		WithField(entity.NewField().WithName(ColumnNameID).WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
 		WithField(entity.NewField().WithName(ColumnNameDocumentID).WithDataType(entity.FieldTypeInt64)).
+		WithField(entity.NewField().WithName(ColumnNameConnectorID).WithDataType(entity.FieldTypeInt64)).
 		WithField(entity.NewField().WithName(ColumnNameContent).WithDataType(entity.FieldTypeJSON)).
 		WithField(entity.NewField().WithName(ColumnNameVector).WithDataType(entity.FieldTypeFloatVector).WithDim(1536))
 	if err = c.client.CreateCollection(ctx, schema, 2, milvus.WithAutoID(true)); err != nil {
@@ -33,6 +33,7 @@ type Errors struct {
 //	    Internal          ErrorWrap = 500
 //	    ErrorBadRequest   ErrorWrap = 400
 //	    ErrorUnauthorized ErrorWrap = 401
+//	    ErrorConflict     ErrorWrap = 409
 //	)
 //
 //	func (e ErrorWrap) Wrap(eo error, msg string) Errors {
@@ -74,12 +75,14 @@ type ErrorWrap int
 // Internal represents an internal server error (500 Internal Server Error).
 // ErrorBadRequest represents a bad request error (400 Bad Request).
 // ErrorUnauthorized represents an unauthorized error (401 Unauthorized).
+// ErrorConflict represents a conflict with the current state of the resource (409 Conflict).
 const (
 	ErrorPermission   ErrorWrap = http.StatusForbidden
 	NotFound          ErrorWrap = http.StatusNotFound
 	Internal          ErrorWrap = http.StatusInternalServerError
 	ErrorBadRequest   ErrorWrap = http.StatusBadRequest
 	ErrorUnauthorized ErrorWrap = http.StatusUnauthorized
+	ErrorConflict     ErrorWrap = http.StatusConflict
 )
 
 // Error returns the error message associated with the Errors object.
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS document_chunks (
+    id SERIAL PRIMARY KEY,
+    document_id bigint NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    collection_name varchar NOT NULL,
+    content text NOT NULL,
+    content_tsv tsvector GENERATED ALWAYS AS (to_tsvector('simple', content)) STORED,
+    creation_date timestamp WITHOUT TIME ZONE NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS document_chunks_document_id_idx ON document_chunks (document_id);
+CREATE INDEX IF NOT EXISTS document_chunks_content_tsv_idx ON document_chunks USING GIN (content_tsv);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS document_chunks;
+-- +goose StatementEnd
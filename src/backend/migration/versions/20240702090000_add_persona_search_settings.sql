@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE personas ADD COLUMN IF NOT EXISTS search_top_k integer not null default 0;
+ALTER TABLE personas ADD COLUMN IF NOT EXISTS search_score_threshold real not null default 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE personas DROP COLUMN IF EXISTS search_top_k;
+ALTER TABLE personas DROP COLUMN IF EXISTS search_score_threshold;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE llms ADD COLUMN IF NOT EXISTS context_size integer not null default 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE llms DROP COLUMN IF EXISTS context_size;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE chat_message_document_pairs ADD COLUMN IF NOT EXISTS citation_number integer not null default 0;
+ALTER TABLE chat_message_document_pairs ADD COLUMN IF NOT EXISTS cited boolean not null default false;
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS invalid_citations boolean not null default false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE chat_message_document_pairs DROP COLUMN IF EXISTS citation_number;
+ALTER TABLE chat_message_document_pairs DROP COLUMN IF EXISTS cited;
+ALTER TABLE chat_messages DROP COLUMN IF EXISTS invalid_citations;
+-- +goose StatementEnd
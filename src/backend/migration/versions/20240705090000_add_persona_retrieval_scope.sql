@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE personas ADD COLUMN IF NOT EXISTS connector_ids bigint[];
+ALTER TABLE personas ADD COLUMN IF NOT EXISTS source_types varchar[];
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE personas DROP COLUMN IF EXISTS connector_ids;
+ALTER TABLE personas DROP COLUMN IF EXISTS source_types;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE llms ADD COLUMN IF NOT EXISTS temperature real;
+ALTER TABLE llms ADD COLUMN IF NOT EXISTS top_p real;
+ALTER TABLE llms ADD COLUMN IF NOT EXISTS max_tokens integer not null default 0;
+ALTER TABLE llms ADD COLUMN IF NOT EXISTS stop varchar[];
+ALTER TABLE llms ADD COLUMN IF NOT EXISTS response_format varchar not null default '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE llms DROP COLUMN IF EXISTS temperature;
+ALTER TABLE llms DROP COLUMN IF EXISTS top_p;
+ALTER TABLE llms DROP COLUMN IF EXISTS max_tokens;
+ALTER TABLE llms DROP COLUMN IF EXISTS stop;
+ALTER TABLE llms DROP COLUMN IF EXISTS response_format;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS connector_runs (
+    id SERIAL PRIMARY KEY,
+    connector_id bigint NOT NULL REFERENCES connectors(id) ON DELETE CASCADE,
+    session_id uuid NOT NULL,
+    status varchar NOT NULL,
+    attempts integer NOT NULL DEFAULT 0,
+    docs_added integer NOT NULL DEFAULT 0,
+    docs_updated integer NOT NULL DEFAULT 0,
+    docs_deleted integer NOT NULL DEFAULT 0,
+    docs_skipped integer NOT NULL DEFAULT 0,
+    docs_failed integer NOT NULL DEFAULT 0,
+    bytes_downloaded bigint NOT NULL DEFAULT 0,
+    error text NOT NULL DEFAULT '',
+    document_errors jsonb NOT NULL DEFAULT '[]',
+    creation_date timestamp WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+    start_date timestamp WITHOUT TIME ZONE,
+    end_date timestamp WITHOUT TIME ZONE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS connector_runs_session_id_idx ON connector_runs (session_id);
+CREATE INDEX IF NOT EXISTS connector_runs_connector_id_idx ON connector_runs (connector_id, creation_date DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS connector_runs;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE connectors ADD COLUMN IF NOT EXISTS lease_owner varchar;
+ALTER TABLE connectors ADD COLUMN IF NOT EXISTS lease_expiry timestamp WITHOUT TIME ZONE;
+CREATE INDEX IF NOT EXISTS connectors_lease_expiry_idx ON connectors (lease_expiry);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS connectors_lease_expiry_idx;
+ALTER TABLE connectors DROP COLUMN IF EXISTS lease_expiry;
+ALTER TABLE connectors DROP COLUMN IF EXISTS lease_owner;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE connectors ADD COLUMN IF NOT EXISTS schedule jsonb;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE connectors DROP COLUMN IF EXISTS schedule;
+-- +goose StatementEnd
@@ -3,10 +3,13 @@ package mocks
 import (
 	"cognix.ch/api/v2/core/model"
 	"cognix.ch/api/v2/core/repository"
+	"cognix.ch/api/v2/core/utils"
 	"context"
+	"github.com/go-pg/pg/v10"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
+	"sync"
 	"time"
 )
 
@@ -102,18 +105,23 @@ type MockConnectorRepo struct {
 	workCh            chan int
 	expectedIteration int
 	iteration         int
+	connectors        map[int64]*model.Connector
+	mx                sync.Mutex
+	released          map[int64]string
 }
 
-func (m *MockConnectorRepo) GetActive(ctx context.Context) ([]*model.Connector, error) {
+func (m *MockConnectorRepo) ClaimActive(ctx context.Context, owner string, lease time.Duration) ([]*model.Connector, error) {
 	result := make([]*model.Connector, 0)
 	zap.S().Errorf("load iteration %d", m.iteration)
 	if m.iteration >= m.expectedIteration {
-		close(m.workCh)
+		if m.workCh != nil {
+			close(m.workCh)
+		}
 		return result, nil
 	}
 	m.iteration++
 	zap.S().Info("connector for running ")
-	for _, conn := range MockedConnectors {
+	for _, conn := range m.connectors {
 		if conn.Status == model.ConnectorStatusReadyToProcessed ||
 			conn.Status == model.ConnectorStatusSuccess ||
 			conn.Status == model.ConnectorStatusError {
@@ -131,13 +139,15 @@ func (m *MockConnectorRepo) GetAllByUser(ctx context.Context, tenantID, userID u
 }
 
 func (m *MockConnectorRepo) GetByIDAndUser(ctx context.Context, tenantID, userID uuid.UUID, id int64) (*model.Connector, error) {
-	//TODO implement me
-	panic("implement me")
+	return m.GetByID(ctx, id)
 }
 
 func (m *MockConnectorRepo) GetByID(ctx context.Context, id int64) (*model.Connector, error) {
-	//TODO implement me
-	panic("implement me")
+	connector, ok := m.connectors[id]
+	if !ok {
+		return nil, utils.NotFound.New("can not load connector")
+	}
+	return connector, nil
 }
 
 func (m *MockConnectorRepo) GetBySource(ctx context.Context, tenantID, userID uuid.UUID, source model.SourceType) (*model.Connector, error) {
@@ -151,13 +161,50 @@ func (m *MockConnectorRepo) Create(ctx context.Context, connector *model.Connect
 }
 
 func (m *MockConnectorRepo) Update(ctx context.Context, connector *model.Connector) error {
-	MockedConnectors[connector.ID.IntPart()] = connector
+	m.connectors[connector.ID.IntPart()] = connector
+	return nil
+}
+
+func (m *MockConnectorRepo) ReleaseLease(ctx context.Context, owner string, ids ...int64) error {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	for _, id := range ids {
+		m.released[id] = m.connectors[id].Status
+	}
 	return nil
 }
 
+// Released returns the status of the connector when its lease was released, false if the lease was not released.
+func (m *MockConnectorRepo) Released(id int64) (string, bool) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	status, ok := m.released[id]
+	return status, ok
+}
+
+func (m *MockConnectorRepo) SetPending(ctx context.Context, connector *model.Connector) (bool, error) {
+	if connector.Status == model.ConnectorStatusPending || connector.Status == model.ConnectorStatusWorking {
+		return false, nil
+	}
+	connector.Status = model.ConnectorStatusPending
+	connector.LastUpdate = pg.NullTime{time.Now().UTC()}
+	return true, m.Update(ctx, connector)
+}
+
 func NewMockConnectorRepo(iteration int, workCh chan int) repository.ConnectorRepository {
 	return &MockConnectorRepo{
 		workCh:            workCh,
 		expectedIteration: iteration,
+		connectors:        MockedConnectors,
+		released:          make(map[int64]string),
+	}
+}
+
+// NewMockConnectorRepoWithConnectors creates the repository of the given connectors, they are claimed once.
+func NewMockConnectorRepoWithConnectors(connectors map[int64]*model.Connector) *MockConnectorRepo {
+	return &MockConnectorRepo{
+		expectedIteration: 1,
+		connectors:        connectors,
+		released:          make(map[int64]string),
 	}
 }
@@ -0,0 +1,60 @@
+package mocks
+
+import (
+	"cognix.ch/api/v2/core/model"
+	"cognix.ch/api/v2/core/parameters"
+	"cognix.ch/api/v2/core/repository"
+	"context"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"sync"
+)
+
+type MockConnectorRunRepository struct {
+	mx   sync.Mutex
+	runs []*model.ConnectorRun
+}
+
+func (m *MockConnectorRunRepository) GetByConnectorID(ctx context.Context, connectorID int64, param *parameters.ConnectorRunListParam) ([]*model.ConnectorRun, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockConnectorRunRepository) GetByID(ctx context.Context, connectorID, id int64) (*model.ConnectorRun, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockConnectorRunRepository) GetBySessionID(ctx context.Context, sessionID uuid.UUID) (*model.ConnectorRun, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	for _, run := range m.runs {
+		if run.SessionID == sessionID {
+			return run, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockConnectorRunRepository) Create(ctx context.Context, run *model.ConnectorRun) error {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	m.runs = append(m.runs, run)
+	run.ID = decimal.NewFromInt(int64(len(m.runs)))
+	return nil
+}
+
+func (m *MockConnectorRunRepository) Update(ctx context.Context, run *model.ConnectorRun) error {
+	return nil
+}
+
+// Runs returns the created runs.
+func (m *MockConnectorRunRepository) Runs() []*model.ConnectorRun {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	return m.runs
+}
+
+func NewMockConnectorRunRepo() repository.ConnectorRunRepository {
+	return &MockConnectorRunRepository{}
+}
@@ -4,6 +4,7 @@ import (
 	"cognix.ch/api/v2/core/model"
 	"cognix.ch/api/v2/core/repository"
 	"context"
+	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
 
@@ -24,6 +25,16 @@ func (m MockDocumentRepository) FindByID(ctx context.Context, id int64) (*model.
 	panic("implement me")
 }
 
+func (m MockDocumentRepository) FindBySourceID(ctx context.Context, connectorID int64, sourceID string) (*model.Document, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m MockDocumentRepository) FindConnectorIDsByScope(ctx context.Context, tenantID uuid.UUID, connectorIDs []int64, sourceTypes []string) ([]int64, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (m MockDocumentRepository) Create(ctx context.Context, document *model.Document) error {
 	document.ID = decimal.NewFromInt(1)
 	return nil
@@ -8,18 +8,25 @@ import (
 	"github.com/go-pg/pg/v10"
 	proto2 "github.com/golang/protobuf/proto"
 	"go.uber.org/zap"
+	"sync"
 	"time"
 )
 
 type MockMessenger struct {
-	workCh chan int
+	workCh    chan int
+	mx        *sync.Mutex
+	published map[string][]int64
 }
 
 func (m MockMessenger) Publish(ctx context.Context, streamName, topic string, body proto2.Message) error {
 
 	if streamName == "semantic" {
 		semantic := body.(*proto.SemanticData)
-		conn := MockedConnectors[semantic.ConnectorId]
+		m.record(streamName, semantic.ConnectorId)
+		conn, ok := MockedConnectors[semantic.ConnectorId]
+		if !ok {
+			return nil
+		}
 		zap.S().Infof("befor sending to semantic .... ")
 		zap.S().Infof("| %s \t\t| %s \t\t | %s \t\t | %v |",
 			conn.Name, conn.Type, conn.Status, conn.LastUpdate)
@@ -31,9 +38,17 @@ func (m MockMessenger) Publish(ctx context.Context, streamName, topic string, bo
 			conn.Name, conn.Type, conn.Status, conn.LastUpdate)
 		return nil
 	}
+	if streamName == "voice" {
+		m.record(streamName, body.(*proto.VoiceData).ConnectorId)
+		return nil
+	}
 	if streamName == "connector" {
 		connRequest := body.(*proto.ConnectorRequest)
-		conn := MockedConnectors[connRequest.Id]
+		m.record(streamName, connRequest.Id)
+		conn, ok := MockedConnectors[connRequest.Id]
+		if !ok {
+			return nil
+		}
 		zap.S().Infof("befor sending to connector  .... ")
 		zap.S().Infof("| %s \t\t| %s \t\t | %s \t\t | %v |",
 			conn.Name, conn.Type, conn.Status, conn.LastUpdate)
@@ -48,6 +63,23 @@ func (m MockMessenger) Publish(ctx context.Context, streamName, topic string, bo
 	return nil
 }
 
+func (m MockMessenger) record(streamName string, connectorID int64) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	m.published[streamName] = append(m.published[streamName], connectorID)
+}
+
+// Published returns ids of connectors whose messages were published to the stream.
+func (m MockMessenger) Published(streamName string) []int64 {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	return m.published[streamName]
+}
+
+func (m MockMessenger) IsOnline() bool {
+	return true
+}
+
 func (m MockMessenger) Listen(ctx context.Context, streamName, topic string, handler messaging.MessageHandler) error {
 
 	return nil
@@ -57,9 +89,29 @@ func (m MockMessenger) StreamConfig() *messaging.StreamConfig {
 	return &messaging.StreamConfig{
 		ConnectorStreamName: "connector",
 		SemanticStreamName:  "semantic",
+		VoiceStreamName:     "voice",
 	}
 }
 
+func (m MockMessenger) GetDeadLetters(ctx context.Context, streamName string, fromSequence uint64, limit int) ([]*messaging.DeadLetter, error) {
+	return []*messaging.DeadLetter{}, nil
+}
+
+func (m MockMessenger) GetDeadLetter(ctx context.Context, sequence uint64) (*messaging.DeadLetter, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m MockMessenger) ReplayDeadLetter(ctx context.Context, sequence uint64) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m MockMessenger) DeleteDeadLetter(ctx context.Context, sequence uint64) error {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (m MockMessenger) Close() {
 	//TODO implement me
 	panic("implement me")
@@ -67,6 +119,8 @@ func (m MockMessenger) Close() {
 
 func NewMockMessenger(workCh chan int) messaging.Client {
 	return &MockMessenger{
-		workCh: workCh,
+		workCh:    workCh,
+		mx:        &sync.Mutex{},
+		published: make(map[string][]int64),
 	}
 }
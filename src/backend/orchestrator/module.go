@@ -5,13 +5,20 @@ import (
 	"cognix.ch/api/v2/core/repository"
 	"cognix.ch/api/v2/core/utils"
 	"context"
+	"github.com/google/uuid"
 	"go.uber.org/fx"
+	"os"
 )
 
+// Config contains the settings of the orchestrator.
+// InstanceID identifies the replica that leases connectors, the host name is used if it is not set.
+// LeaseDuration is the time in seconds after which connectors leased by a crashed replica are claimed again.
 type Config struct {
 	OAuthURL      string `env:"OAUTH_URL,required"`
 	RenewInterval int    `env:"ORCHESTRATOR_RENEW_INTERVAL" envDefault:"30"`
 	FileSizeLimit int    `env:"FILE_SIZE_LIMIT,required"`
+	InstanceID    string `env:"ORCHESTRATOR_INSTANCE_ID"`
+	LeaseDuration int    `env:"ORCHESTRATOR_LEASE_DURATION" envDefault:"300"`
 }
 
 var Module = fx.Options(
@@ -20,11 +27,22 @@ var Module = fx.Options(
 	fx.Provide(
 		func() (*Config, error) {
 			cfg := Config{}
-			err := utils.ReadConfig(&cfg)
-			return &cfg, err
+			if err := utils.ReadConfig(&cfg); err != nil {
+				return nil, err
+			}
+			if cfg.InstanceID == "" {
+				// the host name is the pod name in kubernetes
+				hostname, err := os.Hostname()
+				if err != nil {
+					hostname = uuid.NewString()
+				}
+				cfg.InstanceID = hostname
+			}
+			return &cfg, nil
 		},
 		repository.NewConnectorRepository,
 		repository.NewDocumentRepository,
+		repository.NewConnectorRunRepository,
 		NewServer,
 	),
 	fx.Invoke(RunServer),
@@ -12,8 +12,10 @@ import (
 // Server represents a server that handles various tasks related to connectors and documents.
 type Server struct {
 	renewInterval time.Duration
+	leaseDuration time.Duration
 	connectorRepo repository.ConnectorRepository
 	docRepo       repository.DocumentRepository
+	runRepo       repository.ConnectorRunRepository
 	messenger     messaging.Client
 	scheduler     gocron.Scheduler
 	streamCfg     *messaging.StreamConfig
@@ -21,13 +23,14 @@ type Server struct {
 }
 
 // NewServer creates a new instance of Server.
-// It takes a pointer to Config, ConnectorRepository, DocumentRepository,
+// It takes a pointer to Config, ConnectorRepository, DocumentRepository, ConnectorRunRepository,
 // messaging.Client, and messaging.Config as input parameters.
 // It returns a pointer to Server and an error.
 func NewServer(
 	cfg *Config,
 	connectorRepo repository.ConnectorRepository,
 	docRepo repository.DocumentRepository,
+	runRepo repository.ConnectorRunRepository,
 	messenger messaging.Client,
 	messagingCfg *messaging.Config) (*Server, error) {
 	s, err := gocron.NewScheduler()
@@ -37,7 +40,9 @@ func NewServer(
 
 	return &Server{connectorRepo: connectorRepo,
 		docRepo:       docRepo,
+		runRepo:       runRepo,
 		renewInterval: time.Duration(cfg.RenewInterval) * time.Second,
+		leaseDuration: time.Duration(cfg.LeaseDuration) * time.Second,
 		cfg:           cfg,
 		messenger:     messenger,
 		streamCfg:     messagingCfg.Stream,
@@ -58,11 +63,12 @@ func (s *Server) run(ctx context.Context) error {
 	return nil
 }
 
-// loadFromDatabase loads connectors from the database and triggers the execution for each connector.
+// loadFromDatabase claims connectors in the database and triggers the execution for each connector.
 // If the messenger is offline, no action is taken and the method returns nil.
-// If an error occurs while getting active connectors from the database,
+// If an error occurs while claiming active connectors in the database,
 // the method logs the error and returns the error.
-// For each connector, it creates a new trigger and executes the Do method.
+// Connectors are leased to this instance while they are triggered, other instances skip them.
+// For each connector, it creates a new trigger and executes the Do method, then releases the lease.
 // If an error occurs during the execution of the trigger, it is logged.
 // The method returns nil if all operations are successful. If any error occurs,
 // it is returned as the result of the method.
@@ -73,15 +79,18 @@ func (s *Server) loadFromDatabase() error {
 		return nil
 	}
 	zap.S().Infof("Loading connectors from db")
-	connectors, err := s.connectorRepo.GetActive(ctx)
+	connectors, err := s.connectorRepo.ClaimActive(ctx, s.cfg.InstanceID, s.leaseDuration)
 	if err != nil {
 		zap.S().Errorf("Load connectors failed: %v", err)
 		return err
 	}
 	for _, connector := range connectors {
-		if err = NewTrigger(s.messenger, s.connectorRepo, s.docRepo, connector, s.cfg.FileSizeLimit, s.cfg.OAuthURL).Do(ctx); err != nil {
+		if err = NewTrigger(s.messenger, s.connectorRepo, s.docRepo, s.runRepo, connector, s.cfg.FileSizeLimit, s.cfg.OAuthURL).Do(ctx); err != nil {
 			zap.S().Errorf("run connector %d failed: %v", connector.ID, err)
 		}
+		if err = s.connectorRepo.ReleaseLease(ctx, s.cfg.InstanceID, connector.ID.IntPart()); err != nil {
+			zap.S().Errorf("release connector %d failed: %v", connector.ID, err)
+		}
 	}
 	return nil
 }
@@ -2,12 +2,16 @@ package main
 
 import (
 	"cognix.ch/api/v2/core/messaging"
+	"cognix.ch/api/v2/core/model"
 	"cognix.ch/api/v2/core/utils"
 	"cognix.ch/api/v2/orchestrator/mocks"
 	"context"
+	"github.com/go-pg/pg/v10"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 	"testing"
+	"time"
 )
 
 func TestOrchestrator_Scheduler(t *testing.T) {
@@ -25,6 +29,7 @@ func TestOrchestrator_Scheduler(t *testing.T) {
 		},
 		mocks.NewMockConnectorRepo(10, workCh),
 		mocks.NewMockDocumentRepo(),
+		mocks.NewMockConnectorRunRepo(),
 		mocks.NewMockMessenger(workCh),
 		&messaging.Config{
 			Stream: &messaging.StreamConfig{
@@ -46,3 +51,83 @@ func TestOrchestrator_Scheduler(t *testing.T) {
 	}
 	//t.Log(b.String())
 }
+
+func TestServer_LoadFromDatabase(t *testing.T) {
+	utils.InitLogger(true)
+	embeddingUser := &model.User{
+		EmbeddingModel: &model.EmbeddingModel{ModelDim: 3},
+	}
+	connectorRepo := mocks.NewMockConnectorRepoWithConnectors(map[int64]*model.Connector{
+		1: {
+			ID:                      decimal.NewFromInt(1),
+			Name:                    "web connector ready to process",
+			Type:                    model.SourceTypeWEB,
+			ConnectorSpecificConfig: model.JSONMap{"url": "http://testurl"},
+			RefreshFreq:             60,
+			Status:                  model.ConnectorStatusReadyToProcessed,
+			User:                    embeddingUser,
+		},
+		2: {
+			ID:                      decimal.NewFromInt(2),
+			Name:                    "web connector without embedding model",
+			Type:                    model.SourceTypeWEB,
+			ConnectorSpecificConfig: model.JSONMap{"url": "http://testurl"},
+			RefreshFreq:             60,
+			Status:                  model.ConnectorStatusReadyToProcessed,
+			User:                    &model.User{},
+		},
+		3: {
+			ID:                      decimal.NewFromInt(3),
+			Name:                    "web connector updated recently",
+			Type:                    model.SourceTypeWEB,
+			ConnectorSpecificConfig: model.JSONMap{"url": "http://testurl"},
+			RefreshFreq:             3600,
+			Status:                  model.ConnectorStatusSuccess,
+			LastUpdate:              pg.NullTime{time.Now().UTC()},
+			User:                    embeddingUser,
+		},
+		4: {
+			ID:          decimal.NewFromInt(4),
+			Name:        "web connector disabled",
+			Type:        model.SourceTypeWEB,
+			RefreshFreq: 60,
+			Status:      model.ConnectorStatusDisabled,
+			User:        embeddingUser,
+		},
+	})
+	messenger := mocks.NewMockMessenger(nil).(*mocks.MockMessenger)
+
+	srv, err := NewServer(
+		&Config{
+			FileSizeLimit: 1,
+			InstanceID:    "orchestrator-1",
+			LeaseDuration: 60,
+		},
+		connectorRepo,
+		mocks.NewMockDocumentRepo(),
+		mocks.NewMockConnectorRunRepo(),
+		messenger,
+		&messaging.Config{Stream: messenger.StreamConfig()},
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, srv.loadFromDatabase())
+	// connectors are claimed once, the next reload does not trigger them again
+	assert.NoError(t, srv.loadFromDatabase())
+
+	assert.Equal(t, []int64{1}, messenger.Published("connector"))
+	assert.Empty(t, messenger.Published("semantic"))
+
+	// the lease is released after the connector was triggered
+	status, ok := connectorRepo.Released(1)
+	assert.True(t, ok)
+	assert.Equal(t, model.ConnectorStatusPending, status)
+	// the lease is released when the trigger fails
+	_, ok = connectorRepo.Released(2)
+	assert.True(t, ok)
+	// the lease is released when the connector is not due
+	_, ok = connectorRepo.Released(3)
+	assert.True(t, ok)
+	// connectors that are not claimed are not released
+	_, ok = connectorRepo.Released(4)
+	assert.False(t, ok)
+}
@@ -25,43 +25,50 @@ const (
 )
 
 // trigger represents a type that performs various actions based on the Connector model.
-// It requires a messaging client, ConnectorRepository, DocumentRepository, Connector model,
-// file size limit, and OAuth URL to function properly.
+// It requires a messaging client, ConnectorRepository, DocumentRepository, ConnectorRunRepository,
+// Connector model, file size limit, and OAuth URL to function properly.
+// The run of the session is recorded when a task is sent.
 type (
 	trigger struct {
 		messenger      messaging.Client
 		connectorRepo  repository.ConnectorRepository
 		docRepo        repository.DocumentRepository
+		runRepo        repository.ConnectorRunRepository
 		tracer         trace.Tracer
 		connectorModel *model.Connector
 		fileSizeLimit  int
 		oauthURL       string
+		sessionID      uuid.UUID
+		run            *model.ConnectorRun
 	}
 )
 
 // Do triggers the execution of a connector.
-// It checks if the connector is new or needs to be updated based on the last update and refresh frequency.
+// It checks if the connector is new or needs to be updated based on the last update and its schedule,
+// which is the refresh frequency or a cron expression with optional time windows and jitter.
 // If needed, it prepares the task for the connector and publishes it to the appropriate stream.
 // It returns an error if any operation fails.
 func (t *trigger) Do(ctx context.Context) error {
-	// if connector is new or
-	// todo we need figure out how to use multiple  orchestrators instances
-	// one approach could be that this method will extract top x rows from the database
-	// and it will book them
-
+	// the connector is leased to this orchestrator instance, see ConnectorRepository.ClaimActive
 	if t.connectorModel.User == nil || t.connectorModel.User.EmbeddingModel == nil {
 		return fmt.Errorf("embedding model is not configured for %s", t.connectorModel.Name)
 	}
-	zap.S().Debugf("\n------------  %s\nlast %v refresh Freq %d \nnext %v\nnow  %v\nlast+refreshFreq > now %v\n------------- ",
+	now := time.Now().UTC()
+	due, err := t.connectorModel.IsDue(now)
+	if err != nil {
+		return fmt.Errorf("invalid schedule of %s: %w", t.connectorModel.Name, err)
+	}
+	next, _ := t.connectorModel.NextRun()
+	zap.S().Debugf("\n------------  %s\nlast %v refresh Freq %d schedule %+v\nnext %v\nnow  %v\ndue %v\n------------- ",
 		t.connectorModel.Name,
 		t.connectorModel.LastUpdate.UTC(),
 		t.connectorModel.RefreshFreq,
-		t.connectorModel.LastUpdate.UTC().Add(time.Duration(t.connectorModel.RefreshFreq)*time.Second),
-		time.Now().UTC(),
-		t.connectorModel.LastUpdate.UTC().Add(time.Duration(t.connectorModel.RefreshFreq)*time.Second).Before(time.Now().UTC()))
+		t.connectorModel.Schedule,
+		next,
+		now,
+		due)
 
-	if t.connectorModel.LastUpdate.IsZero() ||
-		t.connectorModel.LastUpdate.UTC().Add(time.Duration(t.connectorModel.RefreshFreq)*time.Second).Before(time.Now().UTC()) {
+	if due {
 		ctx, span := t.tracer.Start(ctx, ConnectorSchedulerSpan)
 		defer span.End()
 		span.SetAttributes(attribute.Int64(model.SpanAttributeConnectorID, t.connectorModel.ID.IntPart()))
@@ -75,13 +82,16 @@ func (t *trigger) Do(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
-		sessionID := uuid.New()
-		if err = connWF.PrepareTask(ctx, sessionID, t); err != nil {
+		t.sessionID = uuid.New()
+		if err = connWF.PrepareTask(ctx, t.sessionID, t); err != nil {
 			span.RecordError(err)
 			zap.S().Errorf("failed to prepare task for connector %s[%d]: %v", t.connectorModel.Name, t.connectorModel.ID.IntPart(), err)
 			if errr := t.updateStatus(ctx, model.ConnectorStatusUnableProcess); errr != nil {
 				span.RecordError(errr)
 			}
+			if errr := t.finishRun(ctx, err); errr != nil {
+				span.RecordError(errr)
+			}
 			return err
 		}
 	}
@@ -95,9 +105,13 @@ func (t *trigger) RunSemantic(ctx context.Context, data *proto.SemanticData) err
 		t.connectorModel.Type == model.SourceTypeYoutube ||
 		t.connectorModel.Type == model.SourceTypeFile {
 		doc := t.connectorModel.Docs[0]
+		if err := t.startRun(ctx); err != nil {
+			return err
+		}
 		var err error
 		// create or update document in database
-		if doc.ID.IntPart() != 0 {
+		isNew := doc.ID.IntPart() == 0
+		if !isNew {
 			err = t.docRepo.Update(ctx, doc)
 		} else {
 			err = t.docRepo.Create(ctx, doc)
@@ -106,6 +120,11 @@ func (t *trigger) RunSemantic(ctx context.Context, data *proto.SemanticData) err
 			zap.S().Errorf("update document failed %v", err)
 			return err
 		}
+		if isNew {
+			t.run.DocsAdded++
+		} else {
+			t.run.DocsUpdated++
+		}
 		data.DocumentId = doc.ID.IntPart()
 	}
 	if err := t.updateStatus(ctx, model.ConnectorStatusPending); err != nil {
@@ -114,23 +133,37 @@ func (t *trigger) RunSemantic(ctx context.Context, data *proto.SemanticData) err
 	zap.S().Infof("send message to semantic %s", t.connectorModel.Name)
 	buf, _ := json.Marshal(data)
 	zap.S().Debugf(" message payload %s", string(buf))
-	return t.messenger.Publish(ctx, t.messenger.StreamConfig().SemanticStreamName,
-		t.messenger.StreamConfig().SemanticStreamSubject, data)
+	if err := t.messenger.Publish(ctx, t.messenger.StreamConfig().SemanticStreamName,
+		t.messenger.StreamConfig().SemanticStreamSubject, data); err != nil {
+		return err
+	}
+	// the document is sent to the semantic service directly, the run is completed
+	return t.finishRun(ctx, nil)
 }
 
 // RunConnector sends a message to the connector and publishes it to the connector stream.
 //
 // It updates the Connector's Params with the file limit and sets the Connector's status to "PENDING".
+// The message is not sent if a run of the connector is already pending or processing, like a sync started by a user.
 // Then, it logs an info message with the name of the connector.
 // Finally, it uses the messaging client to publish the ConnectorRequest to the ConnectorStream.
 //
 // If there is an error updating the status or publishing the message, it returns the error.
 func (t *trigger) RunConnector(ctx context.Context, data *proto.ConnectorRequest) error {
 	data.Params[model.ParamFileLimit] = fmt.Sprintf("%d", t.fileSizeLimit)
-	if err := t.updateStatus(ctx, model.ConnectorStatusPending); err != nil {
+	ok, err := t.connectorRepo.SetPending(ctx, t.connectorModel)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		zap.S().Infof("connector %s is already running", t.connectorModel.Name)
+		return nil
+	}
+	if err = t.startRun(ctx); err != nil {
 		return err
 	}
 	zap.S().Infof("send message to connector %s", t.connectorModel.Name)
+	// the run is completed by the connector service
 	return t.messenger.Publish(ctx, t.messenger.StreamConfig().ConnectorStreamName,
 		t.messenger.StreamConfig().ConnectorStreamSubject, data)
 }
@@ -147,6 +180,7 @@ func (t *trigger) UpToDate(ctx context.Context) error {
 func NewTrigger(messenger messaging.Client,
 	connectorRepo repository.ConnectorRepository,
 	docRepo repository.DocumentRepository,
+	runRepo repository.ConnectorRunRepository,
 	connectorModel *model.Connector,
 	fileSizeLimit int,
 	oauthURL string) *trigger {
@@ -154,6 +188,7 @@ func NewTrigger(messenger messaging.Client,
 		messenger:      messenger,
 		connectorRepo:  connectorRepo,
 		docRepo:        docRepo,
+		runRepo:        runRepo,
 		connectorModel: connectorModel,
 		fileSizeLimit:  fileSizeLimit,
 		oauthURL:       oauthURL,
@@ -169,3 +204,29 @@ func (t *trigger) updateStatus(ctx context.Context, status string) error {
 	t.connectorModel.LastUpdate = pg.NullTime{time.Now().UTC()}
 	return t.connectorRepo.Update(ctx, t.connectorModel)
 }
+
+// startRun records the pending run of the session before the task is sent.
+func (t *trigger) startRun(ctx context.Context) error {
+	if t.run != nil {
+		return nil
+	}
+	t.run = &model.ConnectorRun{
+		ConnectorID:    t.connectorModel.ID,
+		SessionID:      t.sessionID,
+		Status:         model.ConnectorStatusPending,
+		DocumentErrors: make([]*model.ConnectorRunError, 0),
+		CreationDate:   time.Now().UTC(),
+	}
+	return t.runRepo.Create(ctx, t.run)
+}
+
+// finishRun completes the run of the session, a run is recorded for a task that failed before it was sent.
+func (t *trigger) finishRun(ctx context.Context, err error) error {
+	if t.run == nil {
+		if errr := t.startRun(ctx); errr != nil {
+			return errr
+		}
+	}
+	t.run.Finish(err)
+	return t.runRepo.Update(ctx, t.run)
+}
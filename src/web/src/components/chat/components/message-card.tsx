@@ -15,6 +15,7 @@ export interface MessageProps {
   timestamp: string;
   className?: string;
   citations?: Document[];
+  invalidCitations?: boolean;
   isResponse?: boolean;
   feedback?: MessageFeedback;
 }
@@ -24,6 +25,7 @@ const MessageCard: React.FC<MessageProps> = ({
   sender,
   message,
   citations,
+  invalidCitations,
   feedback,
   isResponse,
   className,
@@ -69,12 +71,20 @@ const MessageCard: React.FC<MessageProps> = ({
           <div className="-mt-6 text-muted-foreground break-all">{message}</div>
           <div>
             {sender !== "You" && <div className="pt-2 font-bold">Sources:</div>}
+            {isResponse && invalidCitations && (
+              <div className="pt-1 text-sm text-muted">
+                This answer does not cite the retrieved sources.
+              </div>
+            )}
             {citations?.map((citation) => (
               <div
                 key={citation.id}
                 className="inline-flex cursor-pointer items-center m-1 px-2 py-1 space-x-2 bg-main rounded-lg shadow-md"
               >
                 <FileWhiteIcon className="w-4 h-4" />
+                {citation.citation_number ? (
+                  <span className="font-bold">[{citation.citation_number}]</span>
+                ) : null}
                 <span>{citation.link}</span>
               </div>
             ))}
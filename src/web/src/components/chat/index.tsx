@@ -120,6 +120,7 @@ export function ChatComponent() {
       }
 
       let buffer = "";
+      const streamedMessageIds = new Set<string>();
       while (true) {
         const { value, done } = await reader.read();
         if (done) break;
@@ -155,12 +156,45 @@ export function ChatComponent() {
                 }
                 return prev;
               });
+            } else if (eventType === "delta") {
+              setMessages((prev) => {
+                const messageIndex = (prev ?? []).findIndex(
+                  (message) => message.id === parsedData.Message.id
+                );
+                if (messageIndex === -1) {
+                  return [
+                    ...(prev ?? []),
+                    { ...parsedData.Message, message: parsedData.Delta },
+                  ];
+                }
+                const updatedMessages = [...prev];
+                updatedMessages[messageIndex] = {
+                  ...updatedMessages[messageIndex],
+                  message:
+                    updatedMessages[messageIndex].message + parsedData.Delta,
+                };
+                return updatedMessages;
+              });
+              streamedMessageIds.add(parsedData.Message.id);
             } else if (eventType === "message") {
-              setMessages((prev) => [
-                ...(prev ?? []),
-                { ...parsedData.Message, message: "" },
-              ]);
-              setNewMessage(parsedData.Message);
+              if (streamedMessageIds.has(parsedData.Message.id)) {
+                setMessages((prev) =>
+                  prev?.map((message) =>
+                    message.id === parsedData.Message.id
+                      ? parsedData.Message
+                      : message
+                  )
+                );
+                if (!chatId) {
+                  router.navigate(`/chat/${parsedData.Message.chat_session_id}`);
+                }
+              } else {
+                setMessages((prev) => [
+                  ...(prev ?? []),
+                  { ...parsedData.Message, message: "" },
+                ]);
+                setNewMessage(parsedData.Message);
+              }
             } else if (eventType === "error") {
               router.navigate(`/chat/${currentChatId}`);
               toast.error(parsedData.Message.error);
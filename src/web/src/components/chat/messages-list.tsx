@@ -47,6 +47,7 @@ const MessagesList: React.FC<MessagesListProps> = ({
           message={message.message ?? message.error}
           timestamp={message.time_sent}
           citations={message.citations}
+          invalidCitations={message.invalid_citations}
           feedback={message.feedback}
         />
       ))}
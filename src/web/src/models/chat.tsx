@@ -13,12 +13,16 @@ export interface Document {
   document_id: string;
   content: string;
   updated_date: string;
+  score?: number;
+  citation_number?: number;
+  cited?: boolean;
 }
 
 export interface ChatMessage {
   chat_session_id: string;
   citations?: Document[];
   error?: string;
+  invalid_citations?: boolean;
   id: string;
   latest_child_message?: number;
   message: string;
@@ -68,6 +68,11 @@ export interface LLM {
   updated_date?: string;
   deleted_date?: string;
   url?: string;
+  temperature?: number;
+  top_p?: number;
+  max_tokens?: number;
+  stop?: string[];
+  response_format?: string;
 }
 
 export interface Prompt {
@@ -98,4 +103,6 @@ export interface Persona {
   endpoint?: string;
   api_key?: string;
   url?: string;
+  connector_ids?: number[];
+  source_types?: string[];
 }